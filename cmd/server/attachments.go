@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxAttachmentSize     = 32 << 20
+	attachmentChunkSize   = 64 << 10
+	maxAttachmentFilename = 255
+)
+
+func (s *TaskServiceServer) UploadAttachment(stream taskv1.TaskService_UploadAttachmentServer) error {
+	if s.blobs == nil {
+		return status.Error(codes.FailedPrecondition, "attachments are not configured")
+	}
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "metadata is required")
+	}
+	if err != nil {
+		return err
+	}
+	meta := first.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry metadata")
+	}
+	task_id := strings.TrimSpace(meta.GetTaskId())
+	if task_id == "" {
+		return status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	filename := strings.TrimSpace(meta.GetFilename())
+	if filename == "" || len(filename) > maxAttachmentFilename {
+		return status.Error(codes.InvalidArgument, "filename must be 1-255 characters")
+	}
+	if meta.GetSizeBytes() < 0 || meta.GetSizeBytes() > maxAttachmentSize {
+		return status.Errorf(codes.InvalidArgument, "size_bytes must be between 0 and %d", maxAttachmentSize)
+	}
+	want_sum := strings.ToLower(strings.TrimSpace(meta.GetSha256()))
+	if want_sum == "" {
+		return status.Error(codes.InvalidArgument, "sha256 is required")
+	}
+	s.mu.RLock()
+	_, ok := s.taskMap[task_id]
+	s.mu.RUnlock()
+	if !ok {
+		return status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+
+	w, err := s.blobs.Create()
+	if err != nil {
+		return status.Error(codes.Internal, "create blob: "+err.Error())
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			w.Abort()
+		}
+	}()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetMetadata() != nil {
+			return status.Error(codes.InvalidArgument, "metadata may only be sent once")
+		}
+		chunk := req.GetChunk()
+		if w.Size()+int64(len(chunk)) > meta.GetSizeBytes() {
+			return status.Errorf(codes.InvalidArgument, "upload exceeds declared size of %d bytes", meta.GetSizeBytes())
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return status.Error(codes.Internal, "write blob: "+err.Error())
+		}
+	}
+	if w.Size() != meta.GetSizeBytes() {
+		return status.Errorf(codes.InvalidArgument, "received %d bytes, expected %d", w.Size(), meta.GetSizeBytes())
+	}
+	if got := w.Sum(); got != want_sum {
+		return status.Errorf(codes.DataLoss, "sha256 mismatch: got %s, expected %s", got, want_sum)
+	}
+	sum, err := w.Commit()
+	committed = true
+	if err != nil {
+		return status.Error(codes.Internal, "commit blob: "+err.Error())
+	}
+
+	attachment := &taskv1.Attachment{
+		AttachmentId: uuid.New().String(),
+		TaskId:       task_id,
+		Filename:     filename,
+		ContentType:  strings.TrimSpace(meta.GetContentType()),
+		SizeBytes:    meta.GetSizeBytes(),
+		Sha256:       sum,
+		UploadedBy:   principalFromContext(stream.Context()),
+		CreatedAt:    timestamppb.New(time.Now()),
+	}
+	s.mu.Lock()
+	if _, ok := s.taskMap[task_id]; !ok {
+		s.mu.Unlock()
+		return status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	if err := s.appendLocked(recordAttachment, attachment); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.attachments[task_id] = append(s.attachments[task_id], attachment)
+	s.mu.Unlock()
+	return stream.SendAndClose(attachment)
+}
+
+func (s *TaskServiceServer) DownloadAttachment(req *taskv1.DownloadAttachmentRequest, stream taskv1.TaskService_DownloadAttachmentServer) error {
+	if s.blobs == nil {
+		return status.Error(codes.FailedPrecondition, "attachments are not configured")
+	}
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	attachment_id := strings.TrimSpace(req.GetAttachmentId())
+	if attachment_id == "" {
+		return status.Error(codes.InvalidArgument, "attachment_id is required")
+	}
+	var attachment *taskv1.Attachment
+	s.mu.RLock()
+	for _, a := range s.attachments[task_id] {
+		if a.GetAttachmentId() == attachment_id {
+			attachment = a
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if attachment == nil {
+		return status.Error(codes.NotFound, "attachment not found with id "+attachment_id)
+	}
+
+	f, err := s.blobs.Open(attachment.GetSha256())
+	if errors.Is(err, os.ErrNotExist) {
+		return status.Error(codes.DataLoss, "blob missing for attachment "+attachment_id)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, "open blob: "+err.Error())
+	}
+	defer f.Close()
+
+	if err := stream.Send(&taskv1.DownloadAttachmentResponse{Payload: &taskv1.DownloadAttachmentResponse_Attachment{Attachment: attachment}}); err != nil {
+		return err
+	}
+	buf := make([]byte, attachmentChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			chunk := &taskv1.DownloadAttachmentResponse{Payload: &taskv1.DownloadAttachmentResponse_Chunk{Chunk: buf[:n]}}
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, "read blob: "+err.Error())
+		}
+	}
+}
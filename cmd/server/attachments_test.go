@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/blobstore"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newAttachmentClient(t *testing.T) (taskv1.TaskServiceClient, string, func()) {
+	t.Helper()
+	svc := NewTaskServiceServer()
+	blobs, err := blobstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("blobstore.New failed: %v", err)
+	}
+	svc.blobs = blobs
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "with files"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	return client, created.GetTask().GetTaskId(), cleanup
+}
+
+func upload(client taskv1.TaskServiceClient, meta *taskv1.AttachmentMetadata, data []byte, chunkSize int) (*taskv1.Attachment, error) {
+	stream, err := client.UploadAttachment(ctxWithAuth("devtoken"))
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&taskv1.UploadAttachmentRequest{Payload: &taskv1.UploadAttachmentRequest_Metadata{Metadata: meta}}); err != nil {
+		return nil, err
+	}
+	for len(data) > 0 {
+		n := min(chunkSize, len(data))
+		if err := stream.Send(&taskv1.UploadAttachmentRequest{Payload: &taskv1.UploadAttachmentRequest_Chunk{Chunk: data[:n]}}); err != nil {
+			break
+		}
+		data = data[n:]
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestTaskService_Attachment_UploadThenDownload(t *testing.T) {
+	client, task_id, cleanup := newAttachmentClient(t)
+	defer cleanup()
+
+	data := bytes.Repeat([]byte("log line\n"), 20000)
+	sum := sha256.Sum256(data)
+	attachment, err := upload(client, &taskv1.AttachmentMetadata{
+		TaskId:    task_id,
+		Filename:  "run.log",
+		SizeBytes: int64(len(data)),
+		Sha256:    hex.EncodeToString(sum[:]),
+	}, data, 10000)
+	if err != nil {
+		t.Fatalf("UploadAttachment failed: %v", err)
+	}
+	if attachment.GetUploadedBy() != devUser {
+		t.Fatalf("expected uploaded_by %q, got %q", devUser, attachment.GetUploadedBy())
+	}
+
+	stream, err := client.DownloadAttachment(ctxWithAuth("devtoken"), &taskv1.DownloadAttachmentRequest{TaskId: task_id, AttachmentId: attachment.GetAttachmentId()})
+	if err != nil {
+		t.Fatalf("DownloadAttachment failed: %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("DownloadAttachment Recv failed: %v", err)
+	}
+	if first.GetAttachment().GetFilename() != "run.log" {
+		t.Fatalf("expected metadata first, got %v", first)
+	}
+	var got bytes.Buffer
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DownloadAttachment Recv failed: %v", err)
+		}
+		got.Write(msg.GetChunk())
+	}
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Fatalf("downloaded %d bytes, want %d identical bytes", got.Len(), len(data))
+	}
+}
+
+func TestTaskService_Attachment_UploadRejected(t *testing.T) {
+	client, task_id, cleanup := newAttachmentClient(t)
+	defer cleanup()
+
+	data := []byte("screenshot")
+	sum := sha256.Sum256(data)
+	good := hex.EncodeToString(sum[:])
+	cases := []struct {
+		name string
+		meta *taskv1.AttachmentMetadata
+		code codes.Code
+	}{
+		{
+			name: "checksum mismatch",
+			meta: &taskv1.AttachmentMetadata{TaskId: task_id, Filename: "a.png", SizeBytes: int64(len(data)), Sha256: hex.EncodeToString(make([]byte, 32))},
+			code: codes.DataLoss,
+		},
+		{
+			name: "more bytes than declared",
+			meta: &taskv1.AttachmentMetadata{TaskId: task_id, Filename: "a.png", SizeBytes: 4, Sha256: good},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "over size limit",
+			meta: &taskv1.AttachmentMetadata{TaskId: task_id, Filename: "a.png", SizeBytes: maxAttachmentSize + 1, Sha256: good},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown task",
+			meta: &taskv1.AttachmentMetadata{TaskId: "does-not-exist", Filename: "a.png", SizeBytes: int64(len(data)), Sha256: good},
+			code: codes.NotFound,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := upload(client, tc.meta, data, 3)
+			if status.Code(err) != tc.code {
+				t.Fatalf("expected code %v, got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestTaskService_Attachment_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	blobs, err := blobstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("blobstore.New failed: %v", err)
+	}
+	svc, _ := newStoreServer(t, path)
+	svc.blobs = blobs
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "with files"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	data := []byte("report body")
+	sum := sha256.Sum256(data)
+	attachment, err := upload(client, &taskv1.AttachmentMetadata{TaskId: task_id, Filename: "report.txt", SizeBytes: int64(len(data)), Sha256: hex.EncodeToString(sum[:])}, data, 4)
+	if err != nil {
+		t.Fatalf("UploadAttachment failed: %v", err)
+	}
+	cleanup()
+
+	// The first restart compacts the journal; the second loads the result.
+	for restart := 1; restart <= 2; restart++ {
+		restarted, _ := newStoreServer(t, path)
+		restarted.blobs = blobs
+		client, cleanup = newBufconnClient(t, withServer(restarted))
+		stream, err := client.DownloadAttachment(ctxWithAuth("devtoken"), &taskv1.DownloadAttachmentRequest{TaskId: task_id, AttachmentId: attachment.GetAttachmentId()})
+		if err != nil {
+			t.Fatalf("DownloadAttachment failed: %v", err)
+		}
+		var got bytes.Buffer
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("restart %d: DownloadAttachment Recv failed: %v", restart, err)
+			}
+			got.Write(msg.GetChunk())
+		}
+		if !bytes.Equal(got.Bytes(), data) {
+			t.Fatalf("restart %d: expected the attachment's content, got %q", restart, got.Bytes())
+		}
+		cleanup()
+	}
+}
@@ -10,19 +10,80 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// devUser is the identity attached to callers presenting the single dev token.
+const devUser = "dev"
+
+type principalKey struct{}
+
+// principalFromContext returns the authenticated user for the current call.
+func principalFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(principalKey{}).(string)
+	return user
+}
+
 func authUnaryInterceptor(expectedToken string) grpc.UnaryServerInterceptor {
+	return authUnaryInterceptorWithUsers(map[string]string{expectedToken: devUser})
+}
+
+// authUnaryInterceptorWithUsers accepts any token in users and records the
+// mapped user name on the context.
+func authUnaryInterceptorWithUsers(users map[string]string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
-		}
-		vals := md.Get("authorization")
-		if len(vals) == 0 || strings.TrimSpace(vals[0]) == "" {
-			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+		user, err := authenticate(ctx, users)
+		if err != nil {
+			return nil, err
 		}
-		if vals[0] != "Bearer "+expectedToken {
-			return nil, status.Error(codes.PermissionDenied, "invalid authorization token")
+		return handler(context.WithValue(ctx, principalKey{}, user), req)
+	}
+}
+
+func authenticate(ctx context.Context, users map[string]string) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	vals := md.Get("authorization")
+	if len(vals) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+	return userForAuthorization(users, vals[0])
+}
+
+// userForAuthorization maps an authorization header value to its user.
+func userForAuthorization(users map[string]string, header string) (string, error) {
+	if strings.TrimSpace(header) == "" {
+		return "", status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return "", status.Error(codes.PermissionDenied, "invalid authorization token")
+	}
+	user, ok := users[token]
+	if !ok {
+		return "", status.Error(codes.PermissionDenied, "invalid authorization token")
+	}
+	return user, nil
+}
+
+func authStreamInterceptor(expectedToken string) grpc.StreamServerInterceptor {
+	return authStreamInterceptorWithUsers(map[string]string{expectedToken: devUser})
+}
+
+func authStreamInterceptorWithUsers(users map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		user, err := authenticate(ss.Context(), users)
+		if err != nil {
+			return err
 		}
-		return handler(ctx, req)
+		return handler(srv, &authedStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), principalKey{}, user)})
 	}
 }
+
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const maxCancelReason = 1024
+
+func (s *TaskServiceServer) CancelTask(ctx context.Context, req *taskv1.CancelTaskRequest) (*taskv1.Task, error) {
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	reason := strings.TrimSpace(req.GetReason())
+	if len(reason) > maxCancelReason {
+		return nil, status.Errorf(codes.InvalidArgument, "reason must be at most %d bytes", maxCancelReason)
+	}
+	if reason == "" {
+		reason = "canceled by " + principalFromContext(ctx)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.taskMap[task_id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	if isTerminal(task.GetStatus()) {
+		return nil, status.Error(codes.FailedPrecondition, "task "+task_id+" has already finished")
+	}
+	return s.cancelTaskLocked(ctx, task, reason)
+}
+
+// cancelTaskLocked makes an unfinished task CANCELED, with reason as the
+// event message. s.mu must be held.
+func (s *TaskServiceServer) cancelTaskLocked(ctx context.Context, task *taskv1.Task, reason string) (*taskv1.Task, error) {
+	canceled := proto.Clone(task).(*taskv1.Task)
+	canceled.Status = taskv1.TaskStatus_TASK_STATUS_CANCELED
+	canceled.UpdatedAt = timestamppb.New(time.Now())
+	if err := s.replaceTaskWithMessageLocked(ctx, canceled, reason); err != nil {
+		return nil, err
+	}
+	return canceled, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskService_CancelTask(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "unwanted"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	canceled, err := client.CancelTask(ctxWithAuth("devtoken"), &taskv1.CancelTaskRequest{TaskId: task_id, Reason: "no longer needed"})
+	if err != nil || canceled.GetStatus() != taskv1.TaskStatus_TASK_STATUS_CANCELED {
+		t.Fatalf("expected the task CANCELED, got %v, %v", canceled, err)
+	}
+	if msg := lastEventMessage(t, client); msg != "no longer needed" {
+		t.Fatalf("expected the reason as the event message, got %q", msg)
+	}
+	_, err = client.CancelTask(ctxWithAuth("devtoken"), &taskv1.CancelTaskRequest{TaskId: task_id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition canceling twice, got %v", err)
+	}
+	_, err = client.CancelTask(ctxWithAuth("devtoken"), &taskv1.CancelTaskRequest{TaskId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestTaskService_CancelTask_RevokesSessionLease(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "long"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(helloMsg("w1", 1)); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+	task := recvAssignment(t, stream)
+	if _, err := client.CancelTask(ctxWithAuth("devtoken"), &taskv1.CancelTaskRequest{TaskId: task.GetTaskId()}); err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+	msg, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WorkerSession Recv failed: %v", err)
+	}
+	if msg.GetRevoked().GetTaskId() != task.GetTaskId() || !strings.Contains(msg.GetRevoked().GetReason(), "canceled") {
+		t.Fatalf("expected the canceled task to be revoked, got %v", msg)
+	}
+}
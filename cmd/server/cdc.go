@@ -0,0 +1,50 @@
+package main
+
+import (
+	"cmp"
+	"log"
+	"slices"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/cdc"
+)
+
+// cdcBatch bounds the events exported per write.
+const cdcBatch = 1000
+
+// runCDC exports the event log to sink as it grows and rotates idle files
+// every interval. The event log must be durable (see openStore) for restarts
+// not to lose records.
+func (s *TaskServiceServer) runCDC(sink *cdc.Sink, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.exportCDC(sink)
+		if err != nil {
+			log.Printf("cdc: %v", err)
+		} else if n == cdcBatch {
+			continue
+		}
+		select {
+		case <-s.cdcWake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// exportCDC writes up to cdcBatch logged events after the sink's checkpoint
+// and returns how many it handed over.
+func (s *TaskServiceServer) exportCDC(sink *cdc.Sink) (int, error) {
+	after := sink.Checkpoint()
+	s.mu.RLock()
+	i, _ := slices.BinarySearchFunc(s.events, after+1, func(ev *taskv1.TaskEvent, revision int64) int {
+		return cmp.Compare(ev.GetRevision(), revision)
+	})
+	if i == 0 && len(s.events) > 0 && s.events[0].GetRevision() > after+1 {
+		log.Printf("cdc: events %d to %d were pruned before they could be exported", after+1, s.events[0].GetRevision()-1)
+	}
+	batch := slices.Clone(s.events[i:min(i+cdcBatch, len(s.events))])
+	s.mu.RUnlock()
+	return len(batch), sink.Write(batch)
+}
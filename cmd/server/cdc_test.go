@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/cdc"
+)
+
+func TestTaskService_CDC_ExportsTaskChangesAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	dir := t.TempDir()
+	svc, _ := newStoreServer(t, path)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	sink, err := cdc.Open(cdc.Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("cdc.Open failed: %v", err)
+	}
+
+	first, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "one"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: first.GetTask().GetTaskId(), Body: "not a task change"}); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := svc.exportCDC(sink); err != nil {
+		t.Fatalf("exportCDC failed: %v", err)
+	}
+	// Changed but never exported before the "crash".
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: first.GetTask().GetTaskId()}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	cleanup()
+	sink.Close()
+
+	restarted, _ := newStoreServer(t, path)
+	sink, err = cdc.Open(cdc.Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("cdc.Open failed: %v", err)
+	}
+	defer sink.Close()
+	if _, err := restarted.exportCDC(sink); err != nil {
+		t.Fatalf("exportCDC failed: %v", err)
+	}
+	if _, err := restarted.exportCDC(sink); err != nil {
+		t.Fatalf("exportCDC failed: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "active.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var records []map[string]any
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("bad record %q: %v", sc.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected create and completion exactly once, got %v", records)
+	}
+	if records[0]["type"] != "TASK_EVENT_TYPE_CREATED" || records[1]["type"] != "TASK_EVENT_TYPE_STATUS_CHANGED" || records[1]["revision"] != "3" {
+		t.Fatalf("unexpected records: %v", records)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *TaskServiceServer) AddComment(ctx context.Context, req *taskv1.AddCommentRequest) (*taskv1.Comment, error) {
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	body := strings.TrimSpace(req.GetBody())
+	if body == "" {
+		return nil, status.Error(codes.InvalidArgument, "body is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.taskMap[task_id]; !ok {
+		return nil, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	now := timestamppb.New(time.Now())
+	comment := &taskv1.Comment{
+		CommentId: uuid.New().String(),
+		TaskId:    task_id,
+		Author:    principalFromContext(ctx),
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.publishLocked(ctx, commentEvent(taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED, comment)); err != nil {
+		return nil, err
+	}
+	s.comments[task_id] = append(s.comments[task_id], comment)
+	return comment, nil
+}
+
+func (s *TaskServiceServer) ListComments(ctx context.Context, req *taskv1.ListCommentsRequest) (*taskv1.ListCommentsResponse, error) {
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	page_size := req.GetPageSize()
+	if page_size <= 0 {
+		page_size = 10
+	} else if page_size > 100 {
+		page_size = 100
+	}
+	offset := 0
+	if page_token := req.GetPageToken(); page_token != "" {
+		var err error
+		offset, err = strconv.Atoi(page_token)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.taskMap[task_id]; !ok {
+		return nil, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	comments := s.comments[task_id]
+	res := &taskv1.ListCommentsResponse{Comments: []*taskv1.Comment{}}
+	if offset >= len(comments) {
+		return res, nil
+	}
+	end := min(offset+int(page_size), len(comments))
+	res.Comments = append(res.Comments, comments[offset:end]...)
+	if end < len(comments) {
+		res.NextPageToken = strconv.Itoa(end)
+	}
+	return res, nil
+}
+
+func (s *TaskServiceServer) EditComment(ctx context.Context, req *taskv1.EditCommentRequest) (*taskv1.Comment, error) {
+	body := strings.TrimSpace(req.GetBody())
+	if body == "" {
+		return nil, status.Error(codes.InvalidArgument, "body is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findOwnCommentLocked(ctx, req.GetTaskId(), req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+	task_id := strings.TrimSpace(req.GetTaskId())
+	// Replace rather than mutate so pages already handed out stay consistent.
+	comment := proto.Clone(s.comments[task_id][i]).(*taskv1.Comment)
+	comment.Body = body
+	comment.UpdatedAt = timestamppb.New(time.Now())
+	if err := s.publishLocked(ctx, commentEvent(taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_EDITED, comment)); err != nil {
+		return nil, err
+	}
+	s.comments[task_id][i] = comment
+	return comment, nil
+}
+
+func (s *TaskServiceServer) DeleteComment(ctx context.Context, req *taskv1.DeleteCommentRequest) (*taskv1.DeleteCommentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.findOwnCommentLocked(ctx, req.GetTaskId(), req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+	task_id := strings.TrimSpace(req.GetTaskId())
+	comments := s.comments[task_id]
+	if err := s.publishLocked(ctx, commentEvent(taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_DELETED, comments[i])); err != nil {
+		return nil, err
+	}
+	s.comments[task_id] = append(comments[:i:i], comments[i+1:]...)
+	return &taskv1.DeleteCommentResponse{}, nil
+}
+
+// findOwnCommentLocked returns the index of the comment in its task's list,
+// failing with PermissionDenied if the caller did not write it.
+func (s *TaskServiceServer) findOwnCommentLocked(ctx context.Context, task_id, comment_id string) (int, error) {
+	task_id = strings.TrimSpace(task_id)
+	if task_id == "" {
+		return 0, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	comment_id = strings.TrimSpace(comment_id)
+	if comment_id == "" {
+		return 0, status.Error(codes.InvalidArgument, "comment_id is required")
+	}
+	if _, ok := s.taskMap[task_id]; !ok {
+		return 0, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	for i, c := range s.comments[task_id] {
+		if c.GetCommentId() != comment_id {
+			continue
+		}
+		if c.GetAuthor() != principalFromContext(ctx) {
+			return 0, status.Error(codes.PermissionDenied, "only the author can modify comment "+comment_id)
+		}
+		return i, nil
+	}
+	return 0, status.Error(codes.NotFound, "comment not found with id "+comment_id)
+}
+
+func commentEvent(typ taskv1.TaskEventType, c *taskv1.Comment) *taskv1.TaskEvent {
+	return &taskv1.TaskEvent{
+		TaskId:  c.GetTaskId(),
+		Type:    typ,
+		At:      timestamppb.New(time.Now()),
+		Message: c.GetAuthor() + ": " + c.GetBody(),
+		Comment: c,
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskService_Comments_AddAndListPaginated(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "discuss"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	for i := 1; i <= 3; i++ {
+		c, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: "c" + strconv.Itoa(i)})
+		if err != nil {
+			t.Fatalf("AddComment(%d) failed: %v", i, err)
+		}
+		if c.GetAuthor() != devUser {
+			t.Fatalf("expected author %q, got %q", devUser, c.GetAuthor())
+		}
+	}
+
+	page1, err := client.ListComments(ctxWithAuth("devtoken"), &taskv1.ListCommentsRequest{TaskId: task_id, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListComments page1 failed: %v", err)
+	}
+	if len(page1.GetComments()) != 2 || page1.GetComments()[0].GetBody() != "c1" || page1.GetComments()[1].GetBody() != "c2" {
+		t.Fatalf("unexpected page1: %v", page1.GetComments())
+	}
+	page2, err := client.ListComments(ctxWithAuth("devtoken"), &taskv1.ListCommentsRequest{TaskId: task_id, PageSize: 2, PageToken: page1.GetNextPageToken()})
+	if err != nil {
+		t.Fatalf("ListComments page2 failed: %v", err)
+	}
+	if len(page2.GetComments()) != 1 || page2.GetComments()[0].GetBody() != "c3" {
+		t.Fatalf("unexpected page2: %v", page2.GetComments())
+	}
+	if page2.GetNextPageToken() != "" {
+		t.Fatalf("expected empty next_page_token on last page, got %q", page2.GetNextPageToken())
+	}
+}
+
+func TestTaskService_Comments_AddToMissingTask(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	_, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: "does-not-exist", Body: "hi"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", status.Code(err))
+	}
+}
+
+func TestTaskService_Comments_AuthorOnly(t *testing.T) {
+	client, cleanup := newBufconnClient(t, withUsers(map[string]string{"alicetoken": "alice", "bobtoken": "bob"}))
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("alicetoken"), &taskv1.CreateTaskRequest{Title: "discuss"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	c, err := client.AddComment(ctxWithAuth("alicetoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: "first"})
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	_, err = client.EditComment(ctxWithAuth("bobtoken"), &taskv1.EditCommentRequest{TaskId: task_id, CommentId: c.GetCommentId(), Body: "hijack"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied on edit, got %v", status.Code(err))
+	}
+	_, err = client.DeleteComment(ctxWithAuth("bobtoken"), &taskv1.DeleteCommentRequest{TaskId: task_id, CommentId: c.GetCommentId()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied on delete, got %v", status.Code(err))
+	}
+
+	edited, err := client.EditComment(ctxWithAuth("alicetoken"), &taskv1.EditCommentRequest{TaskId: task_id, CommentId: c.GetCommentId(), Body: "second"})
+	if err != nil {
+		t.Fatalf("EditComment failed: %v", err)
+	}
+	if edited.GetBody() != "second" {
+		t.Fatalf("expected body %q, got %q", "second", edited.GetBody())
+	}
+	if _, err := client.DeleteComment(ctxWithAuth("alicetoken"), &taskv1.DeleteCommentRequest{TaskId: task_id, CommentId: c.GetCommentId()}); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+	list, err := client.ListComments(ctxWithAuth("alicetoken"), &taskv1.ListCommentsRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("ListComments failed: %v", err)
+	}
+	if len(list.GetComments()) != 0 {
+		t.Fatalf("expected no comments after delete, got %d", len(list.GetComments()))
+	}
+}
+
+func TestTaskService_Comments_EmitWatchEvent(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "discuss"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WatchTask(ctx, &taskv1.WatchTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	// The first event guarantees the watch is registered before commenting.
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("WatchTask Recv failed: %v", err)
+	}
+	if _, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: "hello"}); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("WatchTask Recv failed before comment event: %v", err)
+		}
+		if ev.GetType() == taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED {
+			if ev.GetComment().GetBody() != "hello" {
+				t.Fatalf("expected comment body %q, got %q", "hello", ev.GetComment().GetBody())
+			}
+			return
+		}
+	}
+}
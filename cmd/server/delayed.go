@@ -0,0 +1,110 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"log"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// promoteRetry is how long a task whose promotion could not be stored waits
+// before the next try.
+const promoteRetry = time.Second
+
+// applyRunAt sets the run_at of a new task, making it SCHEDULED when that is
+// in the future.
+func applyRunAt(task *taskv1.Task, run_at *timestamppb.Timestamp) error {
+	if run_at == nil {
+		return nil
+	}
+	if err := run_at.CheckValid(); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid run_at: "+err.Error())
+	}
+	task.RunAt = run_at
+	if run_at.AsTime().After(task.GetCreatedAt().AsTime()) {
+		task.Status = taskv1.TaskStatus_TASK_STATUS_SCHEDULED
+	}
+	return nil
+}
+
+type delayedTask struct {
+	at      time.Time
+	task_id string
+}
+
+// delayHeap orders SCHEDULED tasks by run_at. Entries are not removed when
+// a task changes; promoteDueLocked skips the stale ones.
+type delayHeap []delayedTask
+
+func (h delayHeap) Len() int           { return len(h) }
+func (h delayHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h delayHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *delayHeap) Push(x any)        { *h = append(*h, x.(delayedTask)) }
+func (h *delayHeap) Pop() any {
+	old := *h
+	d := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return d
+}
+
+// delayLocked arranges for a SCHEDULED task to become PENDING at its run_at.
+// s.mu must be held.
+func (s *TaskServiceServer) delayLocked(task *taskv1.Task) {
+	heap.Push(&s.delayed, delayedTask{at: task.GetRunAt().AsTime(), task_id: task.GetTaskId()})
+	select {
+	case s.delayWake <- struct{}{}:
+	default:
+	}
+}
+
+// runDelayed promotes SCHEDULED tasks when their run_at arrives.
+func (s *TaskServiceServer) runDelayed() {
+	timer := time.NewTimer(time.Hour)
+	for {
+		s.mu.Lock()
+		next, ok := s.promoteDueLocked(time.Now())
+		s.mu.Unlock()
+		wait := time.Hour
+		if ok {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.delayWake:
+		}
+	}
+}
+
+// promoteDueLocked moves every SCHEDULED task whose run_at is not after now
+// to PENDING and returns when it should run next, if ever. s.mu must be
+// held.
+func (s *TaskServiceServer) promoteDueLocked(now time.Time) (time.Time, bool) {
+	for s.delayed.Len() > 0 && !s.delayed[0].at.After(now) {
+		d := heap.Pop(&s.delayed).(delayedTask)
+		task, ok := s.taskMap[d.task_id]
+		if !ok || task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_SCHEDULED || !task.GetRunAt().AsTime().Equal(d.at) {
+			continue
+		}
+		promoted := proto.Clone(task).(*taskv1.Task)
+		promoted.Status = taskv1.TaskStatus_TASK_STATUS_PENDING
+		promoted.UpdatedAt = timestamppb.New(now)
+		if err := s.replaceTaskLocked(context.Background(), promoted); err != nil {
+			// The task keeps its place; try again shortly.
+			log.Printf("delayed: promoting task %s: %v", d.task_id, err)
+			heap.Push(&s.delayed, d)
+			return now.Add(promoteRetry), true
+		}
+	}
+	if s.delayed.Len() == 0 {
+		return time.Time{}, false
+	}
+	return s.delayed[0].at, true
+}
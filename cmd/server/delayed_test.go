@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTaskService_Delayed_PromotedAtRunAt(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	run_at := time.Now().Add(300 * time.Millisecond)
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "later", RunAt: timestamppb.New(run_at)})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	if created.GetTask().GetStatus() != taskv1.TaskStatus_TASK_STATUS_SCHEDULED {
+		t.Fatalf("expected SCHEDULED, got %v", created.GetTask().GetStatus())
+	}
+	claimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil || claimed.GetTask() != nil {
+		t.Fatalf("expected nothing claimable before run_at, got %v, %v", claimed, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+		if err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+		if got.GetStatus() == taskv1.TaskStatus_TASK_STATUS_PENDING {
+			if time.Now().Before(run_at) {
+				t.Fatalf("promoted before run_at")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task still %v long after run_at", got.GetStatus())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	claimed, err = client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil || claimed.GetTask().GetTaskId() != task_id {
+		t.Fatalf("expected the promoted task to be claimable, got %v, %v", claimed, err)
+	}
+}
+
+func TestTaskService_Delayed_PastAndInvalidRunAt(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "overdue", RunAt: timestamppb.New(time.Now().Add(-time.Minute))})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if created.GetTask().GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING {
+		t.Fatalf("expected a past run_at to be PENDING, got %v", created.GetTask().GetStatus())
+	}
+	_, err = client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "bad", RunAt: &timestamppb.Timestamp{Nanos: -1}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an invalid run_at, got %v", err)
+	}
+}
+
+func TestTaskService_Delayed_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	created, err := svc.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "nightly", RunAt: timestamppb.New(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	restarted, _ := newStoreServer(t, path)
+	restarted.mu.Lock()
+	restarted.promoteDueLocked(time.Now().Add(2 * time.Hour))
+	restarted.mu.Unlock()
+	got, err := restarted.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING {
+		t.Fatalf("expected the reloaded task to be promoted at run_at, got %v", got.GetStatus())
+	}
+}
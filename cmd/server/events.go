@@ -0,0 +1,124 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultMaxEvents bounds the events kept for ListEvents regardless of
+	// age. Each event holds a copy of its task, payloads included, so the
+	// bound is what keeps the log's memory in check.
+	defaultMaxEvents      = 10_000
+	defaultEventRetention = 7 * 24 * time.Hour
+)
+
+// logEventLocked adds a published event to the log served by ListEvents.
+// s.mu must be held.
+func (s *TaskServiceServer) logEventLocked(ev *taskv1.TaskEvent) {
+	s.events = append(s.events, ev)
+	s.pruneEventsLocked()
+	select {
+	case s.cdcWake <- struct{}{}:
+	default:
+	}
+}
+
+// pruneEventsLocked drops events beyond the size bound or older than the
+// retention period. s.mu must be held.
+func (s *TaskServiceServer) pruneEventsLocked() {
+	cutoff := time.Now().Add(-s.eventRetention)
+	drop := max(len(s.events)-s.maxEvents, 0)
+	for drop < len(s.events) && s.events[drop].GetAt().AsTime().Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		s.events = slices.Delete(s.events, 0, drop)
+	}
+}
+
+// ListEvents pages through the event log oldest first. Page tokens are
+// revisions, so they stay valid while old events are pruned.
+func (s *TaskServiceServer) ListEvents(ctx context.Context, req *taskv1.ListEventsRequest) (*taskv1.ListEventsResponse, error) {
+	page_size := req.GetPageSize()
+	if page_size <= 0 {
+		page_size = 10
+	} else if page_size > 100 {
+		page_size = 100
+	}
+	var after int64
+	if page_token := req.GetPageToken(); page_token != "" {
+		var err error
+		after, err = strconv.ParseInt(page_token, 10, 64)
+		if err != nil || after < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	if err := validateLabels(req.GetFilter().GetLabels()); err != nil {
+		return nil, err
+	}
+	if ts := req.GetStartTime(); ts != nil && ts.CheckValid() != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid start_time")
+	}
+	if ts := req.GetEndTime(); ts != nil && ts.CheckValid() != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid end_time")
+	}
+	task_id := strings.TrimSpace(req.GetTaskId())
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, _ := slices.BinarySearchFunc(s.events, after+1, func(ev *taskv1.TaskEvent, revision int64) int {
+		return cmp.Compare(ev.GetRevision(), revision)
+	})
+	res := &taskv1.ListEventsResponse{Events: []*taskv1.TaskEvent{}}
+	for ; i < len(s.events) && len(res.Events) < int(page_size); i++ {
+		ev := s.events[i]
+		if task_id != "" && ev.GetTaskId() != task_id {
+			continue
+		}
+		if !s.eventLogMatchesLocked(req, ev) {
+			continue
+		}
+		res.Events = append(res.Events, ev)
+	}
+	if i < len(s.events) {
+		res.NextPageToken = strconv.FormatInt(s.events[i-1].GetRevision(), 10)
+	}
+	return res, nil
+}
+
+// eventLogMatchesLocked applies the ListEvents filters other than task_id.
+// Events without a task, such as comments, are matched against the task's
+// current state. s.mu must be held.
+func (s *TaskServiceServer) eventLogMatchesLocked(req *taskv1.ListEventsRequest, ev *taskv1.TaskEvent) bool {
+	if len(req.GetTypes()) > 0 && !slices.Contains(req.GetTypes(), ev.GetType()) {
+		return false
+	}
+	if req.GetActor() != "" && ev.GetActor() != req.GetActor() {
+		return false
+	}
+	at := ev.GetAt().AsTime()
+	if req.GetStartTime() != nil && at.Before(req.GetStartTime().AsTime()) {
+		return false
+	}
+	if req.GetEndTime() != nil && !at.Before(req.GetEndTime().AsTime()) {
+		return false
+	}
+	if req.GetFilter() == nil {
+		return true
+	}
+	if ev.GetTask() != nil {
+		return eventMatchesFilter(req.GetFilter(), ev)
+	}
+	task, ok := s.taskMap[ev.GetTaskId()]
+	return ok && matchesFilter(req.GetFilter(), task, task.GetStatus())
+}
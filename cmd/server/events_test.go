@@ -0,0 +1,140 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTaskService_ListEvents_Filters(t *testing.T) {
+	client, cleanup := newBufconnClient(t, withUsers(map[string]string{"alicetoken": "alice", "bobtoken": "bob"}))
+	defer cleanup()
+
+	infra, err := client.CreateTask(ctxWithAuth("alicetoken"), &taskv1.CreateTaskRequest{Title: "infra", Labels: map[string]string{"team": "infra"}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := client.CreateTask(ctxWithAuth("bobtoken"), &taskv1.CreateTaskRequest{Title: "web", Labels: map[string]string{"team": "web"}}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := client.AddComment(ctxWithAuth("bobtoken"), &taskv1.AddCommentRequest{TaskId: infra.GetTask().GetTaskId(), Body: "on it"}); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := client.SetTaskResult(ctxWithAuth("bobtoken"), &taskv1.SetTaskResultRequest{TaskId: infra.GetTask().GetTaskId()}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+
+	res, err := client.ListEvents(ctxWithAuth("alicetoken"), &taskv1.ListEventsRequest{Filter: &taskv1.TaskFilter{Labels: map[string]string{"team": "infra"}}})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	want := []taskv1.TaskEventType{
+		taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED,
+		taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED,
+		taskv1.TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED,
+	}
+	if len(res.GetEvents()) != len(want) {
+		t.Fatalf("expected %d infra events, got %v", len(want), res.GetEvents())
+	}
+	for i, ev := range res.GetEvents() {
+		if ev.GetType() != want[i] || ev.GetTaskId() != infra.GetTask().GetTaskId() {
+			t.Fatalf("event %d: expected %v for the infra task, got %v", i, want[i], ev)
+		}
+	}
+	last := res.GetEvents()[2]
+	if last.GetActor() != "bob" || last.GetPreviousStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING || last.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("unexpected status change: %v", last)
+	}
+
+	res, err = client.ListEvents(ctxWithAuth("alicetoken"), &taskv1.ListEventsRequest{Actor: "alice"})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if len(res.GetEvents()) != 1 || res.GetEvents()[0].GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED {
+		t.Fatalf("expected alice's single create, got %v", res.GetEvents())
+	}
+
+	res, err = client.ListEvents(ctxWithAuth("alicetoken"), &taskv1.ListEventsRequest{EndTime: timestamppb.New(time.Now().Add(-time.Hour))})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if len(res.GetEvents()) != 0 {
+		t.Fatalf("expected no events before an hour ago, got %v", res.GetEvents())
+	}
+}
+
+func TestTaskService_ListEvents_Paginated(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "t"}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+	var revisions []int64
+	page_token := ""
+	for {
+		res, err := client.ListEvents(ctxWithAuth("devtoken"), &taskv1.ListEventsRequest{PageSize: 2, PageToken: page_token})
+		if err != nil {
+			t.Fatalf("ListEvents failed: %v", err)
+		}
+		for _, ev := range res.GetEvents() {
+			revisions = append(revisions, ev.GetRevision())
+		}
+		if page_token = res.GetNextPageToken(); page_token == "" {
+			break
+		}
+	}
+	if len(revisions) != 5 || revisions[0] != 1 || revisions[4] != 5 {
+		t.Fatalf("expected revisions 1..5 across pages, got %v", revisions)
+	}
+}
+
+func TestTaskService_ListEvents_Bounded(t *testing.T) {
+	svc := NewTaskServiceServer()
+	svc.maxEvents = 3
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "t"}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+	res, err := client.ListEvents(ctxWithAuth("devtoken"), &taskv1.ListEventsRequest{PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if evs := res.GetEvents(); len(evs) != 3 || evs[0].GetRevision() != 3 {
+		t.Fatalf("expected only the newest 3 events, got %v", evs)
+	}
+}
+
+func TestTaskService_ListEvents_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "before"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	cleanup()
+
+	restarted, _ := newStoreServer(t, path)
+	client, cleanup = newBufconnClient(t, withServer(restarted))
+	defer cleanup()
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "after"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	res, err := client.ListEvents(ctxWithAuth("devtoken"), &taskv1.ListEventsRequest{})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if len(res.GetEvents()) != 2 || res.GetEvents()[0].GetTask().GetTitle() != "before" || res.GetEvents()[1].GetRevision() != 2 {
+		t.Fatalf("expected the logged event and a new one at revision 2, got %v", res.GetEvents())
+	}
+}
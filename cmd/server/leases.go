@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultLeaseDuration = 30 * time.Second
+	minLeaseDuration     = time.Second
+	maxLeaseDuration     = 10 * time.Minute
+)
+
+func leaseDuration(d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return defaultLeaseDuration, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, status.Error(codes.InvalidArgument, "invalid lease_duration: "+err.Error())
+	}
+	if ld := d.AsDuration(); ld < minLeaseDuration || ld > maxLeaseDuration {
+		return 0, status.Errorf(codes.InvalidArgument, "lease_duration must be between %s and %s", minLeaseDuration, maxLeaseDuration)
+	}
+	return d.AsDuration(), nil
+}
+
+// ClaimTask moves the oldest PENDING task to RUNNING under a new lease for
+// the calling worker, optionally only from some queues.
+func (s *TaskServiceServer) ClaimTask(ctx context.Context, req *taskv1.ClaimTaskRequest) (*taskv1.ClaimTaskResponse, error) {
+	worker_id := strings.TrimSpace(req.GetWorkerId())
+	if worker_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "worker_id is required")
+	}
+	ttl, err := leaseDuration(req.GetLeaseDuration())
+	if err != nil {
+		return nil, err
+	}
+	queues := req.GetQueues()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.claimLocked(ctx, worker_id, ttl, func(task *taskv1.Task) bool {
+		return len(queues) == 0 || slices.Contains(queues, taskQueue(task))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &taskv1.ClaimTaskResponse{Task: task}, nil
+}
+
+// claimLocked leases the oldest PENDING task accepted by match (nil accepts
+// any) to worker_id and returns it, or nil if there is none. Tasks in a
+// queue that is at its concurrency limit are passed over, so a busy queue
+// cannot hold up the others. s.mu must be held.
+func (s *TaskServiceServer) claimLocked(ctx context.Context, worker_id string, ttl time.Duration, match func(*taskv1.Task) bool) (*taskv1.Task, error) {
+	running := make(map[string]int32)
+	for _, task := range s.taskSlice {
+		if task.GetStatus() == taskv1.TaskStatus_TASK_STATUS_RUNNING {
+			running[taskQueue(task)]++
+		}
+	}
+	for _, task := range s.taskSlice {
+		if task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING || (match != nil && !match(task)) {
+			continue
+		}
+		if q := s.queueLocked(taskQueue(task)); q.GetMaxConcurrency() > 0 && running[q.GetName()] >= q.GetMaxConcurrency() {
+			continue
+		}
+		now := time.Now()
+		claimed := proto.Clone(task).(*taskv1.Task)
+		claimed.Status = taskv1.TaskStatus_TASK_STATUS_RUNNING
+		claimed.Attempt++
+		claimed.Progress = nil
+		claimed.UpdatedAt = timestamppb.New(now)
+		claimed.Lease = &taskv1.Lease{
+			LeaseId:   uuid.New().String(),
+			WorkerId:  worker_id,
+			ClaimedAt: timestamppb.New(now),
+			ExpiresAt: timestamppb.New(now.Add(ttl)),
+		}
+		if err := s.replaceTaskLocked(ctx, claimed); err != nil {
+			return nil, err
+		}
+		return claimed, nil
+	}
+	return nil, nil
+}
+
+// HeartbeatTask extends a live lease. Renewals are not published as events.
+func (s *TaskServiceServer) HeartbeatTask(ctx context.Context, req *taskv1.HeartbeatTaskRequest) (*taskv1.Lease, error) {
+	ttl, err := leaseDuration(req.GetLeaseDuration())
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renewLeaseLocked(req.GetTaskId(), req.GetWorkerId(), req.GetLeaseId(), ttl)
+}
+
+// renewLeaseLocked extends a live lease to now plus ttl. The renewal is
+// journaled so a restart keeps it. s.mu must be held.
+func (s *TaskServiceServer) renewLeaseLocked(task_id, worker_id, lease_id string, ttl time.Duration) (*taskv1.Lease, error) {
+	task, err := s.leasedTaskLocked(task_id, worker_id, lease_id)
+	if err != nil {
+		return nil, err
+	}
+	renewed := proto.Clone(task).(*taskv1.Task)
+	renewed.Lease.ExpiresAt = timestamppb.New(time.Now().Add(ttl))
+	if err := s.saveTaskLocked(renewed); err != nil {
+		return nil, err
+	}
+	return renewed.Lease, nil
+}
+
+func (s *TaskServiceServer) CompleteTask(ctx context.Context, req *taskv1.CompleteTaskRequest) (*taskv1.Task, error) {
+	if err := s.checkPayload("result", req.GetResult()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.leasedTaskLocked(req.GetTaskId(), req.GetWorkerId(), req.GetLeaseId())
+	if err != nil {
+		return nil, err
+	}
+	updated := proto.Clone(task).(*taskv1.Task)
+	updated.Status = taskv1.TaskStatus_TASK_STATUS_COMPLETED
+	updated.Result = req.GetResult()
+	updated.UpdatedAt = timestamppb.New(time.Now())
+	if err := s.replaceTaskLocked(ctx, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// FailTask ends the attempt with an error. Depending on the task's retry
+// policy it is retried later, fails, or is dead-lettered.
+func (s *TaskServiceServer) FailTask(ctx context.Context, req *taskv1.FailTaskRequest) (*taskv1.Task, error) {
+	errClass := strings.TrimSpace(req.GetErrorClass())
+	if err := checkErrorClass(errClass); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.leasedTaskLocked(req.GetTaskId(), req.GetWorkerId(), req.GetLeaseId())
+	if err != nil {
+		return nil, err
+	}
+	return s.failAttemptLocked(ctx, task, req.GetError(), errClass)
+}
+
+// leasedTaskLocked returns the task if worker_id holds its current, unexpired
+// lease. A worker whose lease expired or was re-assigned gets
+// FailedPrecondition and must abandon the task. s.mu must be held.
+func (s *TaskServiceServer) leasedTaskLocked(task_id, worker_id, lease_id string) (*taskv1.Task, error) {
+	task_id = strings.TrimSpace(task_id)
+	if task_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	if worker_id == "" || lease_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "worker_id and lease_id are required")
+	}
+	task, ok := s.taskMap[task_id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	lease := task.GetLease()
+	if task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING || lease.GetLeaseId() != lease_id || lease.GetWorkerId() != worker_id {
+		return nil, status.Error(codes.FailedPrecondition, "lease "+lease_id+" on task "+task_id+" is no longer held by "+worker_id)
+	}
+	if !time.Now().Before(lease.GetExpiresAt().AsTime()) {
+		return nil, status.Error(codes.FailedPrecondition, "lease "+lease_id+" on task "+task_id+" has expired")
+	}
+	return task, nil
+}
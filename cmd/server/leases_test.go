@@ -0,0 +1,145 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestTaskService_Leases_ClaimHeartbeatCompleteFail(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	var ids []string
+	for _, title := range []string{"first", "second"} {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: title})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, created.GetTask().GetTaskId())
+	}
+
+	first, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil {
+		t.Fatalf("ClaimTask failed: %v", err)
+	}
+	task := first.GetTask()
+	if task.GetTaskId() != ids[0] || task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING || task.GetLease().GetWorkerId() != "w1" {
+		t.Fatalf("expected the oldest task running under w1, got %v", task)
+	}
+	second, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w2"})
+	if err != nil || second.GetTask().GetTaskId() != ids[1] {
+		t.Fatalf("expected w2 to claim the second task, got %v, %v", second, err)
+	}
+	none, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w3"})
+	if err != nil || none.GetTask() != nil {
+		t.Fatalf("expected nothing left to claim, got %v, %v", none, err)
+	}
+
+	lease, err := client.HeartbeatTask(ctxWithAuth("devtoken"), &taskv1.HeartbeatTaskRequest{
+		TaskId:        task.GetTaskId(),
+		WorkerId:      "w1",
+		LeaseId:       task.GetLease().GetLeaseId(),
+		LeaseDuration: durationpb.New(5 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("HeartbeatTask failed: %v", err)
+	}
+	if !lease.GetExpiresAt().AsTime().After(task.GetLease().GetExpiresAt().AsTime()) {
+		t.Fatalf("expected heartbeat to extend the lease, got %v", lease)
+	}
+
+	result, _ := structpb.NewStruct(map[string]any{"rows": 3})
+	done, err := client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: lease.GetLeaseId(), Result: result})
+	if err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if done.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED || done.GetResult().GetFields()["rows"].GetNumberValue() != 3 {
+		t.Fatalf("unexpected completed task: %v", done)
+	}
+	failed, err := client.FailTask(ctxWithAuth("devtoken"), &taskv1.FailTaskRequest{TaskId: ids[1], WorkerId: "w2", LeaseId: second.GetTask().GetLease().GetLeaseId(), Error: "disk full"})
+	if err != nil {
+		t.Fatalf("FailTask failed: %v", err)
+	}
+	if failed.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || failed.GetError() != "disk full" {
+		t.Fatalf("unexpected failed task: %v", failed)
+	}
+}
+
+func TestTaskService_Leases_StaleWorkerRejected(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "contested"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	stale, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil {
+		t.Fatalf("ClaimTask failed: %v", err)
+	}
+	task_id := stale.GetTask().GetTaskId()
+
+	// Hand the task back as if w1's lease had run out and been reclaimed.
+	svc.mu.Lock()
+	requeued := proto.Clone(svc.taskMap[task_id]).(*taskv1.Task)
+	requeued.Status = taskv1.TaskStatus_TASK_STATUS_PENDING
+	svc.swapTaskLocked(requeued)
+	svc.mu.Unlock()
+	fresh, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w2"})
+	if err != nil || fresh.GetTask().GetTaskId() != task_id {
+		t.Fatalf("expected w2 to claim the task again, got %v, %v", fresh, err)
+	}
+
+	_, err = client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: task_id, WorkerId: "w1", LeaseId: stale.GetTask().GetLease().GetLeaseId()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for the stale worker, got %v", err)
+	}
+	_, err = client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: task_id, WorkerId: "w1", LeaseId: fresh.GetTask().GetLease().GetLeaseId()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for another worker's lease, got %v", err)
+	}
+
+	svc.mu.Lock()
+	expired := proto.Clone(svc.taskMap[task_id]).(*taskv1.Task)
+	expired.Lease.ExpiresAt = expired.Lease.ClaimedAt
+	svc.swapTaskLocked(expired)
+	svc.mu.Unlock()
+	_, err = client.HeartbeatTask(ctxWithAuth("devtoken"), &taskv1.HeartbeatTaskRequest{TaskId: task_id, WorkerId: "w2", LeaseId: fresh.GetTask().GetLease().GetLeaseId()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for an expired lease, got %v", err)
+	}
+}
+
+func TestTaskService_Leases_RenewalSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	task := createAndClaim(t, client)
+	renewed, err := client.HeartbeatTask(ctxWithAuth("devtoken"), &taskv1.HeartbeatTaskRequest{
+		TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId(), LeaseDuration: durationpb.New(10 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("HeartbeatTask failed: %v", err)
+	}
+	cleanup()
+
+	restarted, _ := newStoreServer(t, path)
+	client, cleanup = newBufconnClient(t, withServer(restarted))
+	defer cleanup()
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task.GetTaskId()})
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if !got.GetLease().GetExpiresAt().AsTime().Equal(renewed.GetExpiresAt().AsTime()) {
+		t.Fatalf("expected the renewed expiry %v after a restart, got %v", renewed.GetExpiresAt().AsTime(), got.GetLease().GetExpiresAt().AsTime())
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/eventbus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	logSegmentLines    = 128
+	defaultMaxLogLines = 1024
+	maxLogLineBytes    = 8 << 10
+)
+
+// taskLog keeps the newest lines of a task's output. Lines are stored in
+// fixed-size segments and the oldest segment is dropped whole once the log
+// holds maxLines, rounded up to whole segments.
+type taskLog struct {
+	segments [][]*taskv1.TaskLogLine // oldest first; all but the last are full
+	last     int64                   // sequence of the newest line
+	// changed is closed and replaced whenever a line is added.
+	changed chan struct{}
+}
+
+func (l *taskLog) add(line *taskv1.TaskLogLine, maxLines int) {
+	if n := len(l.segments); n == 0 || len(l.segments[n-1]) == logSegmentLines {
+		if n > 0 && n*logSegmentLines >= maxLines {
+			l.segments = slices.Delete(l.segments, 0, 1)
+		}
+		l.segments = append(l.segments, make([]*taskv1.TaskLogLine, 0, logSegmentLines))
+	}
+	n := len(l.segments) - 1
+	l.segments[n] = append(l.segments[n], line)
+	l.last = line.GetSequence()
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// linesAfter returns the retained lines whose sequence is greater than after.
+func (l *taskLog) linesAfter(after int64) []*taskv1.TaskLogLine {
+	if l == nil {
+		return nil
+	}
+	var lines []*taskv1.TaskLogLine
+	for _, seg := range l.segments {
+		if seg[len(seg)-1].GetSequence() <= after {
+			continue
+		}
+		lines = append(lines, seg[max(after+1-seg[0].GetSequence(), 0):]...)
+	}
+	return lines
+}
+
+// logLocked returns the log of a task, creating an empty one. Only writers
+// create logs; readers look them up in s.logs. s.mu must be held for
+// writing.
+func (s *TaskServiceServer) logLocked(task_id string) *taskLog {
+	l, ok := s.logs[task_id]
+	if !ok {
+		l = &taskLog{changed: make(chan struct{})}
+		s.logs[task_id] = l
+		close(s.logsCreated)
+		s.logsCreated = make(chan struct{})
+	}
+	return l
+}
+
+// putLogLineLocked stores a line without journaling it. s.mu must be held.
+func (s *TaskServiceServer) putLogLineLocked(line *taskv1.TaskLogLine) {
+	s.logLocked(line.GetTaskId()).add(line, s.maxLogLines)
+}
+
+// logLineText drops the line terminator and cuts overlong lines at a rune
+// boundary.
+func logLineText(text string) string {
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+	if len(text) <= maxLogLineBytes {
+		return text
+	}
+	i := maxLogLineBytes
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return text[:i]
+}
+
+// AppendTaskLogs stores output of the attempt the caller holds the lease of.
+// Every message is checked against the lease, so a worker whose lease is
+// lost mid-stream gets FailedPrecondition.
+func (s *TaskServiceServer) AppendTaskLogs(stream taskv1.TaskService_AppendTaskLogsServer) error {
+	var task_id, worker_id, lease_id string
+	res := &taskv1.AppendTaskLogsResponse{}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if task_id == "" {
+			task_id, worker_id, lease_id = strings.TrimSpace(req.GetTaskId()), req.GetWorkerId(), req.GetLeaseId()
+			if task_id == "" {
+				return status.Error(codes.InvalidArgument, "task_id is required")
+			}
+		} else if (req.GetTaskId() != "" && strings.TrimSpace(req.GetTaskId()) != task_id) || (req.GetWorkerId() != "" && req.GetWorkerId() != worker_id) || (req.GetLeaseId() != "" && req.GetLeaseId() != lease_id) {
+			return status.Error(codes.InvalidArgument, "a stream may only append to the task and lease of its first message")
+		}
+		if err := s.appendTaskLogs(task_id, worker_id, lease_id, req.GetLines(), res); err != nil {
+			return err
+		}
+	}
+	if task_id == "" {
+		return status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	return stream.SendAndClose(res)
+}
+
+func (s *TaskServiceServer) appendTaskLogs(task_id, worker_id, lease_id string, texts []string, res *taskv1.AppendTaskLogsResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.leasedTaskLocked(task_id, worker_id, lease_id)
+	if err != nil {
+		return err
+	}
+	l := s.logLocked(task_id)
+	now := timestamppb.New(time.Now())
+	lines := make([]*taskv1.TaskLogLine, len(texts))
+	msgs := make([]proto.Message, len(texts))
+	for i, text := range texts {
+		lines[i] = &taskv1.TaskLogLine{
+			TaskId:   task_id,
+			Sequence: l.last + int64(i) + 1,
+			At:       now,
+			Attempt:  task.GetAttempt(),
+			Text:     logLineText(text),
+		}
+		msgs[i] = lines[i]
+	}
+	if err := s.appendLocked(recordLogLine, msgs...); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		l.add(line, s.maxLogLines)
+	}
+	res.LinesAppended += int64(len(lines))
+	res.LastSequence = l.last
+	return nil
+}
+
+// TailTaskLogs sends the retained lines of a task's output, the last
+// tail_lines of them if set. With follow it then sends new lines as they are
+// appended and finishes once the task reaches a terminal status.
+func (s *TaskServiceServer) TailTaskLogs(req *taskv1.TailTaskLogsRequest, stream taskv1.TaskService_TailTaskLogsServer) error {
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	if req.GetTailLines() < 0 {
+		return status.Error(codes.InvalidArgument, "tail_lines must not be negative")
+	}
+	ctx := stream.Context()
+	s.mu.RLock()
+	if _, ok := s.taskMap[task_id]; !ok {
+		s.mu.RUnlock()
+		return status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	var after int64
+	if l, n := s.logs[task_id], req.GetTailLines(); l != nil && n > 0 {
+		after = max(l.last-int64(n), 0)
+	}
+	// Subscribing under the lock means the task cannot finish unnoticed
+	// between reading its status and waiting.
+	var sub *eventbus.Subscription
+	if req.GetFollow() {
+		sub = s.bus.Subscribe(eventbus.Options{
+			Name:   "TailTaskLogs " + task_id + " by " + principalFromContext(ctx),
+			Buffer: 1,
+			Policy: eventbus.DropOldest,
+			Filter: eventbus.ForTask(task_id),
+		})
+		defer sub.Close()
+	}
+	s.mu.RUnlock()
+
+	for {
+		s.mu.RLock()
+		// A task that has not logged yet has no log; wait for one to be
+		// created instead.
+		l := s.logs[task_id]
+		lines := l.linesAfter(after)
+		changed := s.logsCreated
+		if l != nil {
+			changed = l.changed
+		}
+		done := !req.GetFollow() || isTerminal(s.taskMap[task_id].GetStatus())
+		s.mu.RUnlock()
+		for _, line := range lines {
+			if err := stream.Send(line); err != nil {
+				return err
+			}
+			after = line.GetSequence()
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-sub.Ready():
+			sub.Drain()
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// createAndClaim creates a task and claims it as w1.
+func createAndClaim(t *testing.T, client taskv1.TaskServiceClient) *taskv1.Task {
+	t.Helper()
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "noisy"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	claimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil || claimed.GetTask() == nil {
+		t.Fatalf("ClaimTask failed: %v, %v", claimed, err)
+	}
+	return claimed.GetTask()
+}
+
+func appendLogs(client taskv1.TaskServiceClient, task *taskv1.Task, batches ...[]string) (*taskv1.AppendTaskLogsResponse, error) {
+	stream, err := client.AppendTaskLogs(ctxWithAuth("devtoken"))
+	if err != nil {
+		return nil, err
+	}
+	for i, lines := range batches {
+		req := &taskv1.AppendTaskLogsRequest{Lines: lines}
+		if i == 0 {
+			req.TaskId, req.WorkerId, req.LeaseId = task.GetTaskId(), "w1", task.GetLease().GetLeaseId()
+		}
+		if err := stream.Send(req); err != nil {
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+func tailLogs(t *testing.T, client taskv1.TaskServiceClient, req *taskv1.TailTaskLogsRequest) []string {
+	t.Helper()
+	stream, err := client.TailTaskLogs(ctxWithAuth("devtoken"), req)
+	if err != nil {
+		t.Fatalf("TailTaskLogs failed: %v", err)
+	}
+	var texts []string
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return texts
+		}
+		if err != nil {
+			t.Fatalf("TailTaskLogs failed: %v", err)
+		}
+		texts = append(texts, line.GetText())
+	}
+}
+
+func TestTaskService_Logs_AppendAndTail(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	res, err := appendLogs(client, task, []string{"one\n", "two"}, []string{"three\r\n"})
+	if err != nil {
+		t.Fatalf("AppendTaskLogs failed: %v", err)
+	}
+	if res.GetLinesAppended() != 3 || res.GetLastSequence() != 3 {
+		t.Fatalf("expected 3 lines appended, got %v", res)
+	}
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId()}); strings.Join(got, ",") != "one,two,three" {
+		t.Fatalf("expected every line from the start, got %q", got)
+	}
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId(), TailLines: 2}); strings.Join(got, ",") != "two,three" {
+		t.Fatalf("expected the last 2 lines, got %q", got)
+	}
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId(), TailLines: 10}); len(got) != 3 {
+		t.Fatalf("expected every line when tail_lines exceeds them, got %q", got)
+	}
+}
+
+func TestTaskService_Logs_RequiresLease(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	stale := &taskv1.Task{TaskId: task.GetTaskId(), Lease: &taskv1.Lease{LeaseId: "not-the-lease"}}
+	if _, err := appendLogs(client, stale, []string{"x"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition without the lease, got %v", err)
+	}
+	if _, err := appendLogs(client, &taskv1.Task{}, []string{"x"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a task_id, got %v", err)
+	}
+	stream, err := client.TailTaskLogs(ctxWithAuth("devtoken"), &taskv1.TailTaskLogsRequest{TaskId: "missing"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound tailing an unknown task, got %v", err)
+	}
+}
+
+func TestTaskService_Logs_FollowUntilFinished(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	stream, err := client.TailTaskLogs(ctxWithAuth("devtoken"), &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId(), Follow: true})
+	if err != nil {
+		t.Fatalf("TailTaskLogs failed: %v", err)
+	}
+	if _, err := appendLogs(client, task, []string{"starting"}); err != nil {
+		t.Fatalf("AppendTaskLogs failed: %v", err)
+	}
+	line, err := stream.Recv()
+	if err != nil || line.GetText() != "starting" || line.GetSequence() != 1 || line.GetAttempt() != 1 {
+		t.Fatalf("expected the appended line, got %v, %v", line, err)
+	}
+	if _, err := appendLogs(client, task, []string{"done"}); err != nil {
+		t.Fatalf("AppendTaskLogs failed: %v", err)
+	}
+	if _, err := client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId()}); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	line, err = stream.Recv()
+	if err != nil || line.GetText() != "done" {
+		t.Fatalf("expected the last line, got %v, %v", line, err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected the stream to end with the task, got %v", err)
+	}
+}
+
+func TestTaskService_Logs_BoundedSegments(t *testing.T) {
+	svc := NewTaskServiceServer()
+	svc.maxLogLines = 3 * logSegmentLines
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	total := svc.maxLogLines + 1
+	lines := make([]string, total)
+	for i := range lines {
+		lines[i] = strconv.Itoa(i + 1)
+	}
+	lines[total-1] = strings.Repeat("é", maxLogLineBytes)
+	if _, err := appendLogs(client, task, lines); err != nil {
+		t.Fatalf("AppendTaskLogs failed: %v", err)
+	}
+	got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId()})
+	if len(got) != 2*logSegmentLines+1 || got[0] != strconv.Itoa(logSegmentLines+1) {
+		t.Fatalf("expected the oldest segment to be dropped, got %d lines starting at %q", len(got), got[0])
+	}
+	if last := got[len(got)-1]; len(last) != maxLogLineBytes || strings.Trim(last, "é") != "" {
+		t.Fatalf("expected the long line cut to %d bytes of whole runes, got %d bytes", maxLogLineBytes, len(last))
+	}
+}
+
+func TestTaskService_Logs_TailDoesNotCreateLog(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId(), TailLines: 5}); len(got) != 0 {
+		t.Fatalf("expected no lines, got %q", got)
+	}
+	svc.mu.RLock()
+	_, ok := svc.logs[task.GetTaskId()]
+	svc.mu.RUnlock()
+	if ok {
+		t.Fatalf("expected tailing a task that never logged not to create its log")
+	}
+}
+
+func TestTaskService_Logs_SurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	task := createAndClaim(t, client)
+	if _, err := appendLogs(client, task, []string{"before"}); err != nil {
+		t.Fatalf("AppendTaskLogs failed: %v", err)
+	}
+	cleanup()
+
+	restarted, _ := newStoreServer(t, path)
+	client, cleanup = newBufconnClient(t, withServer(restarted))
+	defer cleanup()
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId()}); strings.Join(got, ",") != "before" {
+		t.Fatalf("expected the logged line after a restart, got %q", got)
+	}
+	if _, err := appendLogs(client, task, []string{"after"}); err != nil {
+		t.Fatalf("AppendTaskLogs failed: %v", err)
+	}
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: task.GetTaskId(), TailLines: 1}); strings.Join(got, ",") != "after" {
+		t.Fatalf("expected numbering to resume after a restart, got %q", got)
+	}
+}
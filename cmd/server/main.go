@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,11 +15,17 @@ import (
 
 	hellov1 "grpc-lab/gen/hello/v1"
 	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/blobstore"
+	"grpc-lab/internal/cdc"
+	"grpc-lab/internal/eventbus"
+	"grpc-lab/internal/journal"
+	"grpc-lab/internal/search"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -52,6 +61,66 @@ type TaskServiceServer struct {
 	taskMap   map[string]*taskv1.Task
 	taskSlice []*taskv1.Task
 	failNext  bool
+	comments  map[string][]*taskv1.Comment
+
+	bus         *eventbus.Bus
+	watchBuffer int
+	watchPolicy eventbus.Policy
+
+	blobs       *blobstore.Store
+	attachments map[string][]*taskv1.Attachment
+
+	maxPayloadBytes int
+
+	index *search.Index
+	stats *statsTracker
+
+	webhooks           []*taskv1.Webhook
+	deliveries         []*taskv1.WebhookDelivery
+	webhookClient      *http.Client
+	webhookMaxAttempts int
+	webhookBackoff     time.Duration
+
+	// journal is nil when nothing is persisted.
+	journal *journal.Log
+	// compactBytes is the least journal size runCompactor rewrites it at;
+	// compactAt is the size the next rewrite happens at.
+	compactBytes int64
+	compactAt    int64
+	compactWake  chan struct{}
+	outbox       []*taskv1.TaskEvent
+	relayWake    chan struct{}
+	// inflight holds the events with unfinished webhook deliveries.
+	inflight map[string]*inflightEvent
+
+	// events is the log served by ListEvents, ordered by revision.
+	events         []*taskv1.TaskEvent
+	maxEvents      int
+	eventRetention time.Duration
+	cdcWake        chan struct{}
+
+	queues []*taskv1.Queue
+	// queuesChanged is closed and replaced whenever a queue limit changes.
+	queuesChanged chan struct{}
+
+	// delayed holds SCHEDULED tasks by run_at for runDelayed.
+	delayed   delayHeap
+	delayWake chan struct{}
+
+	schedules    []*taskv1.Schedule
+	scheduleWake chan struct{}
+
+	logs map[string]*taskLog
+	// logsCreated is closed and replaced whenever a task's log is created.
+	logsCreated chan struct{}
+	// maxLogLines is how many lines of each task's output are kept.
+	maxLogLines int
+	// progressPublished is when each task's latest progress event was
+	// published.
+	progressPublished map[string]time.Time
+	// progressHeld publishes the latest report of each task that came too
+	// soon after the previous event, once progressInterval is over.
+	progressHeld map[string]*time.Timer
 }
 
 func (s *TaskServiceServer) FailNextUnavailable() {
@@ -62,9 +131,179 @@ func (s *TaskServiceServer) FailNextUnavailable() {
 }
 
 func NewTaskServiceServer() *TaskServiceServer {
-	return &TaskServiceServer{
+	s := &TaskServiceServer{
 		taskMap:   make(map[string]*taskv1.Task),
 		taskSlice: make([]*taskv1.Task, 0),
+		comments:  make(map[string][]*taskv1.Comment),
+
+		bus:         eventbus.New(),
+		watchBuffer: eventbus.DefaultBuffer,
+		watchPolicy: eventbus.DropOldest,
+
+		attachments: make(map[string][]*taskv1.Attachment),
+
+		maxPayloadBytes: defaultMaxPayloadBytes,
+
+		index: search.New(),
+		stats: newStatsTracker(time.Now),
+
+		webhookClient:      newWebhookClient(),
+		webhookMaxAttempts: defaultWebhookAttempts,
+		webhookBackoff:     defaultWebhookBackoff,
+
+		compactBytes: defaultCompactBytes,
+		compactWake:  make(chan struct{}, 1),
+		relayWake:    make(chan struct{}, 1),
+		inflight:     make(map[string]*inflightEvent),
+
+		maxEvents:      defaultMaxEvents,
+		eventRetention: defaultEventRetention,
+		cdcWake:        make(chan struct{}, 1),
+
+		queues:        []*taskv1.Queue{{Name: defaultQueue, CreatedAt: timestamppb.New(time.Now())}},
+		queuesChanged: make(chan struct{}),
+
+		delayWake: make(chan struct{}, 1),
+
+		scheduleWake: make(chan struct{}, 1),
+
+		logs:              make(map[string]*taskLog),
+		logsCreated:       make(chan struct{}),
+		maxLogLines:       defaultMaxLogLines,
+		progressPublished: make(map[string]time.Time),
+		progressHeld:      make(map[string]*time.Timer),
+	}
+	go s.runRelay()
+	go s.runCompactor()
+	go s.runDelayed()
+	go s.runSchedules()
+	go s.runReaper()
+	return s
+}
+
+const maxLabels = 32
+
+func validateLabels(labels map[string]string) error {
+	if len(labels) > maxLabels {
+		return status.Error(codes.InvalidArgument, "at most "+strconv.Itoa(maxLabels)+" labels are allowed")
+	}
+	for k := range labels {
+		if strings.TrimSpace(k) == "" || strings.Contains(k, "=") {
+			return status.Error(codes.InvalidArgument, "invalid label key "+strconv.Quote(k))
+		}
+	}
+	return nil
+}
+
+// executionOptions is implemented by the task creation requests.
+type executionOptions interface {
+	GetRunAt() *timestamppb.Timestamp
+	GetRetryPolicy() *taskv1.RetryPolicy
+	GetExecutionTimeout() *durationpb.Duration
+}
+
+// applyExecutionOptions validates when and how a new task runs and sets it
+// on task.
+func applyExecutionOptions(task *taskv1.Task, req executionOptions) error {
+	if err := validateRetryPolicy(req.GetRetryPolicy()); err != nil {
+		return err
+	}
+	if err := validateExecutionTimeout(req.GetExecutionTimeout()); err != nil {
+		return err
+	}
+	if err := applyRunAt(task, req.GetRunAt()); err != nil {
+		return err
+	}
+	task.RetryPolicy = req.GetRetryPolicy()
+	task.ExecutionTimeout = req.GetExecutionTimeout()
+	return nil
+}
+
+// checkParentLocked verifies that a non-empty parent_id names an existing
+// task. s.mu must be held.
+func (s *TaskServiceServer) checkParentLocked(parent_id string) error {
+	if parent_id == "" {
+		return nil
+	}
+	if _, ok := s.taskMap[parent_id]; !ok {
+		return status.Error(codes.NotFound, "parent task not found with id "+parent_id)
+	}
+	return nil
+}
+
+// insertTaskLocked stores a new task. Nothing changes if the change cannot
+// be made durable. s.mu must be held.
+func (s *TaskServiceServer) insertTaskLocked(ctx context.Context, task *taskv1.Task) error {
+	err := s.publishLocked(ctx, &taskv1.TaskEvent{
+		TaskId:         task.TaskId,
+		Type:           taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED,
+		Status:         task.Status,
+		PreviousStatus: task.Status,
+		At:             task.CreatedAt,
+		Task:           task,
+	})
+	if err != nil {
+		return err
+	}
+	s.taskMap[task.TaskId] = task
+	s.taskSlice = append(s.taskSlice, task)
+	s.index.Put(task.TaskId, searchFields(task)...)
+	s.stats.observe(nil, task)
+	if task.Status == taskv1.TaskStatus_TASK_STATUS_SCHEDULED {
+		s.delayLocked(task)
+	}
+	return nil
+}
+
+// replaceTaskLocked swaps in a new version of an existing task. Tasks are
+// never modified in place because responses may still reference the old
+// version. s.mu must be held.
+func (s *TaskServiceServer) replaceTaskLocked(ctx context.Context, task *taskv1.Task) error {
+	return s.replaceTaskWithMessageLocked(ctx, task, "")
+}
+
+// replaceTaskWithMessageLocked is replaceTaskLocked with a message on the
+// published event explaining the change. s.mu must be held.
+func (s *TaskServiceServer) replaceTaskWithMessageLocked(ctx context.Context, task *taskv1.Task, message string) error {
+	old := s.taskMap[task.TaskId]
+	if old.GetStatus() == taskv1.TaskStatus_TASK_STATUS_RUNNING && task.Status != taskv1.TaskStatus_TASK_STATUS_RUNNING {
+		// Watchers see the final progress of the attempt before it ends.
+		// The next attempt starts rate limiting afresh.
+		s.flushProgressLocked(ctx, task.TaskId)
+		delete(s.progressPublished, task.TaskId)
+	}
+	typ := taskv1.TaskEventType_TASK_EVENT_TYPE_UPDATED
+	if old.GetStatus() != task.Status {
+		typ = taskv1.TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED
+	}
+	err := s.publishLocked(ctx, &taskv1.TaskEvent{
+		TaskId:         task.TaskId,
+		Type:           typ,
+		Status:         task.Status,
+		PreviousStatus: old.GetStatus(),
+		At:             task.UpdatedAt,
+		Task:           task,
+		Message:        message,
+	})
+	if err != nil {
+		return err
+	}
+	s.stats.observe(old, task)
+	s.swapTaskLocked(task)
+	s.index.Put(task.TaskId, searchFields(task)...)
+	return nil
+}
+
+// swapTaskLocked replaces the stored version of a task without publishing
+// anything, for bookkeeping such as lease renewals that is not worth an
+// event. s.mu must be held.
+func (s *TaskServiceServer) swapTaskLocked(task *taskv1.Task) {
+	s.taskMap[task.TaskId] = task
+	for i, t := range s.taskSlice {
+		if t.TaskId == task.TaskId {
+			s.taskSlice[i] = task
+			break
+		}
 	}
 }
 
@@ -80,6 +319,13 @@ func (s *TaskServiceServer) CreateTask(ctx context.Context, req *taskv1.CreateTa
 	if title == "" {
 		return nil, status.Error(codes.InvalidArgument, "title is required")
 	}
+	if err := s.checkPayload("input", req.GetInput()); err != nil {
+		return nil, err
+	}
+	if err := validateLabels(req.GetLabels()); err != nil {
+		return nil, err
+	}
+	parent_id := strings.TrimSpace(req.GetParentId())
 	id := uuid.New()
 	now := timestamppb.New(time.Now())
 	task := &taskv1.Task{
@@ -89,11 +335,24 @@ func (s *TaskServiceServer) CreateTask(ctx context.Context, req *taskv1.CreateTa
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		Status:      taskv1.TaskStatus_TASK_STATUS_PENDING,
+		Input:       req.GetInput(),
+		Labels:      req.GetLabels(),
+		ParentId:    parent_id,
+	}
+	if err := applyExecutionOptions(task, req); err != nil {
+		return nil, err
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.taskMap[task.TaskId] = task
-	s.taskSlice = append(s.taskSlice, task)
+	if err := s.checkParentLocked(parent_id); err != nil {
+		return nil, err
+	}
+	if task.Queue, err = s.checkQueueLocked(req.GetQueue()); err != nil {
+		return nil, err
+	}
+	if err := s.insertTaskLocked(ctx, task); err != nil {
+		return nil, err
+	}
 	return &taskv1.CreateTaskResponse{Task: task}, nil
 }
 
@@ -120,11 +379,25 @@ func (s *TaskServiceServer) CreateTaskWithId(ctx context.Context, req *taskv1.Cr
 	if title == "" {
 		return nil, status.Error(codes.InvalidArgument, "title is required")
 	}
+	if err := s.checkPayload("input", req.GetInput()); err != nil {
+		return nil, err
+	}
+	if err := validateLabels(req.GetLabels()); err != nil {
+		return nil, err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if _, exists := s.taskMap[task_id]; exists {
 		return nil, status.Error(codes.AlreadyExists, "task with id "+task_id+" already exists")
 	}
+	parent_id := strings.TrimSpace(req.GetParentId())
+	if err := s.checkParentLocked(parent_id); err != nil {
+		return nil, err
+	}
+	queue, err := s.checkQueueLocked(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
 	now := timestamppb.New(time.Now())
 	task := &taskv1.Task{
 		TaskId:      task_id,
@@ -133,9 +406,17 @@ func (s *TaskServiceServer) CreateTaskWithId(ctx context.Context, req *taskv1.Cr
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		Status:      taskv1.TaskStatus_TASK_STATUS_PENDING,
+		Input:       req.GetInput(),
+		Labels:      req.GetLabels(),
+		ParentId:    parent_id,
+		Queue:       queue,
+	}
+	if err := applyExecutionOptions(task, req); err != nil {
+		return nil, err
+	}
+	if err := s.insertTaskLocked(ctx, task); err != nil {
+		return nil, err
 	}
-	s.taskMap[task.TaskId] = task
-	s.taskSlice = append(s.taskSlice, task)
 	return &taskv1.CreateTaskResponse{Task: task}, nil
 }
 
@@ -169,7 +450,8 @@ func (s *TaskServiceServer) ListTasks(ctx context.Context, req *taskv1.ListTasks
 	res = &taskv1.ListTasksResponse{
 		Tasks: make([]*taskv1.Task, 0),
 	}
-	res.Tasks = s.taskSlice[start:end]
+	// Copy so later replaceTaskLocked calls don't race with marshalling.
+	res.Tasks = append(res.Tasks, s.taskSlice[start:end]...)
 	if end >= len(s.taskSlice) {
 		res.NextPageToken = ""
 	} else {
@@ -179,42 +461,80 @@ func (s *TaskServiceServer) ListTasks(ctx context.Context, req *taskv1.ListTasks
 
 }
 
+// WatchTask sends the task's current state and then every change to it,
+// finishing once the task reaches a terminal status. With since_revision it
+// replays buffered changes instead of sending the current state.
 func (s *TaskServiceServer) WatchTask(req *taskv1.WatchTaskRequest, stream taskv1.TaskService_WatchTaskServer) error {
 	task_id := strings.TrimSpace(req.GetTaskId())
 	if task_id == "" {
 		return status.Error(codes.InvalidArgument, "task_id is required")
 	}
+	if err := validateHeartbeat(req.GetHeartbeatInterval()); err != nil {
+		return err
+	}
+	// Subscribing under the lock means no change can slip in between the
+	// snapshot and the first live event.
 	s.mu.RLock()
-	_, ok := s.taskMap[task_id]
-	s.mu.RUnlock()
+	task, ok := s.taskMap[task_id]
 	if !ok {
+		s.mu.RUnlock()
 		return status.Error(codes.NotFound, "task not found with id "+task_id)
 	}
+	sub, replay, err := s.subscribeSince(req.GetSinceRevision(), "WatchTask "+task_id+" by "+principalFromContext(stream.Context()), eventbus.ForTask(task_id))
+	revision := s.bus.Revision()
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
 
-	for i := 0; i < 3; i++ {
-		select {
-		case <-stream.Context().Done():
-			return stream.Context().Err()
-		default:
+	if req.GetSinceRevision() == 0 {
+		replay = []*taskv1.TaskEvent{{
+			TaskId:         task_id,
+			Type:           taskv1.TaskEventType_TASK_EVENT_TYPE_SNAPSHOT,
+			Status:         task.GetStatus(),
+			PreviousStatus: task.GetStatus(),
+			At:             timestamppb.New(time.Now()),
+			Task:           task,
+			Revision:       revision,
+		}}
+	}
+	for _, ev := range replay {
+		if err := stream.Send(ev); err != nil {
+			return err
 		}
-		time.Sleep(200 * time.Millisecond)
-		status := taskv1.TaskStatus_TASK_STATUS_PENDING
-		switch i {
-		case 1:
-			status = taskv1.TaskStatus_TASK_STATUS_RUNNING
-		case 2:
-			status = taskv1.TaskStatus_TASK_STATUS_COMPLETED
+		if ev.GetTask() != nil && isTerminal(ev.GetTask().GetStatus()) {
+			return nil
 		}
+	}
+	if isTerminal(task.GetStatus()) {
+		return nil
+	}
+	return s.streamEvents(stream.Context(), sub, req.GetHeartbeatInterval(), stream.Send, func(ev *taskv1.TaskEvent) bool {
+		return ev.GetTask() != nil && isTerminal(ev.GetTask().GetStatus())
+	})
+}
 
-		err := stream.Send(&taskv1.TaskEvent{
-			Status: status,
-			At:     timestamppb.New(time.Now()),
-		})
-		if err != nil {
-			return err
-		}
+// subscribeSince subscribes to the bus with the server's slow-consumer
+// settings, replaying history after revision when it is non-zero.
+func (s *TaskServiceServer) subscribeSince(revision int64, name string, filter func(*taskv1.TaskEvent) bool) (*eventbus.Subscription, []*taskv1.TaskEvent, error) {
+	if revision < 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "since_revision must not be negative")
 	}
-	return nil
+	opts := eventbus.Options{Name: name, Buffer: s.watchBuffer, Policy: s.watchPolicy, Filter: filter}
+	if revision == 0 {
+		return s.bus.Subscribe(opts), nil, nil
+	}
+	sub, replay, err := s.bus.SubscribeSince(revision, opts)
+	switch {
+	case errors.Is(err, eventbus.ErrCompacted):
+		return nil, nil, status.Errorf(codes.OutOfRange, "revision %d has been compacted; re-list and watch from the current state", revision)
+	case errors.Is(err, eventbus.ErrFutureRevision):
+		return nil, nil, status.Errorf(codes.OutOfRange, "revision %d is ahead of the server; re-list and watch from the current state", revision)
+	case err != nil:
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	return sub, replay, nil
 }
 
 func (s *TaskServiceServer) BulkCreate(stream taskv1.TaskService_BulkCreateServer) error {
@@ -231,6 +551,12 @@ func (s *TaskServiceServer) BulkCreate(stream taskv1.TaskService_BulkCreateServe
 		if title == "" {
 			return status.Error(codes.InvalidArgument, "title is required")
 		}
+		if err := s.checkPayload("input", req.GetInput()); err != nil {
+			return err
+		}
+		if err := validateLabels(req.GetLabels()); err != nil {
+			return err
+		}
 
 		id := uuid.New()
 		now := timestamppb.New(time.Now())
@@ -241,11 +567,27 @@ func (s *TaskServiceServer) BulkCreate(stream taskv1.TaskService_BulkCreateServe
 			CreatedAt:   now,
 			UpdatedAt:   now,
 			Status:      taskv1.TaskStatus_TASK_STATUS_PENDING,
+			Input:       req.GetInput(),
+			Labels:      req.GetLabels(),
+			ParentId:    strings.TrimSpace(req.GetParentId()),
+		}
+		if err := applyExecutionOptions(task, req); err != nil {
+			return err
 		}
 		s.mu.Lock()
+		if err := s.checkParentLocked(task.ParentId); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		if task.Queue, err = s.checkQueueLocked(req.GetQueue()); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		if err := s.insertTaskLocked(stream.Context(), task); err != nil {
+			s.mu.Unlock()
+			return err
+		}
 		ids = append(ids, task.TaskId)
-		s.taskMap[task.TaskId] = task
-		s.taskSlice = append(s.taskSlice, task)
 		s.mu.Unlock()
 	}
 
@@ -273,17 +615,85 @@ func (s *TaskServiceServer) TaskConsole(stream taskv1.TaskService_TaskConsoleSer
 }
 
 func main() {
+	blobDir := flag.String("blob-dir", "data/blobs", "directory for attachment blobs")
+	maxPayload := flag.Int("max-payload-bytes", defaultMaxPayloadBytes, "maximum encoded size of task input and result")
+	maxLogLines := flag.Int("max-log-lines", defaultMaxLogLines, "lines of output kept per task, rounded up to whole segments")
+	watchBuffer := flag.Int("watch-buffer", eventbus.DefaultBuffer, "events queued per watch stream before the slow-consumer policy applies")
+	watchPolicy := flag.String("watch-policy", eventbus.DropOldest.String(), "slow-consumer policy: drop-oldest, coalesce or disconnect")
+	maxEvents := flag.Int("max-events", defaultMaxEvents, "most events ListEvents keeps, whatever their age")
+	eventRetention := flag.Duration("event-retention", defaultEventRetention, "how long ListEvents keeps events")
+	cdcDir := flag.String("cdc-dir", "", "directory to export task changes to as rotated JSONL files; requires -store")
+	cdcMaxBytes := flag.Int64("cdc-max-bytes", 64<<20, "rotate the CDC file once it reaches this size")
+	cdcMaxAge := flag.Duration("cdc-max-age", time.Hour, "rotate the CDC file once it is this old")
+	httpAddr := flag.String("http-addr", "", "address of the Server-Sent Events bridge for watches, such as :8080; empty disables it")
+	compactBytes := flag.Int64("compact-bytes", defaultCompactBytes, "rewrite the store journal down to the current state once it grows past this size")
+	storePath := flag.String("store", "", "journal file keeping tasks, comments and webhooks across restarts; empty keeps them in memory only")
+	flag.Parse()
 
 	s := NewTaskServiceServer()
+	s.maxPayloadBytes = *maxPayload
+	s.maxLogLines = *maxLogLines
+	s.watchBuffer = *watchBuffer
+	s.maxEvents = *maxEvents
+	s.eventRetention = *eventRetention
+	policy, err := eventbus.ParsePolicy(*watchPolicy)
+	if err != nil {
+		log.Fatalf("watch-policy: %v", err)
+	}
+	s.watchPolicy = policy
+	blobs, err := blobstore.New(*blobDir)
+	if err != nil {
+		log.Fatalf("blob store: %v", err)
+	}
+	s.blobs = blobs
+	s.compactBytes = *compactBytes
+	if *storePath != "" {
+		pending, err := s.openStore(*storePath)
+		if err != nil {
+			log.Fatalf("store: %v", err)
+		}
+		log.Printf("store %s loaded, %d events pending dispatch", *storePath, pending)
+	}
+	if *cdcDir != "" {
+		// Without a store revisions restart from zero and the checkpoint
+		// would skip or repeat records.
+		if *storePath == "" {
+			log.Fatalf("-cdc-dir requires -store")
+		}
+		sink, err := cdc.Open(cdc.Options{Dir: *cdcDir, MaxBytes: *cdcMaxBytes, MaxAge: *cdcMaxAge})
+		if err != nil {
+			log.Fatalf("cdc: %v", err)
+		}
+		log.Printf("cdc export to %s resuming after revision %d", *cdcDir, sink.Checkpoint())
+		interval := time.Minute
+		if *cdcMaxAge > 0 {
+			interval = min(*cdcMaxAge, interval)
+		}
+		go s.runCDC(sink, interval)
+	}
+	s.rebuildSearchIndex()
 
 	lis, err := net.Listen("tcp", ":50051")
 	if err != nil {
 		log.Fatalf("listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(authUnaryInterceptor("devtoken")))
+	users := map[string]string{"devtoken": devUser}
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(authUnaryInterceptorWithUsers(users)),
+		grpc.StreamInterceptor(authStreamInterceptorWithUsers(users)),
+	)
 	taskv1.RegisterTaskServiceServer(grpcServer, s)
 
+	if *httpAddr != "" {
+		go func() {
+			log.Printf("SSE bridge listening on %s", *httpAddr)
+			if err := http.ListenAndServe(*httpAddr, newSSEHandler(s, users)); err != nil {
+				log.Fatalf("http: %v", err)
+			}
+		}()
+	}
+
 	log.Println("gRPC server listening on :50051")
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("serve: %v", err)
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/journal"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Journal record kinds. Every task and comment change is an event record:
+// it is both the durable copy of the change and its outbox entry.
+const (
+	// recordEvent holds a TaskEvent to apply on load and to dispatch until acknowledged.
+	recordEvent = 'e'
+	// recordPending holds a TaskEvent to dispatch until acknowledged whose
+	// change is already part of the state records; compaction writes these.
+	recordPending = 'p'
+	// recordState holds a TaskEvent to apply on load only; compaction writes these.
+	recordState = 's'
+	// recordLog holds an already dispatched TaskEvent kept for ListEvents.
+	recordLog = 'l'
+	// recordAck holds the event_id of an event whose dispatch finished.
+	recordAck            = 'a'
+	recordWebhook        = 'w'
+	recordWebhookDeleted = 'd'
+	recordQueue          = 'q'
+	// recordSchedule holds the latest version of a Schedule.
+	recordSchedule        = 'r'
+	recordScheduleDeleted = 'x'
+	// recordLogLine holds a TaskLogLine.
+	recordLogLine = 'o'
+	// recordTask holds the latest version of a task changed without an
+	// event, such as a renewed lease.
+	recordTask = 't'
+	// recordAttachment holds an Attachment whose blob is committed.
+	recordAttachment = 'f'
+)
+
+// defaultCompactBytes is the journal size past which it is first rewritten
+// while the server runs. Later rewrites wait for it to double.
+const defaultCompactBytes = 64 << 20
+
+// inflightEvent is an event whose webhook deliveries have not all finished.
+type inflightEvent struct {
+	ev         *taskv1.TaskEvent
+	deliveries int
+}
+
+// saveTaskLocked makes task durable and swaps it in without publishing an
+// event. s.mu must be held.
+func (s *TaskServiceServer) saveTaskLocked(task *taskv1.Task) error {
+	if err := s.appendLocked(recordTask, task); err != nil {
+		return err
+	}
+	s.swapTaskLocked(task)
+	return nil
+}
+
+// publishLocked is the single place task events leave the server. The event
+// is written to the journal before anything else sees it, so a change that
+// cannot be made durable fails without side effects. Holding s.mu while
+// publishing keeps events in mutation order. s.mu must be held.
+//
+// The price is that every mutation waits for a disk sync with s.mu held, so
+// a store serializes all changes behind its fsync latency; readers queue up
+// behind a writer that is syncing as well.
+func (s *TaskServiceServer) publishLocked(ctx context.Context, ev *taskv1.TaskEvent) error {
+	ev.EventId = uuid.New().String()
+	ev.Actor = principalFromContext(ctx)
+	// Only publishLocked publishes, so this is the revision Publish will
+	// assign; setting it now makes it part of the journaled event.
+	ev.Revision = s.bus.Revision() + 1
+	if err := s.appendLocked(recordEvent, ev); err != nil {
+		return err
+	}
+	s.bus.Publish(ev)
+	s.logEventLocked(ev)
+	s.outbox = append(s.outbox, ev)
+	select {
+	case s.relayWake <- struct{}{}:
+	default:
+	}
+	if ev.GetTask().GetLabels()[scheduleLabel] != "" && isTerminal(ev.GetStatus()) {
+		// A run queued behind this task may start now.
+		s.wakeSchedulesLocked()
+	}
+	return nil
+}
+
+// appendLocked writes msgs to the journal in one go, if the server has one.
+// s.mu must be held.
+func (s *TaskServiceServer) appendLocked(kind byte, msgs ...proto.Message) error {
+	if s.journal == nil {
+		return nil
+	}
+	recs := make([]journal.Record, 0, len(msgs))
+	for _, m := range msgs {
+		data, err := proto.Marshal(m)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		recs = append(recs, journal.Record{Kind: kind, Data: data})
+	}
+	if err := s.journal.Append(recs...); err != nil {
+		return status.Error(codes.Internal, "store write failed: "+err.Error())
+	}
+	if s.journal.Size() >= s.compactAt {
+		// Not here: the caller has yet to apply the change it journaled.
+		select {
+		case s.compactWake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// runCompactor rewrites the journal down to the current state whenever it
+// outgrows compactAt.
+func (s *TaskServiceServer) runCompactor() {
+	for range s.compactWake {
+		s.mu.Lock()
+		s.compactJournalLocked()
+		s.mu.Unlock()
+	}
+}
+
+// compactJournalLocked rewrites the journal down to the current state.
+// Events the relay has not handed out yet or whose deliveries are still
+// running stay pending. s.mu must be held.
+func (s *TaskServiceServer) compactJournalLocked() {
+	if s.journal == nil || s.journal.Size() < s.compactAt {
+		return
+	}
+	pending := slices.Clone(s.outbox)
+	for _, f := range s.inflight {
+		pending = append(pending, f.ev)
+	}
+	slices.SortFunc(pending, func(a, b *taskv1.TaskEvent) int { return cmp.Compare(a.GetRevision(), b.GetRevision()) })
+	recs, err := s.compactLocked(pending)
+	if err == nil {
+		err = s.journal.Rewrite(recs)
+	}
+	if err != nil {
+		// The journal is still whole; try again once it has doubled.
+		log.Printf("store: compaction failed: %v", err)
+	}
+	s.compactAt = max(s.compactBytes, 2*s.journal.Size())
+}
+
+// runRelay hands outbox events to webhooks in order. An event is only
+// acknowledged once every delivery started for it has finished, so events
+// interrupted by a crash are dispatched again after a restart. Events no
+// webhook wants are acknowledged together, after s.mu is released.
+func (s *TaskServiceServer) runRelay() {
+	for range s.relayWake {
+		var acks []journal.Record
+		s.mu.Lock()
+		for _, ev := range s.outbox {
+			if n := s.notifyWebhooksLocked(ev); n > 0 {
+				s.inflight[ev.GetEventId()] = &inflightEvent{ev: ev, deliveries: n}
+			} else {
+				acks = append(acks, journal.Record{Kind: recordAck, Data: []byte(ev.GetEventId())})
+			}
+		}
+		s.outbox = nil
+		j := s.journal
+		s.mu.Unlock()
+		if j == nil || len(acks) == 0 {
+			continue
+		}
+		// A lost ack only means the event is dispatched again.
+		if err := j.Append(acks...); err != nil {
+			log.Printf("outbox: ack %d events: %v", len(acks), err)
+		}
+	}
+}
+
+// settleLocked records that one delivery of an event finished. s.mu must be
+// held.
+func (s *TaskServiceServer) settleLocked(event_id string) {
+	if event_id == "" {
+		return
+	}
+	if f, ok := s.inflight[event_id]; ok {
+		if f.deliveries--; f.deliveries > 0 {
+			return
+		}
+	}
+	delete(s.inflight, event_id)
+	s.ackLocked(event_id)
+}
+
+func (s *TaskServiceServer) ackLocked(event_id string) {
+	if s.journal == nil {
+		return
+	}
+	// A lost ack only means the event is dispatched again.
+	if err := s.journal.Append(journal.Record{Kind: recordAck, Data: []byte(event_id)}); err != nil {
+		log.Printf("outbox: ack %s: %v", event_id, err)
+	}
+}
+
+// openStore loads the journal at path and makes every later change durable
+// in it. Events that were never acknowledged go back to the relay; their
+// number is returned.
+func (s *TaskServiceServer) openStore(path string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pending []*taskv1.TaskEvent
+	var revision int64
+	acked := make(map[string]bool)
+	var last byte
+	j, err := journal.Open(path, func(rec journal.Record) error {
+		defer func() { last = rec.Kind }()
+		switch rec.Kind {
+		case recordEvent, recordState:
+			ev := &taskv1.TaskEvent{}
+			if err := proto.Unmarshal(rec.Data, ev); err != nil {
+				return err
+			}
+			if rec.Kind == recordState && last != recordState {
+				// Compaction writes the state records together and they
+				// hold every task, so they replace whatever came before.
+				s.resetTasksLocked()
+			}
+			s.applyEventLocked(ev)
+			if rec.Kind == recordEvent {
+				pending = append(pending, ev)
+				s.events = append(s.events, ev)
+			}
+			revision = max(revision, ev.GetRevision())
+		case recordPending:
+			ev := &taskv1.TaskEvent{}
+			if err := proto.Unmarshal(rec.Data, ev); err != nil {
+				return err
+			}
+			pending = append(pending, ev)
+			s.events = append(s.events, ev)
+			revision = max(revision, ev.GetRevision())
+		case recordLog:
+			ev := &taskv1.TaskEvent{}
+			if err := proto.Unmarshal(rec.Data, ev); err != nil {
+				return err
+			}
+			s.events = append(s.events, ev)
+			revision = max(revision, ev.GetRevision())
+		case recordAck:
+			acked[string(rec.Data)] = true
+		case recordWebhook:
+			hook := &taskv1.Webhook{}
+			if err := proto.Unmarshal(rec.Data, hook); err != nil {
+				return err
+			}
+			s.webhooks = append(s.webhooks, hook)
+		case recordWebhookDeleted:
+			s.webhooks = slices.DeleteFunc(s.webhooks, func(h *taskv1.Webhook) bool { return h.GetWebhookId() == string(rec.Data) })
+		case recordQueue:
+			q := &taskv1.Queue{}
+			if err := proto.Unmarshal(rec.Data, q); err != nil {
+				return err
+			}
+			s.putQueueLocked(q)
+		case recordSchedule:
+			sched := &taskv1.Schedule{}
+			if err := proto.Unmarshal(rec.Data, sched); err != nil {
+				return err
+			}
+			s.putScheduleLocked(sched)
+		case recordScheduleDeleted:
+			s.schedules = slices.DeleteFunc(s.schedules, func(sched *taskv1.Schedule) bool { return sched.GetScheduleId() == string(rec.Data) })
+		case recordTask:
+			task := &taskv1.Task{}
+			if err := proto.Unmarshal(rec.Data, task); err != nil {
+				return err
+			}
+			s.swapTaskLocked(task)
+		case recordAttachment:
+			a := &taskv1.Attachment{}
+			if err := proto.Unmarshal(rec.Data, a); err != nil {
+				return err
+			}
+			s.attachments[a.GetTaskId()] = append(s.attachments[a.GetTaskId()], a)
+		case recordLogLine:
+			line := &taskv1.TaskLogLine{}
+			if err := proto.Unmarshal(rec.Data, line); err != nil {
+				return err
+			}
+			s.putLogLineLocked(line)
+		default:
+			return fmt.Errorf("unknown journal record kind %q", rec.Kind)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	pending = slices.DeleteFunc(pending, func(ev *taskv1.TaskEvent) bool { return acked[ev.GetEventId()] })
+	for _, task := range s.taskSlice {
+		s.stats.restore(task)
+		if task.GetStatus() == taskv1.TaskStatus_TASK_STATUS_SCHEDULED {
+			// Tasks whose run_at passed while the server was down are
+			// promoted straight away.
+			s.delayLocked(task)
+		}
+	}
+	s.bus.SetRevision(revision)
+	slices.SortStableFunc(s.events, func(a, b *taskv1.TaskEvent) int { return cmp.Compare(a.GetRevision(), b.GetRevision()) })
+	s.pruneEventsLocked()
+
+	recs, err := s.compactLocked(pending)
+	if err == nil {
+		err = j.Rewrite(recs)
+	}
+	if err != nil {
+		j.Close()
+		return 0, err
+	}
+	s.journal = j
+	s.compactAt = max(s.compactBytes, 2*j.Size())
+	// Runs that came due while the server was down fire now.
+	s.wakeSchedulesLocked()
+	s.outbox = append(s.outbox, pending...)
+	select {
+	case s.relayWake <- struct{}{}:
+	default:
+	}
+	return len(pending), nil
+}
+
+// resetTasksLocked forgets every task and comment. s.mu must be held.
+func (s *TaskServiceServer) resetTasksLocked() {
+	s.taskMap = make(map[string]*taskv1.Task)
+	s.taskSlice = s.taskSlice[:0]
+	s.comments = make(map[string][]*taskv1.Comment)
+}
+
+// applyEventLocked replays a journaled change. Applying the same event
+// twice is harmless. s.mu must be held.
+func (s *TaskServiceServer) applyEventLocked(ev *taskv1.TaskEvent) {
+	if task := ev.GetTask(); task != nil {
+		if _, ok := s.taskMap[task.GetTaskId()]; ok {
+			i := slices.IndexFunc(s.taskSlice, func(t *taskv1.Task) bool { return t.GetTaskId() == task.GetTaskId() })
+			s.taskSlice[i] = task
+		} else {
+			s.taskSlice = append(s.taskSlice, task)
+		}
+		s.taskMap[task.GetTaskId()] = task
+	}
+	c := ev.GetComment()
+	if c == nil {
+		return
+	}
+	comments := s.comments[c.GetTaskId()]
+	i := slices.IndexFunc(comments, func(old *taskv1.Comment) bool { return old.GetCommentId() == c.GetCommentId() })
+	switch {
+	case ev.GetType() == taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_DELETED:
+		if i >= 0 {
+			comments = slices.Delete(comments, i, i+1)
+		}
+	case i >= 0:
+		comments[i] = c
+	default:
+		comments = append(comments, c)
+	}
+	s.comments[c.GetTaskId()] = comments
+}
+
+// compactLocked returns journal records equivalent to the current state.
+// Pending events are kept for dispatch only: the state records already hold
+// their changes. State records carry the current revision so numbering
+// resumes after it. s.mu must be held.
+func (s *TaskServiceServer) compactLocked(pending []*taskv1.TaskEvent) ([]journal.Record, error) {
+	var recs []journal.Record
+	add := func(kind byte, m proto.Message) error {
+		data, err := proto.Marshal(m)
+		if err != nil {
+			return err
+		}
+		recs = append(recs, journal.Record{Kind: kind, Data: data})
+		return nil
+	}
+	isPending := make(map[string]bool)
+	for _, ev := range pending {
+		isPending[ev.GetEventId()] = true
+		if err := add(recordPending, ev); err != nil {
+			return nil, err
+		}
+	}
+	for _, ev := range s.events {
+		if isPending[ev.GetEventId()] {
+			continue
+		}
+		if err := add(recordLog, ev); err != nil {
+			return nil, err
+		}
+	}
+	revision := s.bus.Revision()
+	for _, task := range s.taskSlice {
+		if err := add(recordState, &taskv1.TaskEvent{TaskId: task.GetTaskId(), Type: taskv1.TaskEventType_TASK_EVENT_TYPE_SNAPSHOT, Task: task, Revision: revision}); err != nil {
+			return nil, err
+		}
+		for _, c := range s.comments[task.GetTaskId()] {
+			if err := add(recordState, &taskv1.TaskEvent{TaskId: task.GetTaskId(), Type: taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED, Comment: c}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, hook := range s.webhooks {
+		if err := add(recordWebhook, hook); err != nil {
+			return nil, err
+		}
+	}
+	for _, q := range s.queues {
+		if err := add(recordQueue, q); err != nil {
+			return nil, err
+		}
+	}
+	for _, sched := range s.schedules {
+		if err := add(recordSchedule, sched); err != nil {
+			return nil, err
+		}
+	}
+	for _, task := range s.taskSlice {
+		for _, a := range s.attachments[task.GetTaskId()] {
+			if err := add(recordAttachment, a); err != nil {
+				return nil, err
+			}
+		}
+		for _, line := range s.logs[task.GetTaskId()].linesAfter(0) {
+			if err := add(recordLogLine, line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return recs, nil
+}
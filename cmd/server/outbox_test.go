@@ -0,0 +1,288 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/journal"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newStoreServer(t *testing.T, path string) (*TaskServiceServer, int) {
+	t.Helper()
+	svc := NewTaskServiceServer()
+	pending, err := svc.openStore(path)
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	return svc, pending
+}
+
+func TestTaskService_Store_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+
+	first, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "first", Labels: map[string]string{"team": "infra"}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := first.GetTask().GetTaskId()
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "second"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	c, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: "draft"})
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := client.EditComment(ctxWithAuth("devtoken"), &taskv1.EditCommentRequest{TaskId: task_id, CommentId: c.GetCommentId(), Body: "final"}); err != nil {
+		t.Fatalf("EditComment failed: %v", err)
+	}
+	gone, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: "oops"})
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := client.DeleteComment(ctxWithAuth("devtoken"), &taskv1.DeleteCommentRequest{TaskId: task_id, CommentId: gone.GetCommentId()}); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+	result, _ := structpb.NewStruct(map[string]any{"ok": true})
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id, Result: result}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	cleanup()
+
+	restarted, _ := newStoreServer(t, path)
+	client, cleanup = newBufconnClient(t, withServer(restarted))
+	defer cleanup()
+
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("GetTask after restart failed: %v", err)
+	}
+	if got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED || !got.GetResult().GetFields()["ok"].GetBoolValue() || got.GetLabels()["team"] != "infra" {
+		t.Fatalf("unexpected task after restart: %v", got)
+	}
+	list, err := client.ListTasks(ctxWithAuth("devtoken"), &taskv1.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(list.GetTasks()) != 2 || list.GetTasks()[0].GetTitle() != "first" || list.GetTasks()[1].GetTitle() != "second" {
+		t.Fatalf("unexpected tasks after restart: %v", list.GetTasks())
+	}
+	comments, err := client.ListComments(ctxWithAuth("devtoken"), &taskv1.ListCommentsRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("ListComments failed: %v", err)
+	}
+	if len(comments.GetComments()) != 1 || comments.GetComments()[0].GetBody() != "final" {
+		t.Fatalf("unexpected comments after restart: %v", comments.GetComments())
+	}
+	stats, err := client.GetTaskStats(ctxWithAuth("devtoken"), &taskv1.GetTaskStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetTaskStats failed: %v", err)
+	}
+	if stats.GetTotal() != 2 || stats.GetByStatus()["TASK_STATUS_COMPLETED"] != 1 {
+		t.Fatalf("unexpected stats after restart: %v", stats)
+	}
+}
+
+func TestTaskService_Outbox_RedeliversUnacknowledgedEvents(t *testing.T) {
+	var up atomic.Bool
+	payloads := make(chan *taskv1.WebhookPayload, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var p taskv1.WebhookPayload
+		if err := protojson.Unmarshal(body, &p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		payloads <- &p
+	}))
+	defer receiver.Close()
+
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	// The first attempt fails and the retry never comes, as if the server
+	// crashed mid-delivery.
+	svc.webhookBackoff = time.Hour
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	hook, err := client.CreateWebhook(ctxWithAuth("devtoken"), &taskv1.CreateWebhookRequest{
+		Url:        receiver.URL,
+		EventTypes: []taskv1.TaskEventType{taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "must be announced"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		res, err := client.ListWebhookDeliveries(ctxWithAuth("devtoken"), &taskv1.ListWebhookDeliveriesRequest{WebhookId: hook.GetWebhookId()})
+		if err != nil {
+			t.Fatalf("ListWebhookDeliveries failed: %v", err)
+		}
+		if len(res.GetDeliveries()) == 1 && res.GetDeliveries()[0].GetAttempts() == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("first delivery attempt never happened")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cleanup()
+
+	up.Store(true)
+	restarted, pending := newStoreServer(t, path)
+	if pending != 1 {
+		t.Fatalf("expected 1 pending event after restart, got %d", pending)
+	}
+	client, cleanup = newBufconnClient(t, withServer(restarted))
+	p := <-payloads
+	if p.GetEvent().GetTaskId() != created.GetTask().GetTaskId() || p.GetEvent().GetEventId() == "" {
+		t.Fatalf("unexpected redelivered payload: %v", p)
+	}
+	if d := waitForDelivery(t, client, hook.GetWebhookId()); d.GetState() != taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_SUCCEEDED {
+		t.Fatalf("expected SUCCEEDED after restart, got %v", d.GetState())
+	}
+	cleanup()
+
+	if _, pending := newStoreServer(t, path); pending != 0 {
+		t.Fatalf("expected no pending events once delivered, got %d", pending)
+	}
+}
+
+// countAcks replays a copy of the journal at path, so the live one is left
+// alone, and counts its ack records.
+func countAcks(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	copied := filepath.Join(t.TempDir(), "copy")
+	if err := os.WriteFile(copied, data, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	n := 0
+	j, err := journal.Open(copied, func(rec journal.Record) error {
+		if rec.Kind == recordAck {
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("journal.Open failed: %v", err)
+	}
+	j.Close()
+	return n
+}
+
+func TestTaskService_Store_PendingEventsDoNotReviveState(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	svc.webhookBackoff = time.Hour
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	// Only the comment's addition is announced, and that never succeeds,
+	// so it stays pending while the other events are acknowledged.
+	if _, err := client.CreateWebhook(ctxWithAuth("devtoken"), &taskv1.CreateWebhookRequest{
+		Url:        receiver.URL,
+		EventTypes: []taskv1.TaskEventType{taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED},
+	}); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	var ids []string
+	for _, title := range []string{"first", "second"} {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: title})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, created.GetTask().GetTaskId())
+	}
+	comment, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: ids[1], Body: "short-lived"})
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := client.DeleteComment(ctxWithAuth("devtoken"), &taskv1.DeleteCommentRequest{TaskId: ids[1], CommentId: comment.GetCommentId()}); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for countAcks(t, path) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("events other than the comment's addition were never acknowledged")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cleanup()
+
+	// The first restart compacts the journal; the second loads the result.
+	for restart := 1; restart <= 2; restart++ {
+		restarted, pending := newStoreServer(t, path)
+		if pending != 1 {
+			t.Fatalf("restart %d: expected the comment's addition pending, got %d events", restart, pending)
+		}
+		client, cleanup = newBufconnClient(t, withServer(restarted))
+		comments, err := client.ListComments(ctxWithAuth("devtoken"), &taskv1.ListCommentsRequest{TaskId: ids[1]})
+		if err != nil || len(comments.GetComments()) != 0 {
+			t.Fatalf("restart %d: expected the deleted comment to stay deleted, got %v, %v", restart, comments, err)
+		}
+		list, err := client.ListTasks(ctxWithAuth("devtoken"), &taskv1.ListTasksRequest{})
+		if err != nil || len(list.GetTasks()) != 2 || list.GetTasks()[0].GetTaskId() != ids[0] {
+			t.Fatalf("restart %d: expected tasks in creation order, got %v, %v", restart, list, err)
+		}
+		cleanup()
+	}
+}
+
+func TestTaskService_Store_CompactsWhileRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc := NewTaskServiceServer()
+	svc.compactBytes = 4 << 10
+	if _, err := svc.openStore(path); err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	task := createAndClaim(t, client)
+
+	var lease *taskv1.Lease
+	for range 200 {
+		var err error
+		lease, err = client.HeartbeatTask(ctxWithAuth("devtoken"), &taskv1.HeartbeatTaskRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId()})
+		if err != nil {
+			t.Fatalf("HeartbeatTask failed: %v", err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for svc.journal.Size() >= svc.compactBytes {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the journal compacted, still %d bytes", svc.journal.Size())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cleanup()
+	svc.journal.Close()
+
+	restarted, _ := newStoreServer(t, path)
+	got, err := restarted.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task.GetTaskId()})
+	if err != nil || !got.GetLease().GetExpiresAt().AsTime().Equal(lease.GetExpiresAt().AsTime()) {
+		t.Fatalf("expected the last renewal to survive compaction, got %v, %v", got, err)
+	}
+}
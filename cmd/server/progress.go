@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// progressInterval is the least time between two progress events of a
+	// task, unless the step changes.
+	progressInterval   = time.Second
+	maxProgressStep    = 128
+	maxProgressMessage = 1024
+)
+
+func newProgress(percent float64, step, message string) (*taskv1.TaskProgress, error) {
+	if math.IsNaN(percent) || percent < 0 || percent > 100 {
+		return nil, status.Error(codes.InvalidArgument, "percent must be between 0 and 100")
+	}
+	step = strings.TrimSpace(step)
+	if len(step) > maxProgressStep {
+		return nil, status.Errorf(codes.InvalidArgument, "step must be at most %d bytes", maxProgressStep)
+	}
+	if len(message) > maxProgressMessage {
+		return nil, status.Errorf(codes.InvalidArgument, "message must be at most %d bytes", maxProgressMessage)
+	}
+	return &taskv1.TaskProgress{Percent: percent, Step: step, Message: message}, nil
+}
+
+// ReportProgress records how far the caller has got with the attempt it
+// holds the lease of.
+func (s *TaskServiceServer) ReportProgress(ctx context.Context, req *taskv1.ReportProgressRequest) (*taskv1.Task, error) {
+	p, err := newProgress(req.GetPercent(), req.GetStep(), req.GetMessage())
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.leasedTaskLocked(req.GetTaskId(), req.GetWorkerId(), req.GetLeaseId())
+	if err != nil {
+		return nil, err
+	}
+	return s.reportProgressLocked(ctx, task, p)
+}
+
+// reportProgressLocked stores p as the latest progress of a RUNNING task and
+// publishes it. Reports coming faster than progressInterval for the same
+// step are only journaled; the latest of them is published when the
+// interval is over, or before the attempt ends. s.mu must be held.
+func (s *TaskServiceServer) reportProgressLocked(ctx context.Context, task *taskv1.Task, p *taskv1.TaskProgress) (*taskv1.Task, error) {
+	now := time.Now()
+	p.UpdatedAt = timestamppb.New(now)
+	updated := proto.Clone(task).(*taskv1.Task)
+	updated.Progress = p
+	task_id := task.GetTaskId()
+	last, ok := s.progressPublished[task_id]
+	if ok && now.Sub(last) < progressInterval && p.GetStep() == task.GetProgress().GetStep() {
+		if err := s.saveTaskLocked(updated); err != nil {
+			return nil, err
+		}
+		if _, held := s.progressHeld[task_id]; !held {
+			actor := principalFromContext(ctx)
+			var t *time.Timer
+			t = time.AfterFunc(last.Add(progressInterval).Sub(now), func() {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				// A timer stopped too late may still fire; only the
+				// current one flushes, so a later report is not published
+				// early.
+				if s.progressHeld[task_id] == t {
+					s.flushProgressLocked(context.WithValue(context.Background(), principalKey{}, actor), task_id)
+				}
+			})
+			s.progressHeld[task_id] = t
+		}
+		return updated, nil
+	}
+	if t, held := s.progressHeld[task_id]; held {
+		t.Stop()
+		delete(s.progressHeld, task_id)
+	}
+	if err := s.publishProgressLocked(ctx, updated); err != nil {
+		return nil, err
+	}
+	s.swapTaskLocked(updated)
+	return updated, nil
+}
+
+// flushProgressLocked publishes the progress of a RUNNING task held back by
+// reportProgressLocked, if any. s.mu must be held.
+func (s *TaskServiceServer) flushProgressLocked(ctx context.Context, task_id string) {
+	t, held := s.progressHeld[task_id]
+	if !held {
+		return
+	}
+	t.Stop()
+	delete(s.progressHeld, task_id)
+	task, ok := s.taskMap[task_id]
+	if !ok || task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING {
+		return
+	}
+	if err := s.publishProgressLocked(ctx, task); err != nil {
+		// The progress is journaled already; only its event is lost.
+		log.Printf("progress: task %s: %v", task_id, err)
+	}
+}
+
+// publishProgressLocked publishes the progress of task. s.mu must be held.
+func (s *TaskServiceServer) publishProgressLocked(ctx context.Context, task *taskv1.Task) error {
+	p := task.GetProgress()
+	err := s.publishLocked(ctx, &taskv1.TaskEvent{
+		TaskId:         task.GetTaskId(),
+		Type:           taskv1.TaskEventType_TASK_EVENT_TYPE_PROGRESS,
+		Status:         task.GetStatus(),
+		PreviousStatus: task.GetStatus(),
+		At:             p.GetUpdatedAt(),
+		Task:           task,
+		Message:        p.GetMessage(),
+	})
+	if err != nil {
+		return err
+	}
+	s.progressPublished[task.GetTaskId()] = time.Now()
+	return nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// progressEvents returns the progress events published so far.
+func progressEvents(t *testing.T, client taskv1.TaskServiceClient) []*taskv1.TaskEvent {
+	t.Helper()
+	res, err := client.ListEvents(ctxWithAuth("devtoken"), &taskv1.ListEventsRequest{PageSize: 100})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	var evs []*taskv1.TaskEvent
+	for _, ev := range res.GetEvents() {
+		if ev.GetType() == taskv1.TaskEventType_TASK_EVENT_TYPE_PROGRESS {
+			evs = append(evs, ev)
+		}
+	}
+	return evs
+}
+
+func TestTaskService_ReportProgress_RateLimited(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	report := func(percent float64, step, message string) *taskv1.Task {
+		t.Helper()
+		got, err := client.ReportProgress(ctxWithAuth("devtoken"), &taskv1.ReportProgressRequest{
+			TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId(),
+			Percent: percent, Step: step, Message: message,
+		})
+		if err != nil {
+			t.Fatalf("ReportProgress failed: %v", err)
+		}
+		return got
+	}
+
+	got := report(10, "download", "1 of 10 files")
+	if got.GetProgress().GetPercent() != 10 || got.GetProgress().GetUpdatedAt() == nil {
+		t.Fatalf("expected the progress on the task, got %v", got.GetProgress())
+	}
+	evs := progressEvents(t, client)
+	if len(evs) != 1 || evs[0].GetMessage() != "1 of 10 files" || evs[0].GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING {
+		t.Fatalf("expected one progress event, got %v", evs)
+	}
+
+	report(20, "download", "2 of 10 files")
+	if evs := progressEvents(t, client); len(evs) != 1 {
+		t.Fatalf("expected a report within a second to be stored silently, got %d events", len(evs))
+	}
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task.GetTaskId()})
+	if err != nil || got.GetProgress().GetPercent() != 20 {
+		t.Fatalf("expected the task to carry the latest progress, got %v, %v", got.GetProgress(), err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(progressEvents(t, client)) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the held back report was never published")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if evs := progressEvents(t, client); evs[1].GetTask().GetProgress().GetPercent() != 20 {
+		t.Fatalf("expected the held back report published once the interval was over, got %v", evs[1])
+	}
+
+	report(0, "unpack", "")
+	if evs := progressEvents(t, client); len(evs) != 3 || evs[2].GetTask().GetProgress().GetStep() != "unpack" {
+		t.Fatalf("expected a new step to be published straight away, got %v", evs)
+	}
+
+	// The last report is published before the attempt ends.
+	report(100, "unpack", "done")
+	if _, err := client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId()}); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	res, err := client.ListEvents(ctxWithAuth("devtoken"), &taskv1.ListEventsRequest{PageSize: 100})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	all := res.GetEvents()
+	final, completed := all[len(all)-2], all[len(all)-1]
+	if final.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_PROGRESS || final.GetTask().GetProgress().GetPercent() != 100 || completed.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected the final report published just before the completion, got %v then %v", final, completed)
+	}
+	svc.mu.RLock()
+	_, published := svc.progressPublished[task.GetTaskId()]
+	_, held := svc.progressHeld[task.GetTaskId()]
+	svc.mu.RUnlock()
+	if published || held {
+		t.Fatalf("expected no rate limiting state left for the finished task")
+	}
+}
+
+func TestTaskService_ReportProgress_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	task := createAndClaim(t, client)
+	for _, percent := range []float64{10, 20} {
+		if _, err := client.ReportProgress(ctxWithAuth("devtoken"), &taskv1.ReportProgressRequest{
+			TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId(), Percent: percent,
+		}); err != nil {
+			t.Fatalf("ReportProgress failed: %v", err)
+		}
+	}
+	cleanup()
+
+	restarted, _ := newStoreServer(t, path)
+	client, cleanup = newBufconnClient(t, withServer(restarted))
+	defer cleanup()
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task.GetTaskId()})
+	if err != nil || got.GetProgress().GetPercent() != 20 {
+		t.Fatalf("expected the rate-limited report after a restart, got %v, %v", got.GetProgress(), err)
+	}
+}
+
+func TestTaskService_ReportProgress_Invalid(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	task := createAndClaim(t, client)
+
+	_, err := client.ReportProgress(ctxWithAuth("devtoken"), &taskv1.ReportProgressRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId(), Percent: 150})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for percent over 100, got %v", err)
+	}
+	_, err = client.ReportProgress(ctxWithAuth("devtoken"), &taskv1.ReportProgressRequest{TaskId: task.GetTaskId(), WorkerId: "w2", LeaseId: task.GetLease().GetLeaseId(), Percent: 50})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition without the lease, got %v", err)
+	}
+}
+
+func TestTaskService_WorkerSession_Progress(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "long"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	watch, err := client.WatchTask(ctxWithAuth("devtoken"), &taskv1.WatchTaskRequest{TaskId: created.GetTask().GetTaskId()})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(helloMsg("w1", 1)); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+	task := recvAssignment(t, stream)
+	progress := &taskv1.WorkerProgress{TaskId: task.GetTaskId(), LeaseId: task.GetLease().GetLeaseId(), Percent: 50, Step: "encode", Message: "halfway"}
+	if err := stream.Send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Progress{Progress: progress}}); err != nil {
+		t.Fatalf("Send progress failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		ev, err := watch.Recv()
+		if err != nil {
+			t.Fatalf("WatchTask Recv failed: %v", err)
+		}
+		if ev.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_PROGRESS {
+			continue
+		}
+		if ev.GetMessage() != "halfway" || ev.GetTask().GetProgress().GetPercent() != 50 || ev.GetTask().GetProgress().GetStep() != "encode" {
+			t.Fatalf("expected the reported progress, got %v", ev)
+		}
+		if !ev.GetTask().GetLease().GetExpiresAt().AsTime().After(task.GetLease().GetExpiresAt().AsTime()) {
+			t.Fatalf("expected progress to renew the lease")
+		}
+		return
+	}
+	t.Fatalf("no progress event seen")
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultQueue always exists and holds tasks created without a queue.
+	defaultQueue      = "default"
+	maxQueueNameBytes = 64
+)
+
+func validateQueueName(name string) error {
+	if name == "" || len(name) > maxQueueNameBytes {
+		return status.Error(codes.InvalidArgument, "queue name must be 1 to "+strconv.Itoa(maxQueueNameBytes)+" bytes")
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return status.Error(codes.InvalidArgument, "invalid queue name "+strconv.Quote(name))
+		}
+	}
+	return nil
+}
+
+func validateMaxConcurrency(n int32) error {
+	if n < 0 {
+		return status.Error(codes.InvalidArgument, "max_concurrency must not be negative")
+	}
+	return nil
+}
+
+// taskQueue returns the queue of a task, including tasks stored before
+// tasks had one.
+func taskQueue(task *taskv1.Task) string {
+	if q := task.GetQueue(); q != "" {
+		return q
+	}
+	return defaultQueue
+}
+
+// checkQueueLocked resolves the queue a new task goes to. s.mu must be held.
+func (s *TaskServiceServer) checkQueueLocked(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return defaultQueue, nil
+	}
+	if s.queueLocked(name) == nil {
+		return "", status.Error(codes.NotFound, "queue not found with name "+name)
+	}
+	return name, nil
+}
+
+func (s *TaskServiceServer) queueLocked(name string) *taskv1.Queue {
+	for _, q := range s.queues {
+		if q.GetName() == name {
+			return q
+		}
+	}
+	return nil
+}
+
+func (s *TaskServiceServer) CreateQueue(ctx context.Context, req *taskv1.CreateQueueRequest) (*taskv1.Queue, error) {
+	name := strings.TrimSpace(req.GetName())
+	if err := validateQueueName(name); err != nil {
+		return nil, err
+	}
+	if err := validateMaxConcurrency(req.GetMaxConcurrency()); err != nil {
+		return nil, err
+	}
+	q := &taskv1.Queue{
+		Name:           name,
+		MaxConcurrency: req.GetMaxConcurrency(),
+		CreatedAt:      timestamppb.New(time.Now()),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queueLocked(name) != nil {
+		return nil, status.Error(codes.AlreadyExists, "queue "+name+" already exists")
+	}
+	if err := s.appendLocked(recordQueue, q); err != nil {
+		return nil, err
+	}
+	s.queues = append(s.queues, q)
+	return q, nil
+}
+
+// ListQueues returns every queue, oldest first, with its current counts.
+func (s *TaskServiceServer) ListQueues(ctx context.Context, req *taskv1.ListQueuesRequest) (*taskv1.ListQueuesResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pending := make(map[string]int32)
+	running := make(map[string]int32)
+	for _, task := range s.taskSlice {
+		switch task.GetStatus() {
+		case taskv1.TaskStatus_TASK_STATUS_PENDING:
+			pending[taskQueue(task)]++
+		case taskv1.TaskStatus_TASK_STATUS_RUNNING:
+			running[taskQueue(task)]++
+		}
+	}
+	res := &taskv1.ListQueuesResponse{Queues: []*taskv1.Queue{}}
+	for _, q := range s.queues {
+		counted := proto.Clone(q).(*taskv1.Queue)
+		counted.Pending = pending[q.GetName()]
+		counted.Running = running[q.GetName()]
+		res.Queues = append(res.Queues, counted)
+	}
+	return res, nil
+}
+
+// UpdateQueue changes the concurrency limit of a queue. Lowering it below
+// the number of RUNNING tasks does not revoke any; the queue just gets no
+// more until enough of them finish.
+func (s *TaskServiceServer) UpdateQueue(ctx context.Context, req *taskv1.UpdateQueueRequest) (*taskv1.Queue, error) {
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if err := validateMaxConcurrency(req.GetMaxConcurrency()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.queues, func(q *taskv1.Queue) bool { return q.GetName() == name })
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "queue not found with name "+name)
+	}
+	updated := proto.Clone(s.queues[i]).(*taskv1.Queue)
+	updated.MaxConcurrency = req.GetMaxConcurrency()
+	if err := s.appendLocked(recordQueue, updated); err != nil {
+		return nil, err
+	}
+	s.queues[i] = updated
+	// Wake worker sessions: a raised limit may let them claim more.
+	close(s.queuesChanged)
+	s.queuesChanged = make(chan struct{})
+	return updated, nil
+}
+
+// putQueueLocked adds or replaces a queue loaded from the journal. s.mu
+// must be held.
+func (s *TaskServiceServer) putQueueLocked(q *taskv1.Queue) {
+	if i := slices.IndexFunc(s.queues, func(old *taskv1.Queue) bool { return old.GetName() == q.GetName() }); i >= 0 {
+		s.queues[i] = q
+		return
+	}
+	s.queues = append(s.queues, q)
+}
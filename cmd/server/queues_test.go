@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskService_Queues_ConcurrencyLimit(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	if _, err := client.CreateQueue(ctxWithAuth("devtoken"), &taskv1.CreateQueueRequest{Name: "batch", MaxConcurrency: 1}); err != nil {
+		t.Fatalf("CreateQueue failed: %v", err)
+	}
+	var ids []string
+	for _, queue := range []string{"batch", "batch", ""} {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job", Queue: queue})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, created.GetTask().GetTaskId())
+	}
+
+	claim := func() *taskv1.Task {
+		t.Helper()
+		res, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+		if err != nil {
+			t.Fatalf("ClaimTask failed: %v", err)
+		}
+		return res.GetTask()
+	}
+	first := claim()
+	if first.GetTaskId() != ids[0] {
+		t.Fatalf("expected the oldest batch task, got %v", first)
+	}
+	// The second batch task is older, but batch is at its limit.
+	if task := claim(); task.GetTaskId() != ids[2] || task.GetQueue() != defaultQueue {
+		t.Fatalf("expected the default queue task, got %v", task)
+	}
+	if task := claim(); task != nil {
+		t.Fatalf("expected nothing claimable, got %v", task)
+	}
+
+	list, err := client.ListQueues(ctxWithAuth("devtoken"), &taskv1.ListQueuesRequest{})
+	if err != nil {
+		t.Fatalf("ListQueues failed: %v", err)
+	}
+	if len(list.GetQueues()) != 2 || list.GetQueues()[1].GetName() != "batch" || list.GetQueues()[1].GetRunning() != 1 || list.GetQueues()[1].GetPending() != 1 {
+		t.Fatalf("unexpected queues: %v", list.GetQueues())
+	}
+
+	if _, err := client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: first.GetTaskId(), WorkerId: "w1", LeaseId: first.GetLease().GetLeaseId()}); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if task := claim(); task.GetTaskId() != ids[1] {
+		t.Fatalf("expected the second batch task once a slot freed, got %v", task)
+	}
+}
+
+func TestTaskService_Queues_Validation(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	_, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job", Queue: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for an unknown queue, got %v", err)
+	}
+	_, err = client.CreateQueue(ctxWithAuth("devtoken"), &taskv1.CreateQueueRequest{Name: defaultQueue})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", err)
+	}
+	_, err = client.CreateQueue(ctxWithAuth("devtoken"), &taskv1.CreateQueueRequest{Name: "bad name"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a bad name, got %v", err)
+	}
+	_, err = client.UpdateQueue(ctxWithAuth("devtoken"), &taskv1.UpdateQueueRequest{Name: defaultQueue, MaxConcurrency: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a negative limit, got %v", err)
+	}
+	_, err = client.UpdateQueue(ctxWithAuth("devtoken"), &taskv1.UpdateQueueRequest{Name: "missing", MaxConcurrency: 1})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestTaskService_Queues_RaisingLimitWakesSessions(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	if _, err := client.CreateQueue(ctxWithAuth("devtoken"), &taskv1.CreateQueueRequest{Name: "batch", MaxConcurrency: 1}); err != nil {
+		t.Fatalf("CreateQueue failed: %v", err)
+	}
+	var second string
+	for range 2 {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job", Queue: "batch"})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		second = created.GetTask().GetTaskId()
+	}
+	if _, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1", Queues: []string{"batch"}}); err != nil {
+		t.Fatalf("ClaimTask failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	hello := helloMsg("w2", 2)
+	hello.GetHello().Queues = []string{"batch"}
+	if err := stream.Send(hello); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+	if _, err := client.UpdateQueue(ctxWithAuth("devtoken"), &taskv1.UpdateQueueRequest{Name: "batch", MaxConcurrency: 2}); err != nil {
+		t.Fatalf("UpdateQueue failed: %v", err)
+	}
+	if task := recvAssignment(t, stream); task.GetTaskId() != second {
+		t.Fatalf("expected the waiting batch task, got %v", task)
+	}
+}
+
+func TestTaskService_Queues_SurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	if _, err := svc.CreateQueue(ctxWithAuth("devtoken"), &taskv1.CreateQueueRequest{Name: "batch", MaxConcurrency: 4}); err != nil {
+		t.Fatalf("CreateQueue failed: %v", err)
+	}
+	if _, err := svc.UpdateQueue(ctxWithAuth("devtoken"), &taskv1.UpdateQueueRequest{Name: "batch", MaxConcurrency: 2}); err != nil {
+		t.Fatalf("UpdateQueue failed: %v", err)
+	}
+
+	reopened, _ := newStoreServer(t, path)
+	list, err := reopened.ListQueues(ctxWithAuth("devtoken"), &taskv1.ListQueuesRequest{})
+	if err != nil {
+		t.Fatalf("ListQueues failed: %v", err)
+	}
+	if len(list.GetQueues()) != 2 || list.GetQueues()[1].GetName() != "batch" || list.GetQueues()[1].GetMaxConcurrency() != 2 {
+		t.Fatalf("expected batch with limit 2 after restart, got %v", list.GetQueues())
+	}
+}
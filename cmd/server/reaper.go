@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// reapInterval is how often RUNNING tasks are checked for expired leases and
+// exceeded execution timeouts.
+const reapInterval = time.Second
+
+// Error classes set on attempts the server ends itself. Workers may not
+// report them.
+const (
+	errorClassTimeout      = "timeout"
+	errorClassLeaseExpired = "lease_expired"
+)
+
+func checkErrorClass(class string) error {
+	if class == errorClassTimeout || class == errorClassLeaseExpired {
+		return status.Error(codes.InvalidArgument, "error_class "+strconv.Quote(class)+" is reserved for the server")
+	}
+	return nil
+}
+
+func validateExecutionTimeout(d *durationpb.Duration) error {
+	if d == nil {
+		return nil
+	}
+	if err := d.CheckValid(); err != nil || d.AsDuration() < 0 {
+		return status.Error(codes.InvalidArgument, "invalid execution_timeout")
+	}
+	return nil
+}
+
+// runReaper periodically ends attempts whose worker has gone away or that
+// have run for too long.
+func (s *TaskServiceServer) runReaper() {
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.mu.Lock()
+		s.reapLocked(time.Now())
+		s.mu.Unlock()
+	}
+}
+
+// reapLocked ends every RUNNING attempt that exceeded its execution timeout
+// or whose lease expired by now. A timed-out attempt fails as if its worker
+// had reported error class "timeout". An attempt whose lease expired fails
+// with error class "lease_expired" and is retried like any other failed
+// attempt. s.mu must be held.
+func (s *TaskServiceServer) reapLocked(now time.Time) {
+	var running []*taskv1.Task
+	for _, task := range s.taskSlice {
+		if task.GetStatus() == taskv1.TaskStatus_TASK_STATUS_RUNNING {
+			running = append(running, task)
+		}
+	}
+	ctx := context.Background()
+	for _, task := range running {
+		lease := task.GetLease()
+		var err error
+		switch timeout := task.GetExecutionTimeout(); {
+		case timeout != nil && timeout.AsDuration() > 0 && !now.Before(lease.GetClaimedAt().AsTime().Add(timeout.AsDuration())):
+			_, err = s.failAttemptLocked(ctx, task, "execution timeout of "+timeout.AsDuration().String()+" exceeded", errorClassTimeout)
+		case now.Before(lease.GetExpiresAt().AsTime()):
+			continue
+		default:
+			_, err = s.failAttemptLocked(ctx, task, "lease held by worker "+lease.GetWorkerId()+" expired", errorClassLeaseExpired)
+		}
+		if err != nil {
+			// The task stays RUNNING and is looked at again next time.
+			log.Printf("reaper: task %s: %v", task.GetTaskId(), err)
+		}
+	}
+}
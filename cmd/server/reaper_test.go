@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// reapAt runs the reaper as if it were now plus d.
+func reapAt(svc *TaskServiceServer, d time.Duration) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.reapLocked(time.Now().Add(d))
+}
+
+// lastEventMessage returns the message of the newest event.
+func lastEventMessage(t *testing.T, client taskv1.TaskServiceClient) string {
+	t.Helper()
+	res, err := client.ListEvents(ctxWithAuth("devtoken"), &taskv1.ListEventsRequest{PageSize: 100})
+	if err != nil || len(res.GetEvents()) == 0 {
+		t.Fatalf("ListEvents failed: %v, %v", res, err)
+	}
+	return res.GetEvents()[len(res.GetEvents())-1].GetMessage()
+}
+
+func TestTaskService_Reaper_ExpiredLeaseRequeued(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "orphaned"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	claimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1", LeaseDuration: durationpb.New(time.Minute)})
+	if err != nil || claimed.GetTask().GetTaskId() != task_id {
+		t.Fatalf("ClaimTask failed: %v, %v", claimed, err)
+	}
+
+	reapAt(svc, 30*time.Second)
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil || got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING {
+		t.Fatalf("expected a live lease to be left alone, got %v, %v", got, err)
+	}
+
+	reapAt(svc, 2*time.Minute)
+	got, err = client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil || got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING {
+		t.Fatalf("expected the task back in PENDING, got %v, %v", got, err)
+	}
+	if got.GetErrorClass() != errorClassLeaseExpired || !strings.Contains(got.GetError(), "lease held by worker w1 expired") {
+		t.Fatalf("expected the task to explain the requeue, got %v", got)
+	}
+
+	reclaimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w2"})
+	if err != nil || reclaimed.GetTask().GetTaskId() != task_id || reclaimed.GetTask().GetAttempt() != 2 {
+		t.Fatalf("expected another worker to reclaim the task, got %v, %v", reclaimed, err)
+	}
+
+	// A task that keeps losing its worker is not requeued forever.
+	reapAt(svc, 2*time.Minute)
+	if _, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w3"}); err != nil {
+		t.Fatalf("ClaimTask failed: %v", err)
+	}
+	reapAt(svc, 2*time.Minute)
+	got, err = client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil || got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || got.GetDeadLetteredAt() == nil {
+		t.Fatalf("expected the task dead-lettered after %d lost workers, got %v, %v", lostWorkerPolicy.GetMaxAttempts(), got, err)
+	}
+}
+
+func TestTaskService_Reaper_SingleAttemptPolicyIsKept(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "once", RetryPolicy: &taskv1.RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"}); err != nil {
+		t.Fatalf("ClaimTask failed: %v", err)
+	}
+	reapAt(svc, time.Hour)
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: created.GetTask().GetTaskId()})
+	if err != nil || got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || got.GetAttempt() != 1 {
+		t.Fatalf("expected a task allowing one attempt to fail when its lease expires, got %v, %v", got, err)
+	}
+}
+
+func TestTaskService_Reaper_ExecutionTimeout(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "slow", ExecutionTimeout: durationpb.New(10 * time.Second)})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	if created.GetTask().GetExecutionTimeout().AsDuration() != 10*time.Second {
+		t.Fatalf("expected the execution timeout on the task, got %v", created.GetTask())
+	}
+	claimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1", LeaseDuration: durationpb.New(time.Minute)})
+	if err != nil || claimed.GetTask().GetTaskId() != task_id {
+		t.Fatalf("ClaimTask failed: %v, %v", claimed, err)
+	}
+
+	reapAt(svc, 15*time.Second)
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || got.GetErrorClass() != "timeout" || !strings.Contains(got.GetError(), "execution timeout of 10s exceeded") {
+		t.Fatalf("expected the task to fail with a timeout, got %v", got)
+	}
+
+	_, err = client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: task_id, WorkerId: "w1", LeaseId: claimed.GetTask().GetLease().GetLeaseId()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition completing a timed-out task, got %v", err)
+	}
+}
+
+func TestTaskService_Reaper_FollowsRetryPolicy(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{
+		Title:       "retried",
+		RetryPolicy: &taskv1.RetryPolicy{MaxAttempts: 2, InitialBackoff: durationpb.New(0)},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	for attempt := int32(1); attempt <= 2; attempt++ {
+		if _, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"}); err != nil {
+			t.Fatalf("ClaimTask failed: %v", err)
+		}
+		reapAt(svc, time.Hour)
+		got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+		if err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+		if got.GetErrorClass() != "lease_expired" || got.GetAttempt() != attempt {
+			t.Fatalf("attempt %d: expected a lease_expired failure, got %v", attempt, got)
+		}
+		if attempt == 1 && got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING {
+			t.Fatalf("expected a retry after the first attempt, got %v", got)
+		}
+		if attempt == 2 && (got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || got.GetDeadLetteredAt() == nil) {
+			t.Fatalf("expected the last attempt to dead-letter the task, got %v", got)
+		}
+	}
+	if msg := lastEventMessage(t, client); !strings.Contains(msg, "moved to dead letters") {
+		t.Fatalf("expected the event to explain the dead letter, got %q", msg)
+	}
+}
+
+func TestTaskService_Reaper_InvalidExecutionTimeout(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	_, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "bad", ExecutionTimeout: durationpb.New(-time.Second)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a negative execution_timeout, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultMaxPayloadBytes = 64 << 10
+
+// checkPayload rejects input/result structs larger than the configured limit.
+func (s *TaskServiceServer) checkPayload(field string, payload *structpb.Struct) error {
+	if payload == nil {
+		return nil
+	}
+	if size := proto.Size(payload); size > s.maxPayloadBytes {
+		return status.Error(codes.InvalidArgument, field+" is "+strconv.Itoa(size)+" bytes, limit is "+strconv.Itoa(s.maxPayloadBytes))
+	}
+	return nil
+}
+
+// SetTaskResult records the result of a task and completes it. Only PENDING
+// tasks and RUNNING tasks nobody holds a lease on can be given a result;
+// leased tasks are finished by their worker through CompleteTask.
+func (s *TaskServiceServer) SetTaskResult(ctx context.Context, req *taskv1.SetTaskResultRequest) (*taskv1.Task, error) {
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	if err := s.checkPayload("result", req.GetResult()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.taskMap[task_id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	switch task.GetStatus() {
+	case taskv1.TaskStatus_TASK_STATUS_PENDING:
+	case taskv1.TaskStatus_TASK_STATUS_RUNNING:
+		if task.GetLease() != nil {
+			return nil, status.Error(codes.FailedPrecondition, "task "+task_id+" is leased to "+task.GetLease().GetWorkerId()+"; use CompleteTask with the lease")
+		}
+	default:
+		return nil, status.Error(codes.FailedPrecondition, "task "+task_id+" is "+task.GetStatus().String())
+	}
+	updated := proto.Clone(task).(*taskv1.Task)
+	updated.Lease = nil
+	updated.Result = req.GetResult()
+	updated.Status = taskv1.TaskStatus_TASK_STATUS_COMPLETED
+	updated.UpdatedAt = timestamppb.New(time.Now())
+	if err := s.replaceTaskLocked(ctx, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func isTerminal(st taskv1.TaskStatus) bool {
+	switch st {
+	case taskv1.TaskStatus_TASK_STATUS_COMPLETED, taskv1.TaskStatus_TASK_STATUS_FAILED, taskv1.TaskStatus_TASK_STATUS_CANCELED:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTaskService_SetTaskResult_CompletesOnce(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	input, _ := structpb.NewStruct(map[string]any{"url": "https://example.com", "retries": 3})
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "fetch", Input: input})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	if got := created.GetTask().GetInput().GetFields()["retries"].GetNumberValue(); got != 3 {
+		t.Fatalf("expected input retries 3, got %v", got)
+	}
+
+	result, _ := structpb.NewStruct(map[string]any{"bytes": 512})
+	done, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id, Result: result})
+	if err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	if done.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected COMPLETED, got %v", done.GetStatus())
+	}
+
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if got.GetResult().GetFields()["bytes"].GetNumberValue() != 512 {
+		t.Fatalf("expected stored result, got %v", got.GetResult())
+	}
+
+	_, err = client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id, Result: result})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition on completed task, got %v", status.Code(err))
+	}
+}
+
+func TestTaskService_SetTaskResult_RejectsLeasedAndScheduled(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	task := createAndClaim(t, client)
+	_, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task.GetTaskId()})
+	if status.Code(err) != codes.FailedPrecondition || !strings.Contains(err.Error(), "CompleteTask") {
+		t.Fatalf("expected FailedPrecondition pointing to CompleteTask for a leased task, got %v", err)
+	}
+
+	scheduled, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "later", RunAt: timestamppb.New(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	_, err = client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: scheduled.GetTask().GetTaskId()})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a SCHEDULED task, got %v", err)
+	}
+}
+
+func TestTaskService_Payload_TooLarge(t *testing.T) {
+	svc := NewTaskServiceServer()
+	svc.maxPayloadBytes = 64
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	big, _ := structpb.NewStruct(map[string]any{"blob": strings.Repeat("x", 100)})
+	_, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "big", Input: big})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for input, got %v", status.Code(err))
+	}
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "small"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	_, err = client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: created.GetTask().GetTaskId(), Result: big})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for result, got %v", status.Code(err))
+	}
+}
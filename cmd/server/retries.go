@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxRetryAttempts       = 100
+	defaultRetryBackoff    = time.Second
+	defaultRetryMultiplier = 2
+	defaultMaxRetryBackoff = 5 * time.Minute
+)
+
+// lostWorkerPolicy applies to tasks without a retry policy whose worker went
+// away: a lost worker is not the task's fault, so it is retried straight
+// away, but a task that keeps taking its workers down is dead-lettered.
+var lostWorkerPolicy = &taskv1.RetryPolicy{MaxAttempts: 3, InitialBackoff: durationpb.New(0)}
+
+func validateRetryPolicy(p *taskv1.RetryPolicy) error {
+	if p == nil {
+		return nil
+	}
+	if p.GetMaxAttempts() < 0 || p.GetMaxAttempts() > maxRetryAttempts {
+		return status.Error(codes.InvalidArgument, "retry_policy.max_attempts must be between 0 and "+strconv.Itoa(maxRetryAttempts))
+	}
+	for name, d := range map[string]*durationpb.Duration{"initial_backoff": p.GetInitialBackoff(), "max_backoff": p.GetMaxBackoff()} {
+		if d == nil {
+			continue
+		}
+		if err := d.CheckValid(); err != nil || d.AsDuration() < 0 {
+			return status.Error(codes.InvalidArgument, "invalid retry_policy."+name)
+		}
+	}
+	if m := p.GetBackoffMultiplier(); math.IsNaN(m) || math.IsInf(m, 0) || (m != 0 && m < 1) {
+		return status.Error(codes.InvalidArgument, "retry_policy.backoff_multiplier must be at least 1")
+	}
+	if j := p.GetJitter(); math.IsNaN(j) || j < 0 || j > 1 {
+		return status.Error(codes.InvalidArgument, "retry_policy.jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// retryDelay returns the backoff after the given failed attempt (1-based).
+func retryDelay(p *taskv1.RetryPolicy, attempt int32) time.Duration {
+	base, limit, mult := defaultRetryBackoff, defaultMaxRetryBackoff, float64(defaultRetryMultiplier)
+	if p.GetInitialBackoff() != nil {
+		base = p.GetInitialBackoff().AsDuration()
+	}
+	if p.GetMaxBackoff() != nil {
+		limit = p.GetMaxBackoff().AsDuration()
+	} else {
+		limit = max(limit, base)
+	}
+	if p.GetBackoffMultiplier() != 0 {
+		mult = p.GetBackoffMultiplier()
+	}
+	d := min(float64(base)*math.Pow(mult, float64(max(attempt-1, 0))), float64(limit))
+	d -= d * p.GetJitter() * rand.Float64()
+	return time.Duration(d)
+}
+
+// failAttemptLocked ends the current attempt of a task with an error. Its
+// retry policy decides whether it is retried or fails; a task with a policy
+// that fails for good is dead-lettered. Tasks without a retry policy whose
+// lease expired fall back to lostWorkerPolicy. s.mu must be held.
+func (s *TaskServiceServer) failAttemptLocked(ctx context.Context, task *taskv1.Task, errText, errClass string) (*taskv1.Task, error) {
+	now := time.Now()
+	updated := proto.Clone(task).(*taskv1.Task)
+	updated.Status = taskv1.TaskStatus_TASK_STATUS_FAILED
+	updated.Error = errText
+	updated.ErrorClass = errClass
+	updated.UpdatedAt = timestamppb.New(now)
+
+	p := task.GetRetryPolicy()
+	if p == nil && errClass == errorClassLeaseExpired {
+		p = lostWorkerPolicy
+	}
+	var message string
+	switch {
+	case p.GetMaxAttempts() <= 1:
+		// No retry policy: the task just fails.
+	case len(p.GetRetryableErrors()) > 0 && !slices.Contains(p.GetRetryableErrors(), errClass):
+		updated.DeadLetteredAt = timestamppb.New(now)
+		message = "error class " + strconv.Quote(errClass) + " is not retryable; moved to dead letters"
+	case task.GetAttempt() >= p.GetMaxAttempts():
+		updated.DeadLetteredAt = timestamppb.New(now)
+		message = fmt.Sprintf("attempt %d of %d failed; moved to dead letters", task.GetAttempt(), p.GetMaxAttempts())
+	default:
+		delay := retryDelay(p, task.GetAttempt())
+		updated.Status = taskv1.TaskStatus_TASK_STATUS_PENDING
+		updated.Lease = nil
+		if delay > 0 {
+			updated.Status = taskv1.TaskStatus_TASK_STATUS_SCHEDULED
+			updated.RunAt = timestamppb.New(now.Add(delay))
+		}
+		message = fmt.Sprintf("attempt %d of %d failed; retrying in %s", task.GetAttempt(), p.GetMaxAttempts(), delay.Round(time.Millisecond))
+	}
+	if err := s.replaceTaskWithMessageLocked(ctx, updated, message); err != nil {
+		return nil, err
+	}
+	if updated.Status == taskv1.TaskStatus_TASK_STATUS_SCHEDULED {
+		s.delayLocked(updated)
+	}
+	return updated, nil
+}
+
+// ListDeadLetters lists the tasks that failed for good despite their retry
+// policy, oldest first.
+func (s *TaskServiceServer) ListDeadLetters(ctx context.Context, req *taskv1.ListDeadLettersRequest) (*taskv1.ListDeadLettersResponse, error) {
+	page_size := req.GetPageSize()
+	if page_size <= 0 {
+		page_size = 10
+	} else if page_size > 100 {
+		page_size = 100
+	}
+	offset := 0
+	if page_token := req.GetPageToken(); page_token != "" {
+		var err error
+		offset, err = strconv.Atoi(page_token)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	queue := strings.TrimSpace(req.GetQueue())
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []*taskv1.Task
+	for _, task := range s.taskSlice {
+		if task.GetDeadLetteredAt() != nil && (queue == "" || taskQueue(task) == queue) {
+			matched = append(matched, task)
+		}
+	}
+	res := &taskv1.ListDeadLettersResponse{Tasks: []*taskv1.Task{}}
+	if offset >= len(matched) {
+		return res, nil
+	}
+	end := min(offset+int(page_size), len(matched))
+	res.Tasks = append(res.Tasks, matched[offset:end]...)
+	if end < len(matched) {
+		res.NextPageToken = strconv.Itoa(end)
+	}
+	return res, nil
+}
+
+func (s *TaskServiceServer) RequeueDeadLetter(ctx context.Context, req *taskv1.RequeueDeadLetterRequest) (*taskv1.Task, error) {
+	task_id := strings.TrimSpace(req.GetTaskId())
+	if task_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.taskMap[task_id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "task not found with id "+task_id)
+	}
+	if task.GetDeadLetteredAt() == nil {
+		return nil, status.Error(codes.FailedPrecondition, "task "+task_id+" is not dead-lettered")
+	}
+	requeued := proto.Clone(task).(*taskv1.Task)
+	requeued.Status = taskv1.TaskStatus_TASK_STATUS_PENDING
+	requeued.Attempt = 0
+	requeued.DeadLetteredAt = nil
+	requeued.Lease = nil
+	requeued.Error = ""
+	requeued.ErrorClass = ""
+	requeued.UpdatedAt = timestamppb.New(time.Now())
+	if err := s.replaceTaskWithMessageLocked(ctx, requeued, "requeued from dead letters"); err != nil {
+		return nil, err
+	}
+	return requeued, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// claimAndFail claims the next task as w1 and fails it.
+func claimAndFail(t *testing.T, client taskv1.TaskServiceClient, errClass string) *taskv1.Task {
+	t.Helper()
+	claimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil || claimed.GetTask() == nil {
+		t.Fatalf("ClaimTask failed: %v, %v", claimed, err)
+	}
+	task := claimed.GetTask()
+	failed, err := client.FailTask(ctxWithAuth("devtoken"), &taskv1.FailTaskRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId(), Error: "boom", ErrorClass: errClass})
+	if err != nil {
+		t.Fatalf("FailTask failed: %v", err)
+	}
+	return failed
+}
+
+func TestTaskService_Retries_ExhaustedToDeadLetters(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{
+		Title:       "flaky",
+		RetryPolicy: &taskv1.RetryPolicy{MaxAttempts: 3, InitialBackoff: durationpb.New(0)},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	for attempt := int32(1); attempt <= 2; attempt++ {
+		failed := claimAndFail(t, client, "")
+		if failed.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING || failed.GetAttempt() != attempt || failed.GetError() != "boom" {
+			t.Fatalf("attempt %d: expected PENDING for a retry, got %v", attempt, failed)
+		}
+	}
+	failed := claimAndFail(t, client, "")
+	if failed.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || failed.GetDeadLetteredAt() == nil || failed.GetAttempt() != 3 {
+		t.Fatalf("expected the last attempt to dead-letter the task, got %v", failed)
+	}
+
+	letters, err := client.ListDeadLetters(ctxWithAuth("devtoken"), &taskv1.ListDeadLettersRequest{})
+	if err != nil || len(letters.GetTasks()) != 1 || letters.GetTasks()[0].GetTaskId() != task_id {
+		t.Fatalf("expected the task in dead letters, got %v, %v", letters, err)
+	}
+	letters, err = client.ListDeadLetters(ctxWithAuth("devtoken"), &taskv1.ListDeadLettersRequest{Queue: "other"})
+	if err != nil || len(letters.GetTasks()) != 0 {
+		t.Fatalf("expected no dead letters in another queue, got %v, %v", letters, err)
+	}
+
+	requeued, err := client.RequeueDeadLetter(ctxWithAuth("devtoken"), &taskv1.RequeueDeadLetterRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+	if requeued.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING || requeued.GetAttempt() != 0 || requeued.GetDeadLetteredAt() != nil ||
+		requeued.GetLease() != nil || requeued.GetError() != "" || requeued.GetErrorClass() != "" {
+		t.Fatalf("expected a fresh PENDING task, got %v", requeued)
+	}
+	_, err = client.RequeueDeadLetter(ctxWithAuth("devtoken"), &taskv1.RequeueDeadLetterRequest{TaskId: task_id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition requeueing twice, got %v", err)
+	}
+}
+
+func TestTaskService_Retries_ErrorClasses(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	policy := &taskv1.RetryPolicy{MaxAttempts: 5, InitialBackoff: durationpb.New(time.Hour), RetryableErrors: []string{"transient"}}
+	for range 2 {
+		if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job", RetryPolicy: policy}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	before := time.Now()
+	retried := claimAndFail(t, client, "transient")
+	if retried.GetStatus() != taskv1.TaskStatus_TASK_STATUS_SCHEDULED || retried.GetErrorClass() != "transient" {
+		t.Fatalf("expected a retryable error to schedule a retry, got %v", retried)
+	}
+	if wait := retried.GetRunAt().AsTime().Sub(before); wait < time.Hour || wait > time.Hour+time.Minute {
+		t.Fatalf("expected the retry after the initial backoff, got %v", wait)
+	}
+	dead := claimAndFail(t, client, "bad_input")
+	if dead.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || dead.GetDeadLetteredAt() == nil {
+		t.Fatalf("expected a non-retryable error to dead-letter the task, got %v", dead)
+	}
+}
+
+func TestTaskService_Retries_WithoutPolicyJustFails(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	failed := claimAndFail(t, client, "")
+	if failed.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || failed.GetDeadLetteredAt() != nil {
+		t.Fatalf("expected a plain FAILED task, got %v", failed)
+	}
+}
+
+func TestTaskService_Retries_ReservedErrorClasses(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	claimed, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil || claimed.GetTask() == nil {
+		t.Fatalf("ClaimTask failed: %v, %v", claimed, err)
+	}
+	task := claimed.GetTask()
+	for _, class := range []string{errorClassTimeout, errorClassLeaseExpired} {
+		_, err := client.FailTask(ctxWithAuth("devtoken"), &taskv1.FailTaskRequest{TaskId: task.GetTaskId(), WorkerId: "w1", LeaseId: task.GetLease().GetLeaseId(), Error: "boom", ErrorClass: class})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("%s: expected InvalidArgument for a reserved class, got %v", class, err)
+		}
+	}
+}
+
+func TestTaskService_Retries_InvalidPolicy(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	for name, p := range map[string]*taskv1.RetryPolicy{
+		"negative attempts":  {MaxAttempts: -1},
+		"too many attempts":  {MaxAttempts: maxRetryAttempts + 1},
+		"negative backoff":   {MaxAttempts: 2, InitialBackoff: durationpb.New(-time.Second)},
+		"shrinking backoff":  {MaxAttempts: 2, BackoffMultiplier: 0.5},
+		"jitter out of band": {MaxAttempts: 2, Jitter: 1.5},
+	} {
+		_, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job", RetryPolicy: p})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := &taskv1.RetryPolicy{InitialBackoff: durationpb.New(time.Second), MaxBackoff: durationpb.New(5 * time.Second)}
+	for attempt, want := range map[int32]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		if got := retryDelay(p, attempt); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+	p.Jitter = 0.5
+	for range 100 {
+		if got := retryDelay(p, 2); got < time.Second || got > 2*time.Second {
+			t.Fatalf("retryDelay with jitter = %v, want between 1s and 2s", got)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// Time zones must resolve even where the host has no zoneinfo.
+	_ "time/tzdata"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/cron"
+	"grpc-lab/internal/journal"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxCatchUpRuns bounds the tasks a catch_up schedule creates for runs
+	// it missed; the oldest ones are dropped.
+	maxCatchUpRuns = 100
+	// scheduleLabel is set on every task a schedule creates.
+	scheduleLabel = "schedule_id"
+)
+
+// nextRun returns when sched fires next after t.
+func nextRun(sched *taskv1.Schedule, t time.Time) (time.Time, error) {
+	c, err := cron.Parse(sched.GetCron())
+	if err != nil {
+		return time.Time{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	loc := time.UTC
+	if tz := sched.GetTimeZone(); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return time.Time{}, status.Error(codes.InvalidArgument, "unknown time_zone "+strconv.Quote(tz))
+		}
+	}
+	next := c.Next(t.In(loc))
+	if next.IsZero() {
+		return time.Time{}, status.Error(codes.InvalidArgument, "cron "+strconv.Quote(sched.GetCron())+" never fires")
+	}
+	return next, nil
+}
+
+// prepareScheduleLocked validates the settable fields of sched and computes
+// its next run after now. s.mu must be held.
+func (s *TaskServiceServer) prepareScheduleLocked(sched *taskv1.Schedule, now time.Time) error {
+	if _, ok := taskv1.OverlapPolicy_name[int32(sched.GetOverlapPolicy())]; !ok {
+		return status.Error(codes.InvalidArgument, "unknown overlap_policy")
+	}
+	tmpl := sched.GetTemplate()
+	if tmpl == nil {
+		return status.Error(codes.InvalidArgument, "template is required")
+	}
+	if strings.TrimSpace(tmpl.GetTitle()) == "" {
+		return status.Error(codes.InvalidArgument, "template title is required")
+	}
+	if tmpl.GetRunAt() != nil {
+		return status.Error(codes.InvalidArgument, "template run_at must be unset")
+	}
+	if err := s.checkPayload("input", tmpl.GetInput()); err != nil {
+		return err
+	}
+	if err := validateRetryPolicy(tmpl.GetRetryPolicy()); err != nil {
+		return err
+	}
+	if err := validateExecutionTimeout(tmpl.GetExecutionTimeout()); err != nil {
+		return err
+	}
+	labels := map[string]string{scheduleLabel: sched.GetScheduleId()}
+	for k, v := range tmpl.GetLabels() {
+		labels[k] = v
+	}
+	if err := validateLabels(labels); err != nil {
+		return err
+	}
+	if err := s.checkParentLocked(strings.TrimSpace(tmpl.GetParentId())); err != nil {
+		return err
+	}
+	if _, err := s.checkQueueLocked(tmpl.GetQueue()); err != nil {
+		return err
+	}
+	next, err := nextRun(sched, now)
+	if err != nil {
+		return err
+	}
+	sched.NextRunAt = nil
+	if !sched.GetPaused() {
+		sched.NextRunAt = timestamppb.New(next)
+	}
+	return nil
+}
+
+func (s *TaskServiceServer) CreateSchedule(ctx context.Context, req *taskv1.CreateScheduleRequest) (*taskv1.Schedule, error) {
+	now := time.Now()
+	sched := &taskv1.Schedule{
+		ScheduleId:    uuid.New().String(),
+		Cron:          strings.TrimSpace(req.GetCron()),
+		TimeZone:      strings.TrimSpace(req.GetTimeZone()),
+		Template:      req.GetTemplate(),
+		OverlapPolicy: req.GetOverlapPolicy(),
+		CatchUp:       req.GetCatchUp(),
+		Paused:        req.GetPaused(),
+		CreatedAt:     timestamppb.New(now),
+		CreatedBy:     principalFromContext(ctx),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.prepareScheduleLocked(sched, now); err != nil {
+		return nil, err
+	}
+	if err := s.appendLocked(recordSchedule, sched); err != nil {
+		return nil, err
+	}
+	s.schedules = append(s.schedules, sched)
+	s.wakeSchedulesLocked()
+	return sched, nil
+}
+
+func (s *TaskServiceServer) GetSchedule(ctx context.Context, req *taskv1.GetScheduleRequest) (*taskv1.Schedule, error) {
+	schedule_id := strings.TrimSpace(req.GetScheduleId())
+	if schedule_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id is required")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := s.scheduleIndexLocked(schedule_id)
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "schedule not found with id "+schedule_id)
+	}
+	return s.schedules[i], nil
+}
+
+func (s *TaskServiceServer) ListSchedules(ctx context.Context, req *taskv1.ListSchedulesRequest) (*taskv1.ListSchedulesResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &taskv1.ListSchedulesResponse{Schedules: append([]*taskv1.Schedule{}, s.schedules...)}, nil
+}
+
+func (s *TaskServiceServer) UpdateSchedule(ctx context.Context, req *taskv1.UpdateScheduleRequest) (*taskv1.Schedule, error) {
+	schedule_id := strings.TrimSpace(req.GetScheduleId())
+	if schedule_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.scheduleIndexLocked(schedule_id)
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "schedule not found with id "+schedule_id)
+	}
+	updated := proto.Clone(s.schedules[i]).(*taskv1.Schedule)
+	updated.Cron = strings.TrimSpace(req.GetCron())
+	updated.TimeZone = strings.TrimSpace(req.GetTimeZone())
+	updated.Template = req.GetTemplate()
+	updated.OverlapPolicy = req.GetOverlapPolicy()
+	updated.CatchUp = req.GetCatchUp()
+	updated.Paused = req.GetPaused()
+	if updated.GetOverlapPolicy() != taskv1.OverlapPolicy_OVERLAP_POLICY_QUEUE {
+		updated.QueuedRuns = 0
+	}
+	if err := s.prepareScheduleLocked(updated, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.appendLocked(recordSchedule, updated); err != nil {
+		return nil, err
+	}
+	s.schedules[i] = updated
+	s.wakeSchedulesLocked()
+	return updated, nil
+}
+
+// DeleteSchedule stops future runs. Tasks it already created are kept.
+func (s *TaskServiceServer) DeleteSchedule(ctx context.Context, req *taskv1.DeleteScheduleRequest) (*taskv1.DeleteScheduleResponse, error) {
+	schedule_id := strings.TrimSpace(req.GetScheduleId())
+	if schedule_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.scheduleIndexLocked(schedule_id)
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "schedule not found with id "+schedule_id)
+	}
+	if s.journal != nil {
+		if err := s.journal.Append(journal.Record{Kind: recordScheduleDeleted, Data: []byte(schedule_id)}); err != nil {
+			return nil, status.Error(codes.Internal, "store write failed: "+err.Error())
+		}
+	}
+	s.schedules = slices.Delete(s.schedules, i, i+1)
+	return &taskv1.DeleteScheduleResponse{}, nil
+}
+
+func (s *TaskServiceServer) scheduleIndexLocked(schedule_id string) int {
+	return slices.IndexFunc(s.schedules, func(sched *taskv1.Schedule) bool { return sched.GetScheduleId() == schedule_id })
+}
+
+// putScheduleLocked adds or replaces a schedule loaded from the journal.
+// s.mu must be held.
+func (s *TaskServiceServer) putScheduleLocked(sched *taskv1.Schedule) {
+	if i := s.scheduleIndexLocked(sched.GetScheduleId()); i >= 0 {
+		s.schedules[i] = sched
+		return
+	}
+	s.schedules = append(s.schedules, sched)
+}
+
+func (s *TaskServiceServer) wakeSchedulesLocked() {
+	select {
+	case s.scheduleWake <- struct{}{}:
+	default:
+	}
+}
+
+// runSchedules creates the tasks of schedules as they come due, and of
+// queued runs as the task before them finishes.
+func (s *TaskServiceServer) runSchedules() {
+	timer := time.NewTimer(time.Hour)
+	for {
+		next, ok := s.fireSchedules(time.Now())
+		wait := time.Hour
+		if ok {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.scheduleWake:
+		}
+	}
+}
+
+// fireSchedules starts the queued runs whose previous task finished, runs
+// every schedule due at now and returns when the next one is due, if any.
+func (s *TaskServiceServer) fireSchedules(now time.Time) (time.Time, bool) {
+	var due, unblocked []string
+	s.mu.RLock()
+	for _, sched := range s.schedules {
+		if at := sched.GetNextRunAt(); at != nil && !at.AsTime().After(now) {
+			due = append(due, sched.GetScheduleId())
+		}
+		if prev, ok := s.taskMap[sched.GetLastTaskId()]; sched.GetQueuedRuns() > 0 && (!ok || isTerminal(prev.GetStatus())) {
+			unblocked = append(unblocked, sched.GetScheduleId())
+		}
+	}
+	s.mu.RUnlock()
+	// Each schedule is looked up again before it is acted on, since starting
+	// a queued run changes the task a due run must wait for.
+	for _, schedule_id := range unblocked {
+		if sched := s.currentSchedule(schedule_id); sched != nil {
+			s.startQueuedRun(sched)
+		}
+	}
+	for _, schedule_id := range due {
+		if sched := s.currentSchedule(schedule_id); sched != nil && !sched.GetNextRunAt().AsTime().After(now) {
+			s.fireSchedule(sched, now)
+		}
+	}
+
+	var next time.Time
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sched := range s.schedules {
+		if at := sched.GetNextRunAt(); at != nil && (next.IsZero() || at.AsTime().Before(next)) {
+			next = at.AsTime()
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// currentSchedule returns the current version of a schedule, or nil once it
+// is deleted.
+func (s *TaskServiceServer) currentSchedule(schedule_id string) *taskv1.Schedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.scheduleIndexLocked(schedule_id); i >= 0 {
+		return s.schedules[i]
+	}
+	return nil
+}
+
+// fireSchedule creates the tasks for the runs of sched due at now: all of
+// them with catch_up, otherwise only the latest.
+func (s *TaskServiceServer) fireSchedule(sched *taskv1.Schedule, now time.Time) {
+	var runs []time.Time
+	next := sched.GetNextRunAt().AsTime()
+	for !next.After(now) {
+		runs = append(runs, next)
+		if len(runs) > maxCatchUpRuns {
+			runs = runs[1:]
+		}
+		var err error
+		if next, err = nextRun(sched, next); err != nil {
+			// It does not fire again within the search horizon.
+			next = time.Time{}
+			break
+		}
+	}
+	if !sched.GetCatchUp() {
+		runs = runs[len(runs)-1:]
+	}
+
+	// Tasks are created on behalf of whoever created the schedule.
+	ctx := context.WithValue(context.Background(), principalKey{}, sched.GetCreatedBy())
+	last_task_id := sched.GetLastTaskId()
+	queued_runs := sched.GetQueuedRuns()
+	for _, run := range runs {
+		task_id, queued, err := s.runSchedule(ctx, sched, last_task_id)
+		if err != nil {
+			log.Printf("schedule %s: run at %s: %v", sched.GetScheduleId(), run.Format(time.RFC3339), err)
+			continue
+		}
+		if queued {
+			queued_runs = min(queued_runs+1, maxCatchUpRuns)
+		}
+		if task_id != "" {
+			last_task_id = task_id
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.scheduleIndexLocked(sched.GetScheduleId())
+	if i < 0 || s.schedules[i] != sched {
+		// Deleted or updated meanwhile; an update computed its own next run.
+		return
+	}
+	updated := proto.Clone(sched).(*taskv1.Schedule)
+	updated.LastRunAt = timestamppb.New(runs[len(runs)-1])
+	updated.LastTaskId = last_task_id
+	updated.QueuedRuns = queued_runs
+	updated.NextRunAt = nil
+	if !next.IsZero() {
+		updated.NextRunAt = timestamppb.New(next)
+	}
+	// Keeping the old version would fire the same runs again straight away,
+	// so a failed write only means they may be repeated after a restart.
+	if err := s.appendLocked(recordSchedule, updated); err != nil {
+		log.Printf("schedule %s: %v", sched.GetScheduleId(), err)
+	}
+	s.schedules[i] = updated
+}
+
+// startQueuedRun creates the task of the oldest run of sched held back by
+// OVERLAP_POLICY_QUEUE, now that the task before it finished.
+func (s *TaskServiceServer) startQueuedRun(sched *taskv1.Schedule) {
+	ctx := context.WithValue(context.Background(), principalKey{}, sched.GetCreatedBy())
+	task_id, _, err := s.runSchedule(ctx, sched, sched.GetLastTaskId())
+	if err != nil {
+		log.Printf("schedule %s: queued run: %v", sched.GetScheduleId(), err)
+		return
+	}
+	if task_id == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.scheduleIndexLocked(sched.GetScheduleId())
+	if i < 0 {
+		return
+	}
+	// An update meanwhile keeps the queued runs, so the started one is
+	// taken off whichever version is current.
+	updated := proto.Clone(s.schedules[i]).(*taskv1.Schedule)
+	updated.LastTaskId = task_id
+	updated.QueuedRuns = max(updated.GetQueuedRuns()-1, 0)
+	// As in fireSchedule, a failed write only means the run may be
+	// repeated after a restart.
+	if err := s.appendLocked(recordSchedule, updated); err != nil {
+		log.Printf("schedule %s: %v", sched.GetScheduleId(), err)
+	}
+	s.schedules[i] = updated
+}
+
+// runSchedule creates one task from the template of sched, applying its
+// overlap policy to prev_id, the task of the previous run. It returns ""
+// when the run is skipped, and reports whether it was queued instead.
+func (s *TaskServiceServer) runSchedule(ctx context.Context, sched *taskv1.Schedule, prev_id string) (string, bool, error) {
+	s.mu.Lock()
+	if prev, ok := s.taskMap[prev_id]; ok && !isTerminal(prev.GetStatus()) {
+		switch sched.GetOverlapPolicy() {
+		case taskv1.OverlapPolicy_OVERLAP_POLICY_QUEUE:
+			s.mu.Unlock()
+			return "", true, nil
+		case taskv1.OverlapPolicy_OVERLAP_POLICY_REPLACE:
+			if _, err := s.cancelTaskLocked(ctx, prev, "replaced by a new run of schedule "+sched.GetScheduleId()); err != nil {
+				s.mu.Unlock()
+				return "", false, err
+			}
+		default:
+			s.mu.Unlock()
+			return "", false, nil
+		}
+	}
+	s.mu.Unlock()
+
+	req := proto.Clone(sched.GetTemplate()).(*taskv1.CreateTaskRequest)
+	if req.Labels == nil {
+		req.Labels = make(map[string]string)
+	}
+	req.Labels[scheduleLabel] = sched.GetScheduleId()
+	res, err := s.CreateTask(ctx, req)
+	if err != nil {
+		return "", false, err
+	}
+	return res.GetTask().GetTaskId(), false, nil
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// scheduleTasks returns the tasks created by a schedule, oldest first.
+func scheduleTasks(svc *TaskServiceServer, schedule_id string) []*taskv1.Task {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return scheduleTasksLocked(svc, schedule_id)
+}
+
+func scheduleTasksLocked(svc *TaskServiceServer, schedule_id string) []*taskv1.Task {
+	var tasks []*taskv1.Task
+	for _, task := range svc.taskSlice {
+		if task.GetLabels()[scheduleLabel] == schedule_id {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+func TestTaskService_Schedules_CRUD(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	before := time.Now()
+	sched, err := client.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{
+		Cron:     "0 2 * * *",
+		TimeZone: "Europe/Berlin",
+		Template: &taskv1.CreateTaskRequest{Title: "nightly report", Labels: map[string]string{"team": "infra"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	next := sched.GetNextRunAt().AsTime().In(berlin)
+	if next.Hour() != 2 || next.Minute() != 0 || !next.After(before) || next.Sub(before) > 25*time.Hour {
+		t.Fatalf("expected the next 02:00 Berlin time, got %v", next)
+	}
+	if sched.GetCreatedBy() != devUser {
+		t.Fatalf("expected created_by %q, got %q", devUser, sched.GetCreatedBy())
+	}
+
+	paused, err := client.UpdateSchedule(ctxWithAuth("devtoken"), &taskv1.UpdateScheduleRequest{
+		ScheduleId: sched.GetScheduleId(),
+		Cron:       "@hourly",
+		Template:   sched.GetTemplate(),
+		Paused:     true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSchedule failed: %v", err)
+	}
+	if paused.GetNextRunAt() != nil || paused.GetCron() != "@hourly" {
+		t.Fatalf("expected a paused hourly schedule without a next run, got %v", paused)
+	}
+	list, err := client.ListSchedules(ctxWithAuth("devtoken"), &taskv1.ListSchedulesRequest{})
+	if err != nil || len(list.GetSchedules()) != 1 || !list.GetSchedules()[0].GetPaused() {
+		t.Fatalf("unexpected ListSchedules: %v, %v", list, err)
+	}
+	if _, err := client.DeleteSchedule(ctxWithAuth("devtoken"), &taskv1.DeleteScheduleRequest{ScheduleId: sched.GetScheduleId()}); err != nil {
+		t.Fatalf("DeleteSchedule failed: %v", err)
+	}
+	_, err = client.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: sched.GetScheduleId()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound after delete, got %v", err)
+	}
+}
+
+func TestTaskService_Schedules_Validation(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	tmpl := &taskv1.CreateTaskRequest{Title: "job"}
+	for name, req := range map[string]*taskv1.CreateScheduleRequest{
+		"bad cron":        {Cron: "* * *", Template: tmpl},
+		"never fires":     {Cron: "0 0 30 2 *", Template: tmpl},
+		"bad time zone":   {Cron: "@daily", TimeZone: "Mars/Olympus", Template: tmpl},
+		"no template":     {Cron: "@daily"},
+		"untitled":        {Cron: "@daily", Template: &taskv1.CreateTaskRequest{}},
+		"unknown overlap": {Cron: "@daily", Template: tmpl, OverlapPolicy: 42},
+	} {
+		if _, err := client.CreateSchedule(ctxWithAuth("devtoken"), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+	_, err := client.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{Cron: "@daily", Template: &taskv1.CreateTaskRequest{Title: "job", Queue: "missing"}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for an unknown queue, got %v", err)
+	}
+}
+
+func TestTaskService_Schedules_OverlapPolicies(t *testing.T) {
+	svc := NewTaskServiceServer()
+	create := func(policy taskv1.OverlapPolicy) *taskv1.Schedule {
+		sched, err := svc.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{
+			Cron:          "0 0 1 1 *",
+			Template:      &taskv1.CreateTaskRequest{Title: "yearly"},
+			OverlapPolicy: policy,
+		})
+		if err != nil {
+			t.Fatalf("CreateSchedule failed: %v", err)
+		}
+		return sched
+	}
+	skip := create(taskv1.OverlapPolicy_OVERLAP_POLICY_SKIP)
+	queue := create(taskv1.OverlapPolicy_OVERLAP_POLICY_QUEUE)
+	replace := create(taskv1.OverlapPolicy_OVERLAP_POLICY_REPLACE)
+
+	first := skip.GetNextRunAt().AsTime()
+	svc.fireSchedules(first)
+	svc.fireSchedules(first.AddDate(1, 0, 0))
+
+	if tasks := scheduleTasks(svc, skip.GetScheduleId()); len(tasks) != 1 {
+		t.Fatalf("skip: expected the second run to be skipped, got %d tasks", len(tasks))
+	}
+	if tasks := scheduleTasks(svc, queue.GetScheduleId()); len(tasks) != 1 {
+		t.Fatalf("queue: expected the second run held back, got %d tasks", len(tasks))
+	}
+	if got, _ := svc.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: queue.GetScheduleId()}); got.GetQueuedRuns() != 1 {
+		t.Fatalf("queue: expected one queued run, got %v", got)
+	}
+	tasks := scheduleTasks(svc, replace.GetScheduleId())
+	if len(tasks) != 2 || tasks[0].GetStatus() != taskv1.TaskStatus_TASK_STATUS_CANCELED || tasks[1].GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING {
+		t.Fatalf("replace: expected the first task CANCELED and the second PENDING, got %v", tasks)
+	}
+	got, err := svc.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: replace.GetScheduleId()})
+	if err != nil {
+		t.Fatalf("GetSchedule failed: %v", err)
+	}
+	if got.GetLastTaskId() != tasks[1].GetTaskId() || !got.GetNextRunAt().AsTime().Equal(first.AddDate(2, 0, 0)) {
+		t.Fatalf("expected last_task_id and next_run_at to advance, got %v", got)
+	}
+	if tasks[1].GetTitle() != "yearly" {
+		t.Fatalf("expected the task to follow the template, got %v", tasks[1])
+	}
+}
+
+func TestTaskService_Schedules_CatchUp(t *testing.T) {
+	svc := NewTaskServiceServer()
+	create := func(catch_up bool) *taskv1.Schedule {
+		sched, err := svc.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{
+			Cron:          "@daily",
+			Template:      &taskv1.CreateTaskRequest{Title: "daily"},
+			OverlapPolicy: taskv1.OverlapPolicy_OVERLAP_POLICY_QUEUE,
+			CatchUp:       catch_up,
+		})
+		if err != nil {
+			t.Fatalf("CreateSchedule failed: %v", err)
+		}
+		return sched
+	}
+	latest := create(false)
+	all := create(true)
+
+	// Three days late: four runs are due.
+	svc.fireSchedules(latest.GetNextRunAt().AsTime().AddDate(0, 0, 3))
+	if n := len(scheduleTasks(svc, latest.GetScheduleId())); n != 1 {
+		t.Fatalf("expected only the latest run without catch_up, got %d tasks", n)
+	}
+	// The runs queue behind each other.
+	got, err := svc.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: all.GetScheduleId()})
+	if err != nil {
+		t.Fatalf("GetSchedule failed: %v", err)
+	}
+	if n := len(scheduleTasks(svc, all.GetScheduleId())); n != 1 || got.GetQueuedRuns() != 3 {
+		t.Fatalf("expected every missed run with catch_up, got %d tasks and %d queued runs", n, got.GetQueuedRuns())
+	}
+}
+
+func TestTaskService_Schedules_QueuedRunsDoNotOverlap(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	sched, err := client.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{
+		Cron:          "0 0 1 1 *",
+		Template:      &taskv1.CreateTaskRequest{Title: "yearly"},
+		OverlapPolicy: taskv1.OverlapPolicy_OVERLAP_POLICY_QUEUE,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	first := sched.GetNextRunAt().AsTime()
+	svc.fireSchedules(first)
+	running, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w1"})
+	if err != nil || running.GetTask() == nil {
+		t.Fatalf("ClaimTask failed: %v, %v", running, err)
+	}
+
+	// The second run comes due while the first is still running.
+	svc.fireSchedules(first.AddDate(1, 0, 0))
+	idle, err := client.ClaimTask(ctxWithAuth("devtoken"), &taskv1.ClaimTaskRequest{WorkerId: "w2"})
+	if err != nil || idle.GetTask() != nil {
+		t.Fatalf("expected nothing to claim while the first run is running, got %v, %v", idle, err)
+	}
+
+	done, err := client.CompleteTask(ctxWithAuth("devtoken"), &taskv1.CompleteTaskRequest{TaskId: running.GetTask().GetTaskId(), WorkerId: "w1", LeaseId: running.GetTask().GetLease().GetLeaseId()})
+	if err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(scheduleTasks(svc, sched.GetScheduleId())) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the queued run was never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	second := scheduleTasks(svc, sched.GetScheduleId())[1]
+	if second.GetCreatedAt().AsTime().Before(done.GetUpdatedAt().AsTime()) {
+		t.Fatalf("expected the queued run to start after the first finished, got %v", second)
+	}
+	got, err := client.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: sched.GetScheduleId()})
+	if err != nil || got.GetQueuedRuns() != 0 || got.GetLastTaskId() != second.GetTaskId() {
+		t.Fatalf("expected the queued run taken off the schedule, got %v, %v", got, err)
+	}
+}
+
+func TestTaskService_Schedules_QueuedRunStartedBeforeDueRun(t *testing.T) {
+	svc := NewTaskServiceServer()
+	sched, err := svc.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{
+		Cron:          "0 0 1 1 *",
+		Template:      &taskv1.CreateTaskRequest{Title: "yearly"},
+		OverlapPolicy: taskv1.OverlapPolicy_OVERLAP_POLICY_QUEUE,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	first := sched.GetNextRunAt().AsTime()
+	svc.fireSchedules(first)
+	svc.fireSchedules(first.AddDate(1, 0, 0))
+
+	// Finish the first run without waking the runner, so the next pass both
+	// starts the queued run and finds the third run due.
+	svc.mu.Lock()
+	done := proto.Clone(scheduleTasksLocked(svc, sched.GetScheduleId())[0]).(*taskv1.Task)
+	done.Status = taskv1.TaskStatus_TASK_STATUS_COMPLETED
+	svc.swapTaskLocked(done)
+	svc.mu.Unlock()
+	svc.fireSchedules(first.AddDate(2, 0, 0))
+
+	var unfinished int
+	for _, task := range scheduleTasks(svc, sched.GetScheduleId()) {
+		if !isTerminal(task.GetStatus()) {
+			unfinished++
+		}
+	}
+	got, err := svc.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: sched.GetScheduleId()})
+	if err != nil || unfinished != 1 || got.GetQueuedRuns() != 1 {
+		t.Fatalf("expected one running task and one queued run, got %d unfinished and %v, %v", unfinished, got, err)
+	}
+}
+
+func TestTaskService_Schedules_SurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	svc, _ := newStoreServer(t, path)
+	sched, err := svc.CreateSchedule(ctxWithAuth("devtoken"), &taskv1.CreateScheduleRequest{Cron: "0 0 1 1 *", Template: &taskv1.CreateTaskRequest{Title: "yearly"}})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	svc.fireSchedules(sched.GetNextRunAt().AsTime())
+	fired, _ := svc.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: sched.GetScheduleId()})
+
+	restarted, _ := newStoreServer(t, path)
+	got, err := restarted.GetSchedule(ctxWithAuth("devtoken"), &taskv1.GetScheduleRequest{ScheduleId: sched.GetScheduleId()})
+	if err != nil {
+		t.Fatalf("GetSchedule after restart failed: %v", err)
+	}
+	if got.GetLastTaskId() == "" || got.GetLastTaskId() != fired.GetLastTaskId() || !got.GetNextRunAt().AsTime().Equal(fired.GetNextRunAt().AsTime()) {
+		t.Fatalf("expected the fired schedule after restart, got %v", got)
+	}
+	if _, err := restarted.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: got.GetLastTaskId()}); err != nil {
+		t.Fatalf("expected the created task after restart: %v", err)
+	}
+}
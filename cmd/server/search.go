@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/search"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func searchFields(task *taskv1.Task) []search.Field {
+	return []search.Field{
+		{Text: task.GetTitle(), Weight: 3},
+		{Text: task.GetDescription(), Weight: 1},
+	}
+}
+
+// rebuildSearchIndex re-indexes every stored task, e.g. after loading them
+// at startup.
+func (s *TaskServiceServer) rebuildSearchIndex() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = search.New()
+	for _, task := range s.taskSlice {
+		s.index.Put(task.TaskId, searchFields(task)...)
+	}
+	log.Printf("search index rebuilt with %d tasks", s.index.Len())
+}
+
+func (s *TaskServiceServer) SearchTasks(ctx context.Context, req *taskv1.SearchTasksRequest) (*taskv1.SearchTasksResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if len(search.Tokenize(query)) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query must contain at least one word")
+	}
+	page_size := req.GetPageSize()
+	if page_size <= 0 {
+		page_size = 10
+	} else if page_size > 100 {
+		page_size = 100
+	}
+	offset := 0
+	if page_token := req.GetPageToken(); page_token != "" {
+		var err error
+		offset, err = strconv.Atoi(page_token)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hits := s.index.Search(query)
+	res := &taskv1.SearchTasksResponse{Results: []*taskv1.SearchResult{}}
+	if offset >= len(hits) {
+		return res, nil
+	}
+	end := min(offset+int(page_size), len(hits))
+	for _, hit := range hits[offset:end] {
+		res.Results = append(res.Results, &taskv1.SearchResult{
+			Task:    s.taskMap[hit.ID],
+			Score:   hit.Score,
+			Snippet: hit.Snippet,
+		})
+	}
+	if end < len(hits) {
+		res.NextPageToken = strconv.Itoa(end)
+	}
+	return res, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestTaskService_SearchTasks(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	for _, req := range []*taskv1.CreateTaskRequest{
+		{Title: "Rotate certificates", Description: "renew the TLS certs on the gateway"},
+		{Title: "Upgrade gateway", Description: "new certificate handling needs testing"},
+		{Title: "Buy milk"},
+	} {
+		if _, err := client.CreateTask(ctxWithAuth("devtoken"), req); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	res, err := client.SearchTasks(ctxWithAuth("devtoken"), &taskv1.SearchTasksRequest{Query: "cert"})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(res.GetResults()) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(res.GetResults()))
+	}
+	if res.GetResults()[0].GetTask().GetTitle() != "Rotate certificates" {
+		t.Fatalf("expected title match first, got %q", res.GetResults()[0].GetTask().GetTitle())
+	}
+	if res.GetResults()[0].GetSnippet() != "Rotate <em>certificates</em>" {
+		t.Fatalf("unexpected snippet %q", res.GetResults()[0].GetSnippet())
+	}
+
+	page, err := client.SearchTasks(ctxWithAuth("devtoken"), &taskv1.SearchTasksRequest{Query: "cert", PageSize: 1, PageToken: "1"})
+	if err != nil {
+		t.Fatalf("SearchTasks page 2 failed: %v", err)
+	}
+	if len(page.GetResults()) != 1 || page.GetResults()[0].GetTask().GetTitle() != "Upgrade gateway" || page.GetNextPageToken() != "" {
+		t.Fatalf("unexpected second page: %v", page)
+	}
+}
+
+func TestTaskService_SearchTasks_ReflectsUpdates(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "nightly export"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	result, _ := structpb.NewStruct(map[string]any{"rows": 10})
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: created.GetTask().GetTaskId(), Result: result}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	res, err := client.SearchTasks(ctxWithAuth("devtoken"), &taskv1.SearchTasksRequest{Query: "export"})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if len(res.GetResults()) != 1 || res.GetResults()[0].GetTask().GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected the updated task, got %v", res.GetResults())
+	}
+}
+
+func TestTaskService_SearchTasks_InvalidArgument(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	_, err := client.SearchTasks(ctxWithAuth("devtoken"), &taskv1.SearchTasksRequest{Query: " ,. "})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+}
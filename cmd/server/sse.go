@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// defaultSSEHeartbeat keeps idle connections alive through proxies.
+const defaultSSEHeartbeat = 15 * time.Second
+
+// newSSEHandler serves WatchTask and WatchTasks as text/event-stream for
+// clients that cannot consume gRPC streams:
+//
+//	GET /v1/tasks/{task_id}/events
+//	GET /v1/events?status=RUNNING&label=team=infra&parent=<id>&queue=<name>&snapshot=true
+//
+// Each event's id is its revision, so a reconnecting EventSource resumes
+// through Last-Event-ID. Callers authenticate with the bearer tokens in
+// users, either in the Authorization header or, since browsers cannot set
+// headers on an EventSource, as access_token.
+func newSSEHandler(s *TaskServiceServer, users map[string]string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/tasks/{task_id}/events", func(w http.ResponseWriter, r *http.Request) {
+		serveSSE(w, r, users, func(stream *sseStream, since int64, heartbeat *durationpb.Duration) error {
+			return s.WatchTask(&taskv1.WatchTaskRequest{
+				TaskId:            r.PathValue("task_id"),
+				SinceRevision:     since,
+				HeartbeatInterval: heartbeat,
+			}, stream)
+		})
+	})
+	mux.HandleFunc("GET /v1/events", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		req := &taskv1.WatchTasksRequest{
+			Filter:              &taskv1.TaskFilter{ParentId: q.Get("parent"), Queue: q.Get("queue"), Labels: map[string]string{}},
+			SendInitialSnapshot: q.Get("snapshot") == "true",
+		}
+		for _, v := range q["status"] {
+			st, ok := taskv1.TaskStatus_value["TASK_STATUS_"+strings.ToUpper(v)]
+			if !ok {
+				http.Error(w, "unknown status "+strconv.Quote(v), http.StatusBadRequest)
+				return
+			}
+			req.Filter.Statuses = append(req.Filter.Statuses, taskv1.TaskStatus(st))
+		}
+		for _, v := range q["label"] {
+			k, val, ok := strings.Cut(v, "=")
+			if !ok {
+				http.Error(w, "label must be key=value", http.StatusBadRequest)
+				return
+			}
+			req.Filter.Labels[k] = val
+		}
+		serveSSE(w, r, users, func(stream *sseStream, since int64, heartbeat *durationpb.Duration) error {
+			req.SinceRevision = since
+			req.HeartbeatInterval = heartbeat
+			return s.WatchTasks(req, stream)
+		})
+	})
+	return mux
+}
+
+// serveSSE authenticates the request, parses the resume and heartbeat
+// options and runs watch against an event-stream response.
+func serveSSE(w http.ResponseWriter, r *http.Request, users map[string]string, watch func(*sseStream, int64, *durationpb.Duration) error) {
+	header := r.Header.Get("Authorization")
+	if token := r.URL.Query().Get("access_token"); header == "" && token != "" {
+		header = "Bearer " + token
+	}
+	user, err := userForAuthorization(users, header)
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		http.Error(w, status.Convert(err).Message(), httpStatusFromCode(status.Code(err)))
+		return
+	}
+
+	var since int64
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		if since, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+	heartbeat := defaultSSEHeartbeat
+	if raw := r.URL.Query().Get("heartbeat"); raw != "" {
+		if heartbeat, err = time.ParseDuration(raw); err != nil {
+			http.Error(w, "invalid heartbeat", http.StatusBadRequest)
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	stream := &sseStream{ctx: context.WithValue(r.Context(), principalKey{}, user), w: w, flusher: flusher}
+	err = watch(stream, since, durationpb.New(heartbeat))
+	switch {
+	case !stream.started && err != nil:
+		http.Error(w, status.Convert(err).Message(), httpStatusFromCode(status.Code(err)))
+	case err != nil:
+		if r.Context().Err() == nil {
+			stream.write("error", "", status.Convert(err).Proto())
+		}
+	default:
+		// Tells the client the watch is over rather than to reconnect.
+		stream.write("end", "", nil)
+	}
+}
+
+// sseStream adapts an HTTP response to the watch handlers, which only use
+// Context and Send.
+type sseStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	w       http.ResponseWriter
+	flusher http.Flusher
+	started bool
+}
+
+func (st *sseStream) Context() context.Context {
+	return st.ctx
+}
+
+func (st *sseStream) Send(ev *taskv1.TaskEvent) error {
+	id := ""
+	// A GAP's revision is the newest event lost; resuming after it would
+	// skip the rest, so it gets no id.
+	if ev.GetRevision() > 0 && ev.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_GAP {
+		id = strconv.FormatInt(ev.GetRevision(), 10)
+	}
+	name := strings.ToLower(strings.TrimPrefix(ev.GetType().String(), "TASK_EVENT_TYPE_"))
+	return st.write(name, id, ev)
+}
+
+func (st *sseStream) write(event, id string, m proto.Message) error {
+	data := []byte("{}")
+	if m != nil {
+		var err error
+		if data, err = protojson.Marshal(m); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	if !st.started {
+		st.w.Header().Set("Content-Type", "text/event-stream")
+		st.w.Header().Set("Cache-Control", "no-cache")
+		st.w.WriteHeader(http.StatusOK)
+		st.started = true
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(st.w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(st.w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	st.flusher.Flush()
+	return nil
+}
+
+func httpStatusFromCode(c codes.Code) int {
+	switch c {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.OutOfRange:
+		// The resume point is gone; the client has to start over.
+		return http.StatusGone
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+func readSSEEvent(t *testing.T, br *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event stream failed: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return ev
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			ev.id = value
+		case "event":
+			ev.event = value
+		case "data":
+			ev.data = value
+		}
+	}
+}
+
+func openSSE(t *testing.T, url string, header http.Header) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = header
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	return resp
+}
+
+func TestSSE_Auth(t *testing.T) {
+	svc := NewTaskServiceServer()
+	srv := httptest.NewServer(newSSEHandler(svc, map[string]string{"devtoken": devUser}))
+	defer srv.Close()
+
+	resp := openSSE(t, srv.URL+"/v1/events", http.Header{})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", resp.StatusCode)
+	}
+	resp = openSSE(t, srv.URL+"/v1/events", http.Header{"Authorization": {"Bearer nope"}})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 with a bad token, got %d", resp.StatusCode)
+	}
+	resp = openSSE(t, srv.URL+"/v1/tasks/missing/events?access_token=devtoken", http.Header{})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown task, got %d", resp.StatusCode)
+	}
+}
+
+func TestSSE_WatchTaskUntilTerminal(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	srv := httptest.NewServer(newSSEHandler(svc, map[string]string{"devtoken": devUser}))
+	defer srv.Close()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "browser"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	resp := openSSE(t, srv.URL+"/v1/tasks/"+task_id+"/events", http.Header{"Authorization": {"Bearer devtoken"}})
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected 200 text/event-stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	br := bufio.NewReader(resp.Body)
+	snapshot := readSSEEvent(t, br)
+	if snapshot.event != "snapshot" || snapshot.id != "1" {
+		t.Fatalf("expected snapshot with id 1, got %+v", snapshot)
+	}
+
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	changed := readSSEEvent(t, br)
+	if changed.event != "status_changed" || changed.id != "2" {
+		t.Fatalf("expected status_changed with id 2, got %+v", changed)
+	}
+	var ev taskv1.TaskEvent
+	if err := protojson.Unmarshal([]byte(changed.data), &ev); err != nil {
+		t.Fatalf("event data is not a TaskEvent: %v", err)
+	}
+	if ev.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected COMPLETED, got %v", ev.GetStatus())
+	}
+	if end := readSSEEvent(t, br); end.event != "end" {
+		t.Fatalf("expected end event, got %+v", end)
+	}
+}
+
+func TestSSE_WatchTasksResumesFromLastEventID(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	srv := httptest.NewServer(newSSEHandler(svc, map[string]string{"devtoken": devUser}))
+	defer srv.Close()
+
+	var ids []string
+	for _, labels := range []map[string]string{{"team": "infra"}, {"team": "web"}, {"team": "infra"}} {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "t", Labels: labels})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, created.GetTask().GetTaskId())
+	}
+
+	resp := openSSE(t, srv.URL+"/v1/events?label=team=infra", http.Header{"Authorization": {"Bearer devtoken"}, "Last-Event-ID": {"1"}})
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	got := readSSEEvent(t, bufio.NewReader(resp.Body))
+	var ev taskv1.TaskEvent
+	if err := protojson.Unmarshal([]byte(got.data), &ev); err != nil {
+		t.Fatalf("event data is not a TaskEvent: %v", err)
+	}
+	if got.event != "created" || got.id != strconv.Itoa(3) || ev.GetTaskId() != ids[2] {
+		t.Fatalf("expected the third task's created event with id 3, got %+v", got)
+	}
+
+	resp = openSSE(t, srv.URL+"/v1/events", http.Header{"Authorization": {"Bearer devtoken"}, "Last-Event-ID": {"99"}})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGone {
+		t.Fatalf("expected 410 for a revision ahead of the server, got %d", resp.StatusCode)
+	}
+}
+
+func TestSSE_WatchTasksByQueue(t *testing.T) {
+	svc := NewTaskServiceServer()
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+	srv := httptest.NewServer(newSSEHandler(svc, map[string]string{"devtoken": devUser}))
+	defer srv.Close()
+	if _, err := client.CreateQueue(ctxWithAuth("devtoken"), &taskv1.CreateQueueRequest{Name: "images"}); err != nil {
+		t.Fatalf("CreateQueue failed: %v", err)
+	}
+
+	var want string
+	for _, queue := range []string{"", "images"} {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "t", Queue: queue})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		want = created.GetTask().GetTaskId()
+	}
+
+	resp := openSSE(t, srv.URL+"/v1/events?queue=images&snapshot=true", http.Header{"Authorization": {"Bearer devtoken"}})
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	got := readSSEEvent(t, bufio.NewReader(resp.Body))
+	var ev taskv1.TaskEvent
+	if err := protojson.Unmarshal([]byte(got.data), &ev); err != nil {
+		t.Fatalf("event data is not a TaskEvent: %v", err)
+	}
+	if ev.GetTaskId() != want {
+		t.Fatalf("expected only the task in queue images, got %+v", got)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// throughputWindows are the sliding windows reported by GetTaskStats. The
+// longest one bounds how much history rateCounter keeps.
+var throughputWindows = []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+
+// maxDurationSamples bounds the per-status samples kept for percentiles.
+const maxDurationSamples = 1024
+
+// statsTracker maintains task statistics incrementally from each change, so
+// GetTaskStats never scans the task list. All methods are called with
+// TaskServiceServer.mu held.
+type statsTracker struct {
+	now func() time.Time
+
+	total       int64
+	byStatus    map[taskv1.TaskStatus]int64
+	byLabel     map[string]int64
+	created     rateCounter
+	completed   rateCounter
+	statusSince map[string]time.Time
+	durations   map[taskv1.TaskStatus]*durationSamples
+}
+
+func newStatsTracker(now func() time.Time) *statsTracker {
+	return &statsTracker{
+		now:         now,
+		byStatus:    make(map[taskv1.TaskStatus]int64),
+		byLabel:     make(map[string]int64),
+		statusSince: make(map[string]time.Time),
+		durations:   make(map[taskv1.TaskStatus]*durationSamples),
+	}
+}
+
+// observe records a task moving from old to new. old is nil for a newly
+// created task and new is nil for a removed one.
+func (st *statsTracker) observe(old, new *taskv1.Task) {
+	now := st.now()
+	if old != nil {
+		st.total--
+		st.byStatus[old.GetStatus()]--
+		for k, v := range old.GetLabels() {
+			st.byLabel[k+"="+v]--
+			if st.byLabel[k+"="+v] == 0 {
+				delete(st.byLabel, k+"="+v)
+			}
+		}
+	}
+	if new != nil {
+		st.total++
+		st.byStatus[new.GetStatus()]++
+		for k, v := range new.GetLabels() {
+			st.byLabel[k+"="+v]++
+		}
+	}
+
+	switch {
+	case old == nil && new != nil:
+		st.created.add(now)
+		st.statusSince[new.GetTaskId()] = now
+	case old != nil && new == nil:
+		delete(st.statusSince, old.GetTaskId())
+	case old.GetStatus() != new.GetStatus():
+		if since, ok := st.statusSince[old.GetTaskId()]; ok {
+			samples := st.durations[old.GetStatus()]
+			if samples == nil {
+				samples = &durationSamples{}
+				st.durations[old.GetStatus()] = samples
+			}
+			samples.add(now.Sub(since))
+		}
+		st.statusSince[new.GetTaskId()] = now
+		if new.GetStatus() == taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+			st.completed.add(now)
+		}
+	}
+}
+
+// restore counts a task loaded from the store. Unlike observe it leaves
+// throughput alone, and time in the current status runs from the task's
+// last update.
+func (st *statsTracker) restore(task *taskv1.Task) {
+	st.total++
+	st.byStatus[task.GetStatus()]++
+	for k, v := range task.GetLabels() {
+		st.byLabel[k+"="+v]++
+	}
+	st.statusSince[task.GetTaskId()] = task.GetUpdatedAt().AsTime()
+}
+
+func (st *statsTracker) snapshot() *taskv1.TaskStats {
+	now := st.now()
+	res := &taskv1.TaskStats{
+		Total:    st.total,
+		ByStatus: make(map[string]int64),
+		ByLabel:  make(map[string]int64),
+	}
+	for status, n := range st.byStatus {
+		if n > 0 {
+			res.ByStatus[status.String()] = n
+		}
+	}
+	for label, n := range st.byLabel {
+		res.ByLabel[label] = n
+	}
+	for _, w := range throughputWindows {
+		created, completed := st.created.count(now, w), st.completed.count(now, w)
+		res.Throughput = append(res.Throughput, &taskv1.ThroughputWindow{
+			Window:             durationpb.New(w),
+			Created:            created,
+			Completed:          completed,
+			CreatedPerMinute:   float64(created) / w.Minutes(),
+			CompletedPerMinute: float64(completed) / w.Minutes(),
+		})
+	}
+	for status := range taskv1.TaskStatus_name {
+		samples := st.durations[taskv1.TaskStatus(status)]
+		if samples == nil || len(samples.values) == 0 {
+			continue
+		}
+		sorted := slices.Clone(samples.values)
+		slices.Sort(sorted)
+		res.TimeInStatus = append(res.TimeInStatus, &taskv1.StatusDuration{
+			Status:  taskv1.TaskStatus(status),
+			Samples: samples.seen,
+			Median:  durationpb.New(percentile(sorted, 50)),
+			P95:     durationpb.New(percentile(sorted, 95)),
+		})
+	}
+	slices.SortFunc(res.TimeInStatus, func(a, b *taskv1.StatusDuration) int {
+		return int(a.GetStatus() - b.GetStatus())
+	})
+	return res
+}
+
+func (s *TaskServiceServer) GetTaskStats(ctx context.Context, req *taskv1.GetTaskStatsRequest) (*taskv1.TaskStats, error) {
+	s.mu.RLock()
+	res := s.stats.snapshot()
+	s.mu.RUnlock()
+
+	subs, dropped := s.bus.Stats()
+	res.DroppedEventsTotal = dropped
+	for _, sub := range subs {
+		res.WatchStreams = append(res.WatchStreams, &taskv1.WatchStreamStats{
+			Name:    sub.Name,
+			Policy:  sub.Policy.String(),
+			Queued:  int64(sub.Queued),
+			Dropped: sub.Dropped,
+		})
+	}
+	slices.SortFunc(res.WatchStreams, func(a, b *taskv1.WatchStreamStats) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return res, nil
+}
+
+// rateCounter counts events per second over the longest throughput window.
+type rateCounter struct {
+	buckets [3600]int64
+	seconds [3600]int64
+}
+
+func (r *rateCounter) add(t time.Time) {
+	sec := t.Unix()
+	i := sec % int64(len(r.buckets))
+	if r.seconds[i] != sec {
+		r.seconds[i] = sec
+		r.buckets[i] = 0
+	}
+	r.buckets[i]++
+}
+
+func (r *rateCounter) count(now time.Time, window time.Duration) int64 {
+	cutoff := now.Unix() - int64(window/time.Second)
+	var n int64
+	for i, sec := range r.seconds {
+		if sec > cutoff && sec <= now.Unix() {
+			n += r.buckets[i]
+		}
+	}
+	return n
+}
+
+// durationSamples keeps the most recent maxDurationSamples durations.
+type durationSamples struct {
+	values []time.Duration
+	next   int
+	seen   int64
+}
+
+func (d *durationSamples) add(v time.Duration) {
+	d.seen++
+	if len(d.values) < maxDurationSamples {
+		d.values = append(d.values, v)
+		return
+	}
+	d.values[d.next] = v
+	d.next = (d.next + 1) % maxDurationSamples
+}
+
+// percentile uses the nearest-rank method on an ascending slice.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTaskService_GetTaskStats(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	svc := NewTaskServiceServer()
+	svc.stats = newStatsTracker(clock.Now)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	var ids []string
+	for _, team := range []string{"infra", "infra", "web"} {
+		resp, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "job", Labels: map[string]string{"team": team}})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		ids = append(ids, resp.GetTask().GetTaskId())
+	}
+	clock.Advance(10 * time.Second)
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: ids[0]}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	clock.Advance(30 * time.Second)
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: ids[1]}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	clock.Advance(2 * time.Minute)
+
+	stats, err := client.GetTaskStats(ctxWithAuth("devtoken"), &taskv1.GetTaskStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetTaskStats failed: %v", err)
+	}
+	if stats.GetTotal() != 3 {
+		t.Fatalf("expected total 3, got %d", stats.GetTotal())
+	}
+	if stats.GetByStatus()["TASK_STATUS_PENDING"] != 1 || stats.GetByStatus()["TASK_STATUS_COMPLETED"] != 2 {
+		t.Fatalf("unexpected by_status: %v", stats.GetByStatus())
+	}
+	if stats.GetByLabel()["team=infra"] != 2 || stats.GetByLabel()["team=web"] != 1 {
+		t.Fatalf("unexpected by_label: %v", stats.GetByLabel())
+	}
+
+	minute, five := stats.GetThroughput()[0], stats.GetThroughput()[1]
+	if minute.GetCreated() != 0 || minute.GetCompleted() != 0 {
+		t.Fatalf("expected nothing in the last minute, got %v", minute)
+	}
+	if five.GetCreated() != 3 || five.GetCompleted() != 2 {
+		t.Fatalf("expected 3 created and 2 completed in 5m, got %v", five)
+	}
+
+	if len(stats.GetTimeInStatus()) != 1 {
+		t.Fatalf("expected only PENDING durations, got %v", stats.GetTimeInStatus())
+	}
+	pending := stats.GetTimeInStatus()[0]
+	if pending.GetSamples() != 2 || pending.GetMedian().AsDuration() != 10*time.Second || pending.GetP95().AsDuration() != 40*time.Second {
+		t.Fatalf("unexpected PENDING durations: %v", pending)
+	}
+}
+
+func TestTaskService_GetTaskStats_WatchStreams(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WatchTasks(ctx, &taskv1.WatchTasksRequest{HeartbeatInterval: durationpb.New(100 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("WatchTasks failed: %v", err)
+	}
+	// A heartbeat proves the subscription exists.
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("WatchTasks Recv failed: %v", err)
+	}
+
+	stats, err := client.GetTaskStats(ctxWithAuth("devtoken"), &taskv1.GetTaskStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetTaskStats failed: %v", err)
+	}
+	if len(stats.GetWatchStreams()) != 1 {
+		t.Fatalf("expected 1 watch stream, got %v", stats.GetWatchStreams())
+	}
+	ws := stats.GetWatchStreams()[0]
+	if ws.GetName() != "WatchTasks by "+devUser || ws.GetPolicy() != "drop-oldest" || ws.GetDropped() != 0 {
+		t.Fatalf("unexpected watch stream stats: %v", ws)
+	}
+}
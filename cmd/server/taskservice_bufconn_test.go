@@ -23,51 +23,60 @@ const bufSize = 1024 * 1024
 func ctxWithAuth(token string) context.Context {
 	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
 }
-func newBufconnClient(t *testing.T) (taskv1.TaskServiceClient, func()) {
-	t.Helper()
-
-	lis := bufconn.Listen(bufSize)
 
-	svc := NewTaskServiceServer()
+// bufconnOption configures the server behind a bufconn client.
+type bufconnOption func(*bufconnConfig)
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(authUnaryInterceptor("devtoken")))
-	taskv1.RegisterTaskServiceServer(grpcServer, svc)
-
-	go func() {
-		_ = grpcServer.Serve(lis)
-	}()
-
-	dialer := func(context.Context, string) (net.Conn, error) {
-		return lis.Dial()
-	}
+type bufconnConfig struct {
+	svc   *TaskServiceServer
+	users map[string]string
+}
 
-	conn, err := grpc.DialContext(
-		context.Background(),
-		"bufnet",
-		grpc.WithContextDialer(dialer),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		t.Fatalf("DialContext(bufnet) failed: %v", err)
+// withServer serves an already configured svc, for tests that need to set
+// fields before the first RPC.
+func withServer(svc *TaskServiceServer) bufconnOption {
+	return func(c *bufconnConfig) {
+		c.svc = svc
 	}
+}
 
-	cleanup := func() {
-		_ = conn.Close()
-		grpcServer.Stop()
-		_ = lis.Close()
+// withUsers accepts the tokens in users, mapped to user names, instead of
+// only devtoken.
+func withUsers(users map[string]string) bufconnOption {
+	return func(c *bufconnConfig) {
+		c.users = users
 	}
+}
 
-	return taskv1.NewTaskServiceClient(conn), cleanup
+func newBufconnClient(t *testing.T, opts ...bufconnOption) (taskv1.TaskServiceClient, func()) {
+	t.Helper()
+	client, _, cleanup := newBufconnClientWithServer(t, opts...)
+	return client, cleanup
 }
 
-func newBufconnClientWithServer(t *testing.T) (taskv1.TaskServiceClient, *TaskServiceServer, func()) {
+func newBufconnClientWithServer(t *testing.T, opts ...bufconnOption) (taskv1.TaskServiceClient, *TaskServiceServer, func()) {
 	t.Helper()
 
+	var cfg bufconnConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	lis := bufconn.Listen(bufSize)
 
-	svc := NewTaskServiceServer()
+	svc := cfg.svc
+	if svc == nil {
+		svc = NewTaskServiceServer()
+	}
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(authUnaryInterceptor("devtoken")))
+	unary, stream := authUnaryInterceptor("devtoken"), authStreamInterceptor("devtoken")
+	if cfg.users != nil {
+		unary, stream = authUnaryInterceptorWithUsers(cfg.users), authStreamInterceptorWithUsers(cfg.users)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(unary),
+		grpc.StreamInterceptor(stream),
+	)
 	taskv1.RegisterTaskServiceServer(grpcServer, svc)
 
 	go func() {
@@ -512,6 +521,19 @@ func TestTaskService_BulkCreate(t *testing.T) {
 	}
 }
 
+func TestTaskService_Stream_Unauthenticated(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+	stream, err := client.BulkCreate(context.Background())
+	if err != nil {
+		t.Fatalf("BulkCreate failed to start: %v", err)
+	}
+	_, err = stream.CloseAndRecv()
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", status.Code(err))
+	}
+}
+
 func TestTaskService_TaskConsole(t *testing.T) {
 	client, cleanup := newBufconnClient(t)
 	defer cleanup()
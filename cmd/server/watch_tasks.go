@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/eventbus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// matchesFilter reports whether task, in the given status, is selected by f.
+// A nil filter matches everything.
+func matchesFilter(f *taskv1.TaskFilter, task *taskv1.Task, st taskv1.TaskStatus) bool {
+	if len(f.GetStatuses()) > 0 && !slices.Contains(f.GetStatuses(), st) {
+		return false
+	}
+	for k, v := range f.GetLabels() {
+		if got, ok := task.GetLabels()[k]; !ok || got != v {
+			return false
+		}
+	}
+	if f.GetParentId() != "" && task.GetParentId() != f.GetParentId() {
+		return false
+	}
+	if f.GetQueue() != "" && taskQueue(task) != f.GetQueue() {
+		return false
+	}
+	return true
+}
+
+// eventMatchesFilter selects task changes where the task matched the filter
+// before or after the change, so watchers also see tasks leaving their view.
+func eventMatchesFilter(f *taskv1.TaskFilter, ev *taskv1.TaskEvent) bool {
+	task := ev.GetTask()
+	if task == nil {
+		return false
+	}
+	return matchesFilter(f, task, ev.GetStatus()) || matchesFilter(f, task, ev.GetPreviousStatus())
+}
+
+// WatchTasks streams changes to every task matching the request filter,
+// optionally preceded by a snapshot of the tasks that match right now and
+// by buffered changes after since_revision.
+func (s *TaskServiceServer) WatchTasks(req *taskv1.WatchTasksRequest, stream taskv1.TaskService_WatchTasksServer) error {
+	filter := req.GetFilter()
+	if err := validateLabels(filter.GetLabels()); err != nil {
+		return err
+	}
+	if err := validateHeartbeat(req.GetHeartbeatInterval()); err != nil {
+		return err
+	}
+	if strings.TrimSpace(filter.GetParentId()) != filter.GetParentId() {
+		return status.Error(codes.InvalidArgument, "invalid parent_id")
+	}
+
+	s.mu.RLock()
+	sub, replay, err := s.subscribeSince(req.GetSinceRevision(), "WatchTasks by "+principalFromContext(stream.Context()), func(ev *taskv1.TaskEvent) bool {
+		return eventMatchesFilter(filter, ev)
+	})
+	if err != nil {
+		s.mu.RUnlock()
+		return err
+	}
+	revision := s.bus.Revision()
+	var snapshot []*taskv1.Task
+	if req.GetSendInitialSnapshot() {
+		for _, task := range s.taskSlice {
+			if matchesFilter(filter, task, task.GetStatus()) {
+				snapshot = append(snapshot, task)
+			}
+		}
+	}
+	s.mu.RUnlock()
+	defer sub.Close()
+
+	now := timestamppb.New(time.Now())
+	for _, task := range snapshot {
+		err := stream.Send(&taskv1.TaskEvent{
+			TaskId:         task.GetTaskId(),
+			Type:           taskv1.TaskEventType_TASK_EVENT_TYPE_SNAPSHOT,
+			Status:         task.GetStatus(),
+			PreviousStatus: task.GetStatus(),
+			At:             now,
+			Task:           task,
+			Revision:       revision,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, ev := range replay {
+		if err := stream.Send(ev); err != nil {
+			return err
+		}
+	}
+
+	return s.streamEvents(stream.Context(), sub, req.GetHeartbeatInterval(), stream.Send, nil)
+}
+
+const (
+	minHeartbeatInterval = 100 * time.Millisecond
+	maxHeartbeatInterval = 5 * time.Minute
+)
+
+func validateHeartbeat(d *durationpb.Duration) error {
+	if d == nil {
+		return nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid heartbeat_interval: "+err.Error())
+	}
+	if iv := d.AsDuration(); iv < minHeartbeatInterval || iv > maxHeartbeatInterval {
+		return status.Errorf(codes.InvalidArgument, "heartbeat_interval must be between %s and %s", minHeartbeatInterval, maxHeartbeatInterval)
+	}
+	return nil
+}
+
+// streamEvents forwards live events from sub until the client goes away or
+// last reports true for a sent event. When heartbeat is set, an idle stream
+// gets a HEARTBEAT event carrying the bus revision it is current as of. A
+// subscriber disconnected by the slow-consumer policy ends the stream with
+// ResourceExhausted.
+func (s *TaskServiceServer) streamEvents(ctx context.Context, sub *eventbus.Subscription, heartbeat *durationpb.Duration, send func(*taskv1.TaskEvent) error, last func(*taskv1.TaskEvent) bool) error {
+	defer func() {
+		if n := sub.Dropped(); n > 0 {
+			log.Printf("watch stream dropped %d events", n)
+		}
+	}()
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	if heartbeat != nil {
+		ticker = time.NewTicker(heartbeat.AsDuration())
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Ready():
+			events, err := sub.Drain()
+			if errors.Is(err, eventbus.ErrOverflow) {
+				return status.Error(codes.ResourceExhausted, "watch stream fell too far behind and was disconnected")
+			}
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			for _, ev := range events {
+				if err := send(ev); err != nil {
+					return err
+				}
+				if last != nil && last(ev) {
+					return nil
+				}
+			}
+			if ticker != nil {
+				ticker.Reset(heartbeat.AsDuration())
+			}
+		case <-tick:
+			// Events up to revision are already queued on sub by the time
+			// Revision returns, so only an empty queue means the stream is
+			// really caught up to it.
+			revision := s.bus.Revision()
+			if sub.Len() > 0 {
+				continue
+			}
+			err := send(&taskv1.TaskEvent{
+				Type:     taskv1.TaskEventType_TASK_EVENT_TYPE_HEARTBEAT,
+				At:       timestamppb.New(time.Now()),
+				Revision: revision,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestTaskService_WatchTasks_FilterAndSnapshot(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	parent, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "release"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	parent_id := parent.GetTask().GetTaskId()
+	existing, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "build", ParentId: parent_id, Labels: map[string]string{"team": "infra"}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WatchTasks(ctx, &taskv1.WatchTasksRequest{
+		Filter: &taskv1.TaskFilter{
+			Statuses: []taskv1.TaskStatus{taskv1.TaskStatus_TASK_STATUS_PENDING},
+			Labels:   map[string]string{"team": "infra"},
+			ParentId: parent_id,
+		},
+		SendInitialSnapshot: true,
+	})
+	if err != nil {
+		t.Fatalf("WatchTasks failed: %v", err)
+	}
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTasks Recv failed: %v", err)
+	}
+	if ev.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_SNAPSHOT || ev.GetTaskId() != existing.GetTask().GetTaskId() {
+		t.Fatalf("expected snapshot of existing task, got %v", ev)
+	}
+
+	// Neither of these match: wrong label, no parent.
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "docs", ParentId: parent_id, Labels: map[string]string{"team": "web"}}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "orphan", Labels: map[string]string{"team": "infra"}}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "test", ParentId: parent_id, Labels: map[string]string{"team": "infra"}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	ev, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTasks Recv failed: %v", err)
+	}
+	if ev.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED || ev.GetTaskId() != created.GetTask().GetTaskId() {
+		t.Fatalf("expected created event for matching task, got %v", ev)
+	}
+
+	// Completing a task moves it out of the PENDING filter; the watcher still
+	// hears about it.
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: existing.GetTask().GetTaskId()}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	ev, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTasks Recv failed: %v", err)
+	}
+	if ev.GetTaskId() != existing.GetTask().GetTaskId() || ev.GetPreviousStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING || ev.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected PENDING->COMPLETED event, got %v", ev)
+	}
+}
+
+func TestTaskService_Create_UnknownParent(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	_, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "child", ParentId: "does-not-exist"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", status.Code(err))
+	}
+}
+
+func TestTaskService_WatchTasks_Heartbeat(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "before"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WatchTasks(ctx, &taskv1.WatchTasksRequest{HeartbeatInterval: durationpb.New(100 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("WatchTasks failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("WatchTasks Recv failed: %v", err)
+		}
+		if ev.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_HEARTBEAT || ev.GetRevision() != 1 || ev.GetAt() == nil {
+			t.Fatalf("expected heartbeat at revision 1, got %v", ev)
+		}
+	}
+}
+
+func TestTaskService_WatchTasks_InvalidHeartbeat(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	stream, err := client.WatchTasks(ctxWithAuth("devtoken"), &taskv1.WatchTasksRequest{HeartbeatInterval: durationpb.New(time.Millisecond)})
+	if err != nil {
+		t.Fatalf("WatchTasks failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/eventbus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskService_WatchTask_StreamsRealChanges(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "watch me"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	stream, err := client.WatchTask(ctxWithAuth("devtoken"), &taskv1.WatchTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	snapshot, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTask Recv failed: %v", err)
+	}
+	if snapshot.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_SNAPSHOT || snapshot.GetStatus() != taskv1.TaskStatus_TASK_STATUS_PENDING {
+		t.Fatalf("expected PENDING snapshot first, got %v", snapshot)
+	}
+
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTask Recv failed: %v", err)
+	}
+	if ev.GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED || ev.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected COMPLETED status change, got %v", ev)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected stream to end after terminal status, got %v", err)
+	}
+}
+
+func TestTaskService_WatchTask_AlreadyTerminal(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "done"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+
+	stream, err := client.WatchTask(ctxWithAuth("devtoken"), &taskv1.WatchTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTask Recv failed: %v", err)
+	}
+	if ev.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected COMPLETED snapshot, got %v", ev.GetStatus())
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestTaskService_WatchTask_NotFound(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	stream, err := client.WatchTask(ctxWithAuth("devtoken"), &taskv1.WatchTaskRequest{TaskId: "does-not-exist"})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", status.Code(err))
+	}
+}
+
+func TestTaskService_WatchTask_ResumeFromRevision(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "resume"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	stream, err := client.WatchTask(ctx, &taskv1.WatchTaskRequest{TaskId: task_id})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	snapshot, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTask Recv failed: %v", err)
+	}
+	cancel()
+
+	// Changes made while nobody is watching.
+	for _, body := range []string{"one", "two"} {
+		if _, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: body}); err != nil {
+			t.Fatalf("AddComment failed: %v", err)
+		}
+	}
+
+	stream, err = client.WatchTask(ctxWithAuth("devtoken"), &taskv1.WatchTaskRequest{TaskId: task_id, SinceRevision: snapshot.GetRevision()})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	last := snapshot.GetRevision()
+	for _, body := range []string{"one", "two"} {
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("WatchTask Recv failed: %v", err)
+		}
+		if ev.GetComment().GetBody() != body || ev.GetRevision() <= last {
+			t.Fatalf("expected replayed comment %q after revision %d, got %v", body, last, ev)
+		}
+		last = ev.GetRevision()
+	}
+	if _, err := client.SetTaskResult(ctxWithAuth("devtoken"), &taskv1.SetTaskResultRequest{TaskId: task_id}); err != nil {
+		t.Fatalf("SetTaskResult failed: %v", err)
+	}
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WatchTask Recv failed: %v", err)
+	}
+	if ev.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED || ev.GetRevision() != last+1 {
+		t.Fatalf("expected live COMPLETED event at revision %d, got %v", last+1, ev)
+	}
+}
+
+func TestTaskService_WatchTask_CompactedRevision(t *testing.T) {
+	svc := NewTaskServiceServer()
+	svc.bus = eventbus.NewWithHistory(2)
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "busy"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	for i := 0; i < 3; i++ {
+		if _, err := client.AddComment(ctxWithAuth("devtoken"), &taskv1.AddCommentRequest{TaskId: task_id, Body: "spam"}); err != nil {
+			t.Fatalf("AddComment failed: %v", err)
+		}
+	}
+
+	for _, since := range []int64{1, 100} {
+		stream, err := client.WatchTask(ctxWithAuth("devtoken"), &taskv1.WatchTaskRequest{TaskId: task_id, SinceRevision: since})
+		if err != nil {
+			t.Fatalf("WatchTask failed: %v", err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.OutOfRange {
+			t.Fatalf("since_revision %d: expected OutOfRange, got %v", since, err)
+		}
+	}
+}
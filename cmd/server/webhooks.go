@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/journal"
+	"grpc-lab/internal/webhook"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxDeliveryLog bounds the retained webhook deliveries.
+	maxDeliveryLog         = 1000
+	webhookTimeout         = 10 * time.Second
+	defaultWebhookAttempts = 5
+	defaultWebhookBackoff  = 500 * time.Millisecond
+	maxWebhookBackoff      = 30 * time.Second
+)
+
+func (s *TaskServiceServer) CreateWebhook(ctx context.Context, req *taskv1.CreateWebhookRequest) (*taskv1.Webhook, error) {
+	raw := strings.TrimSpace(req.GetUrl())
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "url must be an absolute http or https URL")
+	}
+	if err := validateLabels(req.GetFilter().GetLabels()); err != nil {
+		return nil, err
+	}
+	secret := req.GetSecret()
+	if secret == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		secret = hex.EncodeToString(b)
+	}
+	hook := &taskv1.Webhook{
+		WebhookId:  uuid.New().String(),
+		Url:        raw,
+		EventTypes: req.GetEventTypes(),
+		Filter:     req.GetFilter(),
+		Secret:     secret,
+		CreatedAt:  timestamppb.New(time.Now()),
+		CreatedBy:  principalFromContext(ctx),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.appendLocked(recordWebhook, hook); err != nil {
+		return nil, err
+	}
+	s.webhooks = append(s.webhooks, hook)
+	return hook, nil
+}
+
+func (s *TaskServiceServer) ListWebhooks(ctx context.Context, req *taskv1.ListWebhooksRequest) (*taskv1.ListWebhooksResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := &taskv1.ListWebhooksResponse{Webhooks: []*taskv1.Webhook{}}
+	for _, hook := range s.webhooks {
+		redacted := proto.Clone(hook).(*taskv1.Webhook)
+		redacted.Secret = ""
+		res.Webhooks = append(res.Webhooks, redacted)
+	}
+	return res, nil
+}
+
+func (s *TaskServiceServer) DeleteWebhook(ctx context.Context, req *taskv1.DeleteWebhookRequest) (*taskv1.DeleteWebhookResponse, error) {
+	webhook_id := strings.TrimSpace(req.GetWebhookId())
+	if webhook_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "webhook_id is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.webhooks, func(h *taskv1.Webhook) bool { return h.GetWebhookId() == webhook_id })
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "webhook not found with id "+webhook_id)
+	}
+	if s.journal != nil {
+		if err := s.journal.Append(journal.Record{Kind: recordWebhookDeleted, Data: []byte(webhook_id)}); err != nil {
+			return nil, status.Error(codes.Internal, "store write failed: "+err.Error())
+		}
+	}
+	s.webhooks = slices.Delete(s.webhooks, i, i+1)
+	return &taskv1.DeleteWebhookResponse{}, nil
+}
+
+func (s *TaskServiceServer) ListWebhookDeliveries(ctx context.Context, req *taskv1.ListWebhookDeliveriesRequest) (*taskv1.ListWebhookDeliveriesResponse, error) {
+	page_size := req.GetPageSize()
+	if page_size <= 0 {
+		page_size = 10
+	} else if page_size > 100 {
+		page_size = 100
+	}
+	offset := 0
+	if page_token := req.GetPageToken(); page_token != "" {
+		var err error
+		offset, err = strconv.Atoi(page_token)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	webhook_id := strings.TrimSpace(req.GetWebhookId())
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []*taskv1.WebhookDelivery
+	for _, d := range s.deliveries {
+		if webhook_id == "" || d.GetWebhookId() == webhook_id {
+			matched = append(matched, d)
+		}
+	}
+	res := &taskv1.ListWebhookDeliveriesResponse{Deliveries: []*taskv1.WebhookDelivery{}}
+	if offset >= len(matched) {
+		return res, nil
+	}
+	end := min(offset+int(page_size), len(matched))
+	res.Deliveries = append(res.Deliveries, matched[offset:end]...)
+	if end < len(matched) {
+		res.NextPageToken = strconv.Itoa(end)
+	}
+	return res, nil
+}
+
+// ReplayWebhookDelivery sends the event of an earlier delivery again as a
+// new delivery, whatever the outcome of the original.
+func (s *TaskServiceServer) ReplayWebhookDelivery(ctx context.Context, req *taskv1.ReplayWebhookDeliveryRequest) (*taskv1.WebhookDelivery, error) {
+	delivery_id := strings.TrimSpace(req.GetDeliveryId())
+	if delivery_id == "" {
+		return nil, status.Error(codes.InvalidArgument, "delivery_id is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.deliveries, func(d *taskv1.WebhookDelivery) bool { return d.GetDeliveryId() == delivery_id })
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "delivery not found with id "+delivery_id)
+	}
+	original := s.deliveries[i]
+	hook := s.webhookLocked(original.GetWebhookId())
+	if hook == nil {
+		return nil, status.Error(codes.FailedPrecondition, "webhook "+original.GetWebhookId()+" no longer exists")
+	}
+	return s.enqueueDeliveryLocked(hook, original.GetEvent(), delivery_id), nil
+}
+
+func (s *TaskServiceServer) webhookLocked(webhook_id string) *taskv1.Webhook {
+	for _, hook := range s.webhooks {
+		if hook.GetWebhookId() == webhook_id {
+			return hook
+		}
+	}
+	return nil
+}
+
+// notifyWebhooksLocked starts a delivery to every webhook interested in ev
+// and returns how many it started. s.mu must be held.
+func (s *TaskServiceServer) notifyWebhooksLocked(ev *taskv1.TaskEvent) int {
+	n := 0
+	for _, hook := range s.webhooks {
+		if len(hook.GetEventTypes()) > 0 && !slices.Contains(hook.GetEventTypes(), ev.GetType()) {
+			continue
+		}
+		if hook.GetFilter() != nil && !eventMatchesFilter(hook.GetFilter(), ev) {
+			continue
+		}
+		s.enqueueDeliveryLocked(hook, ev, "")
+		n++
+	}
+	return n
+}
+
+// enqueueDeliveryLocked records a pending delivery and sends it in the
+// background. The returned record must not be modified once s.mu is
+// released. s.mu must be held.
+func (s *TaskServiceServer) enqueueDeliveryLocked(hook *taskv1.Webhook, ev *taskv1.TaskEvent, replay_of string) *taskv1.WebhookDelivery {
+	now := timestamppb.New(time.Now())
+	d := &taskv1.WebhookDelivery{
+		DeliveryId: uuid.New().String(),
+		WebhookId:  hook.GetWebhookId(),
+		Event:      ev,
+		State:      taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_PENDING,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ReplayOf:   replay_of,
+	}
+	s.deliveries = append(s.deliveries, d)
+	if drop := len(s.deliveries) - maxDeliveryLog; drop > 0 {
+		// Pending deliveries are still being sent, so only finished ones go.
+		s.deliveries = slices.DeleteFunc(s.deliveries, func(d *taskv1.WebhookDelivery) bool {
+			if drop > 0 && d.GetState() != taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_PENDING {
+				drop--
+				return true
+			}
+			return false
+		})
+	}
+	// Replays are not part of the outbox bookkeeping.
+	settle := ev.GetEventId()
+	if replay_of != "" {
+		settle = ""
+	}
+	go s.deliver(d.GetDeliveryId(), settle)
+	return d
+}
+
+// deliver POSTs a delivery until the receiver answers 2xx or the attempts
+// run out, recording every attempt in the delivery log. The outbox event
+// settle, if any, is settled once the delivery finishes.
+func (s *TaskServiceServer) deliver(delivery_id, settle string) {
+	for attempt := 1; ; attempt++ {
+		s.mu.RLock()
+		d := s.deliveryLocked(delivery_id)
+		hook := s.webhookLocked(d.GetWebhookId())
+		client, attempts, backoff := s.webhookClient, s.webhookMaxAttempts, s.webhookBackoff
+		s.mu.RUnlock()
+		if hook == nil {
+			s.updateDelivery(delivery_id, settle, func(d *taskv1.WebhookDelivery) {
+				d.State = taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_FAILED
+				d.LastError = "webhook deleted"
+			})
+			return
+		}
+
+		body, err := protojson.Marshal(&taskv1.WebhookPayload{DeliveryId: delivery_id, WebhookId: hook.GetWebhookId(), Event: d.GetEvent()})
+		if err != nil {
+			s.updateDelivery(delivery_id, settle, func(d *taskv1.WebhookDelivery) {
+				d.State = taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_FAILED
+				d.LastError = err.Error()
+			})
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+		code, err := webhook.Send(ctx, client, webhook.Request{
+			URL:        hook.GetUrl(),
+			Secret:     hook.GetSecret(),
+			WebhookID:  hook.GetWebhookId(),
+			DeliveryID: delivery_id,
+			Body:       body,
+		})
+		cancel()
+
+		done := err == nil || attempt >= attempts
+		s.updateDelivery(delivery_id, settle, func(d *taskv1.WebhookDelivery) {
+			d.Attempts = int32(attempt)
+			d.LastStatusCode = int32(code)
+			d.LastError = ""
+			switch {
+			case err == nil:
+				d.State = taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_SUCCEEDED
+			case done:
+				d.State = taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_FAILED
+				d.LastError = err.Error()
+			default:
+				d.LastError = err.Error()
+			}
+		})
+		if done {
+			return
+		}
+		time.Sleep(webhook.Backoff(attempt, backoff, maxWebhookBackoff))
+	}
+}
+
+func (s *TaskServiceServer) deliveryLocked(delivery_id string) *taskv1.WebhookDelivery {
+	for _, d := range s.deliveries {
+		if d.GetDeliveryId() == delivery_id {
+			return d
+		}
+	}
+	return nil
+}
+
+// updateDelivery applies fn to a copy of the delivery and swaps it in, so
+// records already returned to callers are never modified. A delivery left
+// in a final state settles its outbox event in the same step.
+func (s *TaskServiceServer) updateDelivery(delivery_id, settle string, fn func(*taskv1.WebhookDelivery)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.deliveries {
+		if d.GetDeliveryId() != delivery_id {
+			continue
+		}
+		updated := proto.Clone(d).(*taskv1.WebhookDelivery)
+		fn(updated)
+		updated.UpdatedAt = timestamppb.New(time.Now())
+		s.deliveries[i] = updated
+		if updated.GetState() != taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_PENDING {
+			s.settleLocked(settle)
+		}
+		return
+	}
+}
+
+func newWebhookClient() *http.Client {
+	return &http.Client{Timeout: webhookTimeout}
+}
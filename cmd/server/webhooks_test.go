@@ -0,0 +1,175 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/webhook"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// waitForDelivery polls the delivery log until a delivery for webhook_id
+// reaches a final state.
+func waitForDelivery(t *testing.T, client taskv1.TaskServiceClient, webhook_id string) *taskv1.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		res, err := client.ListWebhookDeliveries(ctxWithAuth("devtoken"), &taskv1.ListWebhookDeliveriesRequest{WebhookId: webhook_id})
+		if err != nil {
+			t.Fatalf("ListWebhookDeliveries failed: %v", err)
+		}
+		for _, d := range res.GetDeliveries() {
+			if d.GetState() != taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_PENDING {
+				return d
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("no finished delivery for webhook %s", webhook_id)
+	return nil
+}
+
+func TestTaskService_Webhooks_SignedDeliveryWithRetry(t *testing.T) {
+	svc := NewTaskServiceServer()
+	svc.webhookBackoff = time.Millisecond
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	var calls atomic.Int32
+	payloads := make(chan *taskv1.WebhookPayload, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("s3cret", body, r.Header.Get(webhook.SignatureHeader)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		if calls.Add(1) == 1 {
+			http.Error(w, "try again", http.StatusInternalServerError)
+			return
+		}
+		var p taskv1.WebhookPayload
+		if err := protojson.Unmarshal(body, &p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		payloads <- &p
+	}))
+	defer receiver.Close()
+
+	hook, err := client.CreateWebhook(ctxWithAuth("devtoken"), &taskv1.CreateWebhookRequest{
+		Url:        receiver.URL,
+		EventTypes: []taskv1.TaskEventType{taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED},
+		Secret:     "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if hook.GetCreatedBy() != devUser {
+		t.Fatalf("expected created_by %q, got %q", devUser, hook.GetCreatedBy())
+	}
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "notify me"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	d := waitForDelivery(t, client, hook.GetWebhookId())
+	if d.GetState() != taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_SUCCEEDED {
+		t.Fatalf("expected SUCCEEDED, got %v (%s)", d.GetState(), d.GetLastError())
+	}
+	if d.GetAttempts() != 2 || d.GetLastStatusCode() != http.StatusOK {
+		t.Fatalf("expected 2 attempts ending in 200, got %d ending in %d", d.GetAttempts(), d.GetLastStatusCode())
+	}
+	p := <-payloads
+	if p.GetDeliveryId() != d.GetDeliveryId() || p.GetEvent().GetTaskId() != created.GetTask().GetTaskId() {
+		t.Fatalf("unexpected payload: %v", p)
+	}
+
+	replay, err := client.ReplayWebhookDelivery(ctxWithAuth("devtoken"), &taskv1.ReplayWebhookDeliveryRequest{DeliveryId: d.GetDeliveryId()})
+	if err != nil {
+		t.Fatalf("ReplayWebhookDelivery failed: %v", err)
+	}
+	if replay.GetReplayOf() != d.GetDeliveryId() {
+		t.Fatalf("expected replay_of %q, got %q", d.GetDeliveryId(), replay.GetReplayOf())
+	}
+	select {
+	case p := <-payloads:
+		if p.GetDeliveryId() != replay.GetDeliveryId() || p.GetEvent().GetTaskId() != created.GetTask().GetTaskId() {
+			t.Fatalf("unexpected replay payload: %v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("replayed delivery never arrived")
+	}
+}
+
+func TestTaskService_Webhooks_FailAfterMaxAttempts(t *testing.T) {
+	svc := NewTaskServiceServer()
+	svc.webhookBackoff = time.Millisecond
+	svc.webhookMaxAttempts = 3
+	client, cleanup := newBufconnClient(t, withServer(svc))
+	defer cleanup()
+
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	hook, err := client.CreateWebhook(ctxWithAuth("devtoken"), &taskv1.CreateWebhookRequest{Url: receiver.URL})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if hook.GetSecret() == "" {
+		t.Fatalf("expected a generated secret")
+	}
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "doomed"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	d := waitForDelivery(t, client, hook.GetWebhookId())
+	if d.GetState() != taskv1.WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_FAILED {
+		t.Fatalf("expected FAILED, got %v", d.GetState())
+	}
+	if d.GetAttempts() != 3 || calls.Load() != 3 || d.GetLastStatusCode() != http.StatusServiceUnavailable {
+		t.Fatalf("expected 3 attempts ending in 503, got %d (%d calls) ending in %d", d.GetAttempts(), calls.Load(), d.GetLastStatusCode())
+	}
+}
+
+func TestTaskService_Webhooks_Manage(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	_, err := client.CreateWebhook(ctxWithAuth("devtoken"), &taskv1.CreateWebhookRequest{Url: "ftp://example.com/hook"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for non-http url, got %v", status.Code(err))
+	}
+
+	hook, err := client.CreateWebhook(ctxWithAuth("devtoken"), &taskv1.CreateWebhookRequest{Url: "http://127.0.0.1:1/hook", Secret: "shh"})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	list, err := client.ListWebhooks(ctxWithAuth("devtoken"), &taskv1.ListWebhooksRequest{})
+	if err != nil {
+		t.Fatalf("ListWebhooks failed: %v", err)
+	}
+	if len(list.GetWebhooks()) != 1 || list.GetWebhooks()[0].GetSecret() != "" {
+		t.Fatalf("expected one webhook with a redacted secret, got %v", list.GetWebhooks())
+	}
+
+	if _, err := client.DeleteWebhook(ctxWithAuth("devtoken"), &taskv1.DeleteWebhookRequest{WebhookId: hook.GetWebhookId()}); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	_, err = client.DeleteWebhook(ctxWithAuth("devtoken"), &taskv1.DeleteWebhookRequest{WebhookId: hook.GetWebhookId()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound on second delete, got %v", status.Code(err))
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/pkg/worker"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// runWorker runs w against client until the test ends.
+func runWorker(t *testing.T, w *worker.Worker) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	})
+}
+
+// waitForStatus polls until the task reaches want.
+func waitForStatus(t *testing.T, client taskv1.TaskServiceClient, task_id string, want taskv1.TaskStatus) *taskv1.Task {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+		if err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+		if got.GetStatus() == want {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task %s still %v, want %v", task_id, got.GetStatus(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func createTyped(t *testing.T, client taskv1.TaskServiceClient, taskType string) string {
+	t.Helper()
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: taskType, Labels: map[string]string{"type": taskType}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	return created.GetTask().GetTaskId()
+}
+
+func TestWorkerSDK_RunsHandlers(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	// Registered before runWorker, so the worker stops first.
+	t.Cleanup(cleanup)
+
+	w := worker.New(client, worker.Options{WorkerID: "sdk", Concurrency: 2})
+	w.Handle("echo", func(ctx context.Context, task *worker.Task) (*structpb.Struct, error) {
+		if err := task.Log(ctx, "echoing "+task.GetTitle()); err != nil {
+			return nil, err
+		}
+		if err := task.Progress(100, "echo", "done"); err != nil {
+			return nil, err
+		}
+		return structpb.NewStruct(map[string]any{"title": task.GetTitle()})
+	})
+	w.Handle("flaky", func(ctx context.Context, task *worker.Task) (*structpb.Struct, error) {
+		return nil, worker.WithClass("transient", errors.New("try again"))
+	})
+	w.Handle("broken", func(ctx context.Context, task *worker.Task) (*structpb.Struct, error) {
+		panic("boom")
+	})
+	runWorker(t, w)
+
+	echo := waitForStatus(t, client, createTyped(t, client, "echo"), taskv1.TaskStatus_TASK_STATUS_COMPLETED)
+	if echo.GetResult().GetFields()["title"].GetStringValue() != "echo" || echo.GetLease().GetWorkerId() != "sdk" {
+		t.Fatalf("expected the handler's result, got %v", echo)
+	}
+	if got := tailLogs(t, client, &taskv1.TailTaskLogsRequest{TaskId: echo.GetTaskId()}); strings.Join(got, ",") != "echoing echo" {
+		t.Fatalf("expected the handler's log line, got %q", got)
+	}
+
+	flaky := waitForStatus(t, client, createTyped(t, client, "flaky"), taskv1.TaskStatus_TASK_STATUS_FAILED)
+	if flaky.GetError() != "try again" || flaky.GetErrorClass() != "transient" {
+		t.Fatalf("expected the handler's error and class, got %v", flaky)
+	}
+	broken := waitForStatus(t, client, createTyped(t, client, "broken"), taskv1.TaskStatus_TASK_STATUS_FAILED)
+	if broken.GetErrorClass() != worker.ErrorClassPanic || !strings.Contains(broken.GetError(), "boom") {
+		t.Fatalf("expected a recovered panic, got %v", broken)
+	}
+
+	// Tasks no handler is registered for are not pushed to the worker.
+	other := createTyped(t, client, "other")
+	time.Sleep(100 * time.Millisecond)
+	if got := waitForStatus(t, client, other, taskv1.TaskStatus_TASK_STATUS_PENDING); got.GetAttempt() != 0 {
+		t.Fatalf("expected the unhandled task to stay unclaimed, got %v", got)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/eventbus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxWorkerCapacity = 1000
+
+// WorkerSession pushes tasks to a connected worker as they become PENDING,
+// up to its capacity, and takes heartbeats, progress and results back. Any
+// task still leased to the session when it ends has its attempt failed as
+// if the lease expired, so its retry policy decides whether it is retried,
+// failed or dead-lettered.
+func (s *TaskServiceServer) WorkerSession(stream taskv1.TaskService_WorkerSessionServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hello := first.GetHello()
+	if hello == nil {
+		return status.Error(codes.InvalidArgument, "the first message must be hello")
+	}
+	worker_id := strings.TrimSpace(hello.GetWorkerId())
+	if worker_id == "" {
+		return status.Error(codes.InvalidArgument, "worker_id is required")
+	}
+	capacity := int(hello.GetCapacity())
+	if capacity < 1 || capacity > maxWorkerCapacity {
+		return status.Errorf(codes.InvalidArgument, "capacity must be between 1 and %d", maxWorkerCapacity)
+	}
+	ttl, err := leaseDuration(hello.GetLeaseDuration())
+	if err != nil {
+		return err
+	}
+	match := func(task *taskv1.Task) bool {
+		if len(hello.GetQueues()) > 0 && !slices.Contains(hello.GetQueues(), taskQueue(task)) {
+			return false
+		}
+		return len(hello.GetCapabilities()) == 0 || slices.Contains(hello.GetCapabilities(), task.GetLabels()["type"])
+	}
+
+	// Subscribing before the first claim means no task that becomes PENDING
+	// later can be missed. Tasks leaving RUNNING wake the session too, since
+	// they free a slot in their queue or may be one the session holds.
+	sub := s.bus.Subscribe(eventbus.Options{
+		Name:   "WorkerSession " + worker_id + " by " + principalFromContext(ctx),
+		Buffer: 1,
+		Policy: eventbus.DropOldest,
+		Filter: func(ev *taskv1.TaskEvent) bool {
+			return ev.GetTask() != nil && ev.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING
+		},
+	})
+	defer sub.Close()
+
+	held := make(map[string]string) // task_id -> lease_id
+	defer s.releaseLeases(ctx, worker_id, held)
+
+	msgs := make(chan *taskv1.WorkerMessage)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		s.mu.RLock()
+		queues_changed := s.queuesChanged
+		s.mu.RUnlock()
+		for len(held) < capacity {
+			s.mu.Lock()
+			task, err := s.claimLocked(ctx, worker_id, ttl, match)
+			s.mu.Unlock()
+			if err != nil {
+				return err
+			}
+			if task == nil {
+				break
+			}
+			held[task.GetTaskId()] = task.GetLease().GetLeaseId()
+			if err := stream.Send(&taskv1.DispatchMessage{Msg: &taskv1.DispatchMessage_Assignment{Assignment: &taskv1.TaskAssignment{Task: task}}}); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-sub.Ready():
+			sub.Drain()
+			for task_id, reason := range s.lostLeases(held) {
+				if err := s.revoke(stream, held, task_id, status.Error(codes.FailedPrecondition, reason)); err != nil {
+					return err
+				}
+			}
+		case <-queues_changed:
+		case msg := <-msgs:
+			if err := s.handleWorkerMessage(ctx, stream, worker_id, ttl, held, msg); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *TaskServiceServer) handleWorkerMessage(ctx context.Context, stream taskv1.TaskService_WorkerSessionServer, worker_id string, ttl time.Duration, held map[string]string, msg *taskv1.WorkerMessage) error {
+	var task_id, lease_id string
+	var err error
+	switch m := msg.GetMsg().(type) {
+	case *taskv1.WorkerMessage_Heartbeat:
+		for task_id, lease_id := range held {
+			if err := s.renewLease(task_id, worker_id, lease_id, ttl); err != nil {
+				if err := s.revoke(stream, held, task_id, err); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case *taskv1.WorkerMessage_Progress:
+		task_id, lease_id = m.Progress.GetTaskId(), m.Progress.GetLeaseId()
+		// A bad report is one handler's bug, so only its task is revoked.
+		var p *taskv1.TaskProgress
+		if p, err = newProgress(m.Progress.GetPercent(), m.Progress.GetStep(), m.Progress.GetMessage()); err == nil {
+			err = s.sessionProgress(ctx, task_id, worker_id, lease_id, ttl, p)
+		}
+	case *taskv1.WorkerMessage_Result:
+		task_id, lease_id = m.Result.GetTaskId(), m.Result.GetLeaseId()
+		if m.Result.GetOutcome() == nil {
+			return status.Error(codes.InvalidArgument, "result needs a result or an error")
+		}
+		if _, ok := m.Result.GetOutcome().(*taskv1.WorkerResult_Error); ok {
+			// Like a bad progress report, a reserved class is one
+			// handler's bug, so only its task is revoked.
+			if err = checkErrorClass(strings.TrimSpace(m.Result.GetErrorClass())); err != nil {
+				break
+			}
+			_, err = s.FailTask(ctx, &taskv1.FailTaskRequest{TaskId: task_id, WorkerId: worker_id, LeaseId: lease_id, Error: m.Result.GetError(), ErrorClass: m.Result.GetErrorClass()})
+		} else {
+			_, err = s.CompleteTask(ctx, &taskv1.CompleteTaskRequest{TaskId: task_id, WorkerId: worker_id, LeaseId: lease_id, Result: m.Result.GetResult()})
+		}
+		if err == nil {
+			delete(held, task_id)
+			return nil
+		}
+		// A result that is too large or malformed is the worker's bug, not a
+		// lost lease; end the session so the task is released.
+		if status.Code(err) == codes.InvalidArgument {
+			return err
+		}
+	case *taskv1.WorkerMessage_Hello:
+		return status.Error(codes.InvalidArgument, "hello may only be sent once")
+	default:
+		return status.Error(codes.InvalidArgument, "empty worker message")
+	}
+	if err != nil {
+		return s.revoke(stream, held, task_id, err)
+	}
+	return nil
+}
+
+// sessionProgress records progress sent over a session. Progress proves the
+// worker is alive, so it renews the lease too.
+func (s *TaskServiceServer) sessionProgress(ctx context.Context, task_id, worker_id, lease_id string, ttl time.Duration, p *taskv1.TaskProgress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.renewLeaseLocked(task_id, worker_id, lease_id, ttl); err != nil {
+		return err
+	}
+	_, err := s.reportProgressLocked(ctx, s.taskMap[task_id], p)
+	return err
+}
+
+func (s *TaskServiceServer) renewLease(task_id, worker_id, lease_id string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.renewLeaseLocked(task_id, worker_id, lease_id, ttl)
+	return err
+}
+
+// lostLeases returns why each held task whose lease is gone, e.g. because
+// it was canceled or reaped, was lost.
+func (s *TaskServiceServer) lostLeases(held map[string]string) map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lost := make(map[string]string)
+	for task_id, lease_id := range held {
+		task, ok := s.taskMap[task_id]
+		switch {
+		case !ok:
+			lost[task_id] = "task " + task_id + " no longer exists"
+		case task.GetStatus() == taskv1.TaskStatus_TASK_STATUS_CANCELED:
+			lost[task_id] = "task " + task_id + " was canceled"
+		case task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING || task.GetLease().GetLeaseId() != lease_id:
+			lost[task_id] = "lease " + lease_id + " on task " + task_id + " is no longer held"
+		}
+	}
+	return lost
+}
+
+// revoke tells the worker it lost task_id and forgets it.
+func (s *TaskServiceServer) revoke(stream taskv1.TaskService_WorkerSessionServer, held map[string]string, task_id string, reason error) error {
+	delete(held, task_id)
+	return stream.Send(&taskv1.DispatchMessage{Msg: &taskv1.DispatchMessage_Revoked{Revoked: &taskv1.TaskRevoked{
+		TaskId: task_id,
+		Reason: status.Convert(reason).Message(),
+	}}})
+}
+
+// releaseLeases ends the attempts still leased to a finished session as if
+// their leases had expired, so they are retried or failed without waiting
+// for the reaper.
+func (s *TaskServiceServer) releaseLeases(ctx context.Context, worker_id string, held map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for task_id, lease_id := range held {
+		task, ok := s.taskMap[task_id]
+		if !ok || task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING || task.GetLease().GetLeaseId() != lease_id {
+			continue
+		}
+		if _, err := s.failAttemptLocked(ctx, task, "session of worker "+worker_id+" ended", errorClassLeaseExpired); err != nil {
+			log.Printf("WorkerSession: releasing task %s: %v", task_id, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func helloMsg(worker_id string, capacity int32, capabilities ...string) *taskv1.WorkerMessage {
+	return &taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Hello{Hello: &taskv1.WorkerHello{WorkerId: worker_id, Capacity: capacity, Capabilities: capabilities}}}
+}
+
+func resultMsg(task *taskv1.Task, errText string) *taskv1.WorkerMessage {
+	res := &taskv1.WorkerResult{TaskId: task.GetTaskId(), LeaseId: task.GetLease().GetLeaseId()}
+	if errText != "" {
+		res.Outcome = &taskv1.WorkerResult_Error{Error: errText}
+	} else {
+		res.Outcome = &taskv1.WorkerResult_Result{Result: nil}
+	}
+	return &taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Result{Result: res}}
+}
+
+func recvAssignment(t *testing.T, stream taskv1.TaskService_WorkerSessionClient) *taskv1.Task {
+	t.Helper()
+	msg, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("WorkerSession Recv failed: %v", err)
+	}
+	if msg.GetAssignment() == nil {
+		t.Fatalf("expected an assignment, got %v", msg)
+	}
+	return msg.GetAssignment().GetTask()
+}
+
+func TestTaskService_WorkerSession_PushesUpToCapacity(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	create := func(title string) string {
+		created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: title})
+		if err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		return created.GetTask().GetTaskId()
+	}
+	first, second := create("first"), create("second")
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(helloMsg("w1", 1)); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+
+	task := recvAssignment(t, stream)
+	if task.GetTaskId() != first || task.GetStatus() != taskv1.TaskStatus_TASK_STATUS_RUNNING || task.GetLease().GetWorkerId() != "w1" {
+		t.Fatalf("expected the first task leased to w1, got %v", task)
+	}
+	if err := stream.Send(resultMsg(task, "")); err != nil {
+		t.Fatalf("Send result failed: %v", err)
+	}
+	// Capacity frees up, so the second task follows without asking.
+	task = recvAssignment(t, stream)
+	if task.GetTaskId() != second {
+		t.Fatalf("expected the second task, got %v", task)
+	}
+	if err := stream.Send(resultMsg(task, "boom")); err != nil {
+		t.Fatalf("Send result failed: %v", err)
+	}
+	// An idle session is pushed new tasks as soon as they are created.
+	third := create("third")
+	if task := recvAssignment(t, stream); task.GetTaskId() != third {
+		t.Fatalf("expected the new task to be pushed, got %v", task)
+	}
+
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: first})
+	if err != nil || got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected first COMPLETED, got %v, %v", got, err)
+	}
+	got, err = client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: second})
+	if err != nil || got.GetStatus() != taskv1.TaskStatus_TASK_STATUS_FAILED || got.GetError() != "boom" {
+		t.Fatalf("expected second FAILED with its error, got %v, %v", got, err)
+	}
+}
+
+func TestTaskService_WorkerSession_Capabilities(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "resize", Labels: map[string]string{"type": "image"}}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	transcode, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "transcode", Labels: map[string]string{"type": "video"}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(helloMsg("w1", 5, "video")); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+	if task := recvAssignment(t, stream); task.GetTaskId() != transcode.GetTask().GetTaskId() {
+		t.Fatalf("expected only the video task, got %v", task)
+	}
+}
+
+func TestTaskService_WorkerSession_DisconnectReleasesLeases(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "abandoned"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(helloMsg("w1", 1)); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+	recvAssignment(t, stream)
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: created.GetTask().GetTaskId()})
+		if err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+		if got.GetStatus() == taskv1.TaskStatus_TASK_STATUS_PENDING {
+			if got.GetLease() != nil || got.GetErrorClass() != errorClassLeaseExpired || got.GetAttempt() != 1 {
+				t.Fatalf("expected the attempt failed and the lease cleared, got %v", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task still %v after the worker disconnected", got.GetStatus())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTaskService_WorkerSession_DisconnectFollowsRetryPolicy(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	created, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: "crashy", RetryPolicy: &taskv1.RetryPolicy{MaxAttempts: 2, InitialBackoff: durationpb.New(0)}})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task_id := created.GetTask().GetTaskId()
+	for attempt := 1; attempt <= 2; attempt++ {
+		ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+		stream, err := client.WorkerSession(ctx)
+		if err != nil {
+			t.Fatalf("WorkerSession failed: %v", err)
+		}
+		if err := stream.Send(helloMsg("w1", 1)); err != nil {
+			t.Fatalf("Send hello failed: %v", err)
+		}
+		if got := recvAssignment(t, stream); got.GetAttempt() != int32(attempt) {
+			t.Fatalf("expected attempt %d, got %v", attempt, got)
+		}
+		cancel()
+		want := taskv1.TaskStatus_TASK_STATUS_PENDING
+		if attempt == 2 {
+			want = taskv1.TaskStatus_TASK_STATUS_FAILED
+		}
+		waitForStatus(t, client, task_id, want)
+	}
+	got, err := client.GetTask(ctxWithAuth("devtoken"), &taskv1.GetTaskRequest{TaskId: task_id})
+	if err != nil || got.GetDeadLetteredAt() == nil {
+		t.Fatalf("expected the task dead-lettered once its attempts ran out, got %v, %v", got, err)
+	}
+}
+
+func TestTaskService_WorkerSession_BadProgressRevokesOnlyItsTask(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	for _, title := range []string{"first", "second"} {
+		if _, err := client.CreateTask(ctxWithAuth("devtoken"), &taskv1.CreateTaskRequest{Title: title}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+	ctx, cancel := context.WithCancel(ctxWithAuth("devtoken"))
+	defer cancel()
+	stream, err := client.WorkerSession(ctx)
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(helloMsg("w1", 2)); err != nil {
+		t.Fatalf("Send hello failed: %v", err)
+	}
+	first, second := recvAssignment(t, stream), recvAssignment(t, stream)
+
+	progress := &taskv1.WorkerProgress{TaskId: first.GetTaskId(), LeaseId: first.GetLease().GetLeaseId(), Percent: 150}
+	if err := stream.Send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Progress{Progress: progress}}); err != nil {
+		t.Fatalf("Send progress failed: %v", err)
+	}
+	msg, err := stream.Recv()
+	if err != nil || msg.GetRevoked().GetTaskId() != first.GetTaskId() {
+		t.Fatalf("expected the first task revoked, got %v, %v", msg, err)
+	}
+	if err := stream.Send(resultMsg(second, "")); err != nil {
+		t.Fatalf("Send result failed: %v", err)
+	}
+	waitForStatus(t, client, second.GetTaskId(), taskv1.TaskStatus_TASK_STATUS_COMPLETED)
+}
+
+func TestTaskService_WorkerSession_RequiresHello(t *testing.T) {
+	client, cleanup := newBufconnClient(t)
+	defer cleanup()
+
+	stream, err := client.WorkerSession(ctxWithAuth("devtoken"))
+	if err != nil {
+		t.Fatalf("WorkerSession failed: %v", err)
+	}
+	if err := stream.Send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Heartbeat{Heartbeat: &taskv1.WorkerHeartbeat{}}}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without hello, got %v", err)
+	}
+}
@@ -2,18 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	taskv1 "grpc-lab/gen/task/v1"
 	"io"
 	"log"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type tokenCreds string
@@ -27,6 +37,80 @@ func (t tokenCreds) RequireTransportSecurity() bool {
 }
 
 func runCreate(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	var input *structpb.Struct
+	labels := map[string]string{}
+	parent_id := ""
+	queue := ""
+	var run_at *timestamppb.Timestamp
+	var timeout *durationpb.Duration
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--input":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--input requires a JSON object")
+			}
+			var err error
+			input, err = parseJSONObject(args[i+1])
+			if err != nil {
+				return err
+			}
+			i++
+		case "--label":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--label requires key=value")
+			}
+			k, v, ok := strings.Cut(args[i+1], "=")
+			if !ok {
+				return fmt.Errorf("invalid label: %s", args[i+1])
+			}
+			labels[k] = v
+			i++
+		case "--parent":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--parent requires a task id")
+			}
+			parent_id = args[i+1]
+			i++
+		case "--queue":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--queue requires a queue name")
+			}
+			queue = args[i+1]
+			i++
+		case "--in", "--at":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			if args[i] == "--in" {
+				d, err := time.ParseDuration(args[i+1])
+				if err != nil {
+					return fmt.Errorf("invalid --in: %s", args[i+1])
+				}
+				run_at = timestamppb.New(time.Now().Add(d))
+			} else {
+				at, err := time.Parse(time.RFC3339, args[i+1])
+				if err != nil {
+					return fmt.Errorf("invalid --at, want RFC 3339: %s", args[i+1])
+				}
+				run_at = timestamppb.New(at)
+			}
+			i++
+		case "--timeout":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--timeout requires a duration")
+			}
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil {
+				return fmt.Errorf("invalid --timeout: %s", args[i+1])
+			}
+			timeout = durationpb.New(d)
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	args = rest
 	if len(args) < 1 {
 		return fmt.Errorf("task title is required")
 	}
@@ -38,15 +122,26 @@ func runCreate(ctx context.Context, c taskv1.TaskServiceClient, args []string) e
 		description = strings.Join(args[1:], " ")
 	}
 
-	req := &taskv1.CreateTaskRequest{Title: title, Description: description}
+	req := &taskv1.CreateTaskRequest{Title: title, Description: description, Input: input, Labels: labels, ParentId: parent_id, Queue: queue, RunAt: run_at, ExecutionTimeout: timeout}
 	resp, err := c.CreateTask(ctx, req)
 	if err != nil {
 		return err
 	}
 	log.Printf("Created Task with ID: %s, title: %s, description: %s", resp.GetTask().GetTaskId(), resp.GetTask().GetTitle(), resp.GetTask().GetDescription())
+	if resp.GetTask().GetStatus() == taskv1.TaskStatus_TASK_STATUS_SCHEDULED {
+		log.Printf("Scheduled to run at %s", resp.GetTask().GetRunAt().AsTime().Local().Format(time.RFC3339))
+	}
 	return nil
 }
 
+func parseJSONObject(raw string) (*structpb.Struct, error) {
+	st := &structpb.Struct{}
+	if err := protojson.Unmarshal([]byte(raw), st); err != nil {
+		return nil, fmt.Errorf("invalid JSON object: %w", err)
+	}
+	return st, nil
+}
+
 func runGet(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("task id is required")
@@ -56,7 +151,29 @@ func runGet(ctx context.Context, c taskv1.TaskServiceClient, args []string) erro
 	if err != nil {
 		return err
 	}
-	log.Printf("Fetched Task with ID: %s Title: %s Description: %s", task.GetTaskId(), task.GetTitle(), task.GetDescription())
+	log.Printf("Fetched Task with ID: %s Title: %s Description: %s Status: %s", task.GetTaskId(), task.GetTitle(), task.GetDescription(), task.GetStatus().String())
+	if task.GetInput() != nil {
+		log.Printf("Input: %s", protojson.Format(task.GetInput()))
+	}
+	if task.GetResult() != nil {
+		log.Printf("Result: %s", protojson.Format(task.GetResult()))
+	}
+	return nil
+}
+
+func runResult(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("task id and JSON result are required")
+	}
+	result, err := parseJSONObject(args[1])
+	if err != nil {
+		return err
+	}
+	task, err := c.SetTaskResult(ctx, &taskv1.SetTaskResultRequest{TaskId: args[0], Result: result})
+	if err != nil {
+		return err
+	}
+	log.Printf("Task %s is %s with result: %s", task.GetTaskId(), task.GetStatus().String(), protojson.Format(task.GetResult()))
 	return nil
 }
 
@@ -86,11 +203,188 @@ func runList(ctx context.Context, c taskv1.TaskServiceClient, args []string) err
 
 }
 
+func runSearch(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("search query is required")
+	}
+	resp, err := c.SearchTasks(ctx, &taskv1.SearchTasksRequest{Query: strings.Join(args, " ")})
+	if err != nil {
+		return err
+	}
+	for _, r := range resp.GetResults() {
+		log.Printf("Task ID: %s Score: %.2f Title: %s Match: %s", r.GetTask().GetTaskId(), r.GetScore(), r.GetTask().GetTitle(), r.GetSnippet())
+	}
+	log.Printf("Next Page Token %s", resp.GetNextPageToken())
+	return nil
+}
+
+func runStats(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	stats, err := c.GetTaskStats(ctx, &taskv1.GetTaskStatsRequest{})
+	if err != nil {
+		return err
+	}
+	log.Printf("Total tasks: %d", stats.GetTotal())
+	for _, name := range slices.Sorted(maps.Keys(stats.GetByStatus())) {
+		log.Printf("  %-24s %d", name, stats.GetByStatus()[name])
+	}
+	for _, label := range slices.Sorted(maps.Keys(stats.GetByLabel())) {
+		log.Printf("  label %-18s %d", label, stats.GetByLabel()[label])
+	}
+	for _, w := range stats.GetThroughput() {
+		log.Printf("Last %s: created %d (%.2f/min) completed %d (%.2f/min)", w.GetWindow().AsDuration(), w.GetCreated(), w.GetCreatedPerMinute(), w.GetCompleted(), w.GetCompletedPerMinute())
+	}
+	for _, d := range stats.GetTimeInStatus() {
+		log.Printf("Time in %s: median %s p95 %s (%d samples)", d.GetStatus().String(), d.GetMedian().AsDuration(), d.GetP95().AsDuration(), d.GetSamples())
+	}
+	for _, ws := range stats.GetWatchStreams() {
+		log.Printf("Watch stream %q (%s): queued %d dropped %d", ws.GetName(), ws.GetPolicy(), ws.GetQueued(), ws.GetDropped())
+	}
+	log.Printf("Dropped watch events total: %d", stats.GetDroppedEventsTotal())
+	return nil
+}
+
+const (
+	watchHeartbeat = 5 * time.Second
+	// A watch is reported stale after this many heartbeats go missing.
+	missedHeartbeats = 3
+)
+
 func runWatch(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("task id is required")
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: watch <task_id> [since_revision]")
+	}
+	var since int64
+	if len(args) == 2 {
+		var err error
+		since, err = strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid since_revision: %s", args[1])
+		}
 	}
-	stream, err := c.WatchTask(ctx, &taskv1.WatchTaskRequest{TaskId: args[0]})
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.WatchTask(ctx, &taskv1.WatchTaskRequest{TaskId: args[0], SinceRevision: since, HeartbeatInterval: durationpb.New(watchHeartbeat)})
+	if err != nil {
+		return err
+	}
+
+	type recv struct {
+		event *taskv1.TaskEvent
+		err   error
+	}
+	recvCh := make(chan recv)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			select {
+			case recvCh <- recv{event, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	revision := since
+	drawn := false
+	stale := time.NewTimer(missedHeartbeats * watchHeartbeat)
+	defer stale.Stop()
+	for {
+		select {
+		case <-stale.C:
+			return fmt.Errorf("watch is stale: no heartbeat for %s; resume with: watch %s %d", missedHeartbeats*watchHeartbeat, args[0], revision)
+		case r := <-recvCh:
+			if r.err == io.EOF {
+				return nil
+			}
+			if status.Code(r.err) == codes.OutOfRange {
+				return fmt.Errorf("cannot resume from revision %d, run watch without a revision to start over: %w", since, r.err)
+			}
+			if r.err != nil {
+				return r.err
+			}
+			stale.Reset(missedHeartbeats * watchHeartbeat)
+			event := r.event
+			revision = event.GetRevision()
+			if event.GetType() == taskv1.TaskEventType_TASK_EVENT_TYPE_HEARTBEAT {
+				continue
+			}
+			if event.GetType() == taskv1.TaskEventType_TASK_EVENT_TYPE_GAP {
+				log.Printf("Warning: missed events up to rev=%d (%s); re-run get for the current state", event.GetRevision(), event.GetMessage())
+				continue
+			}
+			if event.GetType() == taskv1.TaskEventType_TASK_EVENT_TYPE_PROGRESS {
+				// Redraw the bar in place until something else is printed.
+				fmt.Fprint(os.Stderr, "\r"+progressBar(event.GetTask().GetProgress()))
+				drawn = true
+				continue
+			}
+			if drawn {
+				fmt.Fprintln(os.Stderr)
+				drawn = false
+			}
+			log.Printf("Task Event: rev=%d type=%v status=%v at=%s message=%s", event.GetRevision(), event.GetType().String(), event.GetStatus().String(), event.GetAt().AsTime().String(), event.GetMessage())
+		}
+	}
+}
+
+const progressBarWidth = 30
+
+// progressBar renders p as a single line such as
+// "[=========>          ]  33% download: 12 of 36 files".
+func progressBar(p *taskv1.TaskProgress) string {
+	filled := int(p.GetPercent() / 100 * progressBarWidth)
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	line := fmt.Sprintf("[%s] %3.0f%%", bar, p.GetPercent())
+	if p.GetStep() != "" {
+		line += " " + p.GetStep() + ":"
+	}
+	if p.GetMessage() != "" {
+		line += " " + p.GetMessage()
+	}
+	// Pad to clear what is left of a longer previous line.
+	return fmt.Sprintf("%-100s", line)
+}
+
+func runWatchAll(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	req := &taskv1.WatchTasksRequest{Filter: &taskv1.TaskFilter{Labels: map[string]string{}}}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--snapshot" {
+			req.SendInitialSnapshot = true
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", args[i])
+		}
+		val := args[i+1]
+		i++
+		switch args[i-1] {
+		case "--status":
+			st, ok := taskv1.TaskStatus_value["TASK_STATUS_"+strings.ToUpper(val)]
+			if !ok {
+				return fmt.Errorf("unknown status: %s", val)
+			}
+			req.Filter.Statuses = append(req.Filter.Statuses, taskv1.TaskStatus(st))
+		case "--label":
+			k, v, ok := strings.Cut(val, "=")
+			if !ok {
+				return fmt.Errorf("invalid label: %s", val)
+			}
+			req.Filter.Labels[k] = v
+		case "--parent":
+			req.Filter.ParentId = val
+		case "--queue":
+			req.Filter.Queue = val
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i-1])
+		}
+	}
+	stream, err := c.WatchTasks(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -102,8 +396,76 @@ func runWatch(ctx context.Context, c taskv1.TaskServiceClient, args []string) er
 		if err != nil {
 			return err
 		}
-		log.Printf("Task Event: status=%v at=%s message=%s", event.GetStatus().String(), event.GetAt().AsTime().String(), event.GetMessage())
+		log.Printf("Task Event: task=%s type=%v status=%v previous=%v title=%s", event.GetTaskId(), event.GetType().String(), event.GetStatus().String(), event.GetPreviousStatus().String(), event.GetTask().GetTitle())
+	}
+}
+
+// runEvents lists the event log, e.g. what happened to infra tasks in the
+// last day: events --label team=infra --since 24h
+func runEvents(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	req := &taskv1.ListEventsRequest{Filter: &taskv1.TaskFilter{Labels: map[string]string{}}}
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s requires a value", args[i])
+		}
+		val := args[i+1]
+		i++
+		switch args[i-1] {
+		case "--task":
+			req.TaskId = val
+		case "--status":
+			st, ok := taskv1.TaskStatus_value["TASK_STATUS_"+strings.ToUpper(val)]
+			if !ok {
+				return fmt.Errorf("unknown status: %s", val)
+			}
+			req.Filter.Statuses = append(req.Filter.Statuses, taskv1.TaskStatus(st))
+		case "--label":
+			k, v, ok := strings.Cut(val, "=")
+			if !ok {
+				return fmt.Errorf("invalid label: %s", val)
+			}
+			req.Filter.Labels[k] = v
+		case "--parent":
+			req.Filter.ParentId = val
+		case "--type":
+			typ, ok := taskv1.TaskEventType_value["TASK_EVENT_TYPE_"+strings.ToUpper(val)]
+			if !ok {
+				return fmt.Errorf("unknown event type: %s", val)
+			}
+			req.Types = append(req.Types, taskv1.TaskEventType(typ))
+		case "--actor":
+			req.Actor = val
+		case "--since", "--until":
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %s", args[i-1], val)
+			}
+			if args[i-1] == "--since" {
+				req.StartTime = timestamppb.New(time.Now().Add(-d))
+			} else {
+				req.EndTime = timestamppb.New(time.Now().Add(-d))
+			}
+		case "--page-size":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("invalid page_size: %s", val)
+			}
+			req.PageSize = int32(n)
+		case "--page-token":
+			req.PageToken = val
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i-1])
+		}
+	}
+	resp, err := c.ListEvents(ctx, req)
+	if err != nil {
+		return err
+	}
+	for _, ev := range resp.GetEvents() {
+		log.Printf("Rev: %d At: %s Task: %s Type: %s Actor: %s Status: %s -> %s Message: %s", ev.GetRevision(), ev.GetAt().AsTime().String(), ev.GetTaskId(), ev.GetType(), ev.GetActor(), ev.GetPreviousStatus(), ev.GetStatus(), ev.GetMessage())
 	}
+	log.Printf("Next Page Token %s", resp.GetNextPageToken())
+	return nil
 }
 
 func runBulkCreate(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
@@ -177,12 +539,481 @@ func runTaskConsole(ctx context.Context, c taskv1.TaskServiceClient, args []stri
 	return nil
 }
 
+func runComment(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: comment add|list|edit|delete <task_id> ...")
+	}
+	sub, task_id, rest := args[0], args[1], args[2:]
+	switch sub {
+	case "add":
+		if len(rest) < 1 {
+			return fmt.Errorf("comment body is required")
+		}
+		comment, err := c.AddComment(ctx, &taskv1.AddCommentRequest{TaskId: task_id, Body: strings.Join(rest, " ")})
+		if err != nil {
+			return err
+		}
+		log.Printf("Added Comment ID: %s Author: %s Body: %s", comment.GetCommentId(), comment.GetAuthor(), comment.GetBody())
+	case "list":
+		page_size := 10
+		page_token := ""
+		var err error
+		if len(rest) >= 1 {
+			page_size, err = strconv.Atoi(rest[0])
+			if err != nil {
+				return fmt.Errorf("invalid page_size: %s", rest[0])
+			}
+		}
+		if len(rest) == 2 {
+			page_token = rest[1]
+		}
+		resp, err := c.ListComments(ctx, &taskv1.ListCommentsRequest{TaskId: task_id, PageSize: int32(page_size), PageToken: page_token})
+		if err != nil {
+			return err
+		}
+		for _, comment := range resp.GetComments() {
+			log.Printf("Comment ID: %s Author: %s At: %s Body: %s", comment.GetCommentId(), comment.GetAuthor(), comment.GetCreatedAt().AsTime().String(), comment.GetBody())
+		}
+		log.Printf("Next Page Token %s", resp.GetNextPageToken())
+	case "edit":
+		if len(rest) < 2 {
+			return fmt.Errorf("comment id and body are required")
+		}
+		comment, err := c.EditComment(ctx, &taskv1.EditCommentRequest{TaskId: task_id, CommentId: rest[0], Body: strings.Join(rest[1:], " ")})
+		if err != nil {
+			return err
+		}
+		log.Printf("Edited Comment ID: %s Body: %s", comment.GetCommentId(), comment.GetBody())
+	case "delete":
+		if len(rest) != 1 {
+			return fmt.Errorf("comment id is required")
+		}
+		if _, err := c.DeleteComment(ctx, &taskv1.DeleteCommentRequest{TaskId: task_id, CommentId: rest[0]}); err != nil {
+			return err
+		}
+		log.Printf("Deleted Comment ID: %s", rest[0])
+	default:
+		return fmt.Errorf("unknown comment command: %s", sub)
+	}
+	return nil
+}
+
+func runWebhook(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: webhook add|list|delete|deliveries|replay ...")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "add":
+		req := &taskv1.CreateWebhookRequest{}
+		for i := 0; i < len(rest); i++ {
+			if !strings.HasPrefix(rest[i], "--") {
+				req.Url = rest[i]
+				continue
+			}
+			if i+1 >= len(rest) {
+				return fmt.Errorf("%s requires a value", rest[i])
+			}
+			val := rest[i+1]
+			i++
+			switch rest[i-1] {
+			case "--event":
+				typ, ok := taskv1.TaskEventType_value["TASK_EVENT_TYPE_"+strings.ToUpper(val)]
+				if !ok {
+					return fmt.Errorf("unknown event type: %s", val)
+				}
+				req.EventTypes = append(req.EventTypes, taskv1.TaskEventType(typ))
+			case "--secret":
+				req.Secret = val
+			default:
+				return fmt.Errorf("unknown flag: %s", rest[i-1])
+			}
+		}
+		hook, err := c.CreateWebhook(ctx, req)
+		if err != nil {
+			return err
+		}
+		log.Printf("Added Webhook ID: %s URL: %s Secret: %s", hook.GetWebhookId(), hook.GetUrl(), hook.GetSecret())
+	case "list":
+		resp, err := c.ListWebhooks(ctx, &taskv1.ListWebhooksRequest{})
+		if err != nil {
+			return err
+		}
+		for _, hook := range resp.GetWebhooks() {
+			log.Printf("Webhook ID: %s URL: %s Events: %v", hook.GetWebhookId(), hook.GetUrl(), hook.GetEventTypes())
+		}
+	case "delete":
+		if len(rest) != 1 {
+			return fmt.Errorf("webhook id is required")
+		}
+		if _, err := c.DeleteWebhook(ctx, &taskv1.DeleteWebhookRequest{WebhookId: rest[0]}); err != nil {
+			return err
+		}
+		log.Printf("Deleted Webhook ID: %s", rest[0])
+	case "deliveries":
+		req := &taskv1.ListWebhookDeliveriesRequest{PageSize: 100}
+		if len(rest) >= 1 {
+			req.WebhookId = rest[0]
+		}
+		resp, err := c.ListWebhookDeliveries(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, d := range resp.GetDeliveries() {
+			log.Printf("Delivery ID: %s Webhook: %s Event: %s State: %s Attempts: %d Code: %d Error: %s", d.GetDeliveryId(), d.GetWebhookId(), d.GetEvent().GetType(), d.GetState(), d.GetAttempts(), d.GetLastStatusCode(), d.GetLastError())
+		}
+	case "replay":
+		if len(rest) != 1 {
+			return fmt.Errorf("delivery id is required")
+		}
+		d, err := c.ReplayWebhookDelivery(ctx, &taskv1.ReplayWebhookDeliveryRequest{DeliveryId: rest[0]})
+		if err != nil {
+			return err
+		}
+		log.Printf("Replaying as Delivery ID: %s", d.GetDeliveryId())
+	default:
+		return fmt.Errorf("unknown webhook command: %s", sub)
+	}
+	return nil
+}
+
+func runQueue(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: queue create|set <name> [max_concurrency] | queue list")
+	}
+	sub, rest := args[0], args[1:]
+	var max_concurrency int64
+	if (sub == "create" || sub == "set") && len(rest) == 2 {
+		var err error
+		max_concurrency, err = strconv.ParseInt(rest[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid max_concurrency: %s", rest[1])
+		}
+		rest = rest[:1]
+	}
+	switch sub {
+	case "create":
+		if len(rest) != 1 {
+			return fmt.Errorf("queue name is required")
+		}
+		q, err := c.CreateQueue(ctx, &taskv1.CreateQueueRequest{Name: rest[0], MaxConcurrency: int32(max_concurrency)})
+		if err != nil {
+			return err
+		}
+		log.Printf("Created Queue: %s MaxConcurrency: %d", q.GetName(), q.GetMaxConcurrency())
+	case "set":
+		if len(rest) != 1 {
+			return fmt.Errorf("queue name is required")
+		}
+		q, err := c.UpdateQueue(ctx, &taskv1.UpdateQueueRequest{Name: rest[0], MaxConcurrency: int32(max_concurrency)})
+		if err != nil {
+			return err
+		}
+		log.Printf("Updated Queue: %s MaxConcurrency: %d", q.GetName(), q.GetMaxConcurrency())
+	case "list":
+		resp, err := c.ListQueues(ctx, &taskv1.ListQueuesRequest{})
+		if err != nil {
+			return err
+		}
+		for _, q := range resp.GetQueues() {
+			log.Printf("Queue: %s MaxConcurrency: %d Pending: %d Running: %d", q.GetName(), q.GetMaxConcurrency(), q.GetPending(), q.GetRunning())
+		}
+	default:
+		return fmt.Errorf("unknown queue command: %s", sub)
+	}
+	return nil
+}
+
+// runSchedule manages recurring tasks, e.g. a report every night at two:
+// schedule add --tz Europe/Berlin "0 2 * * *" "nightly report"
+func runSchedule(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: schedule add|list|get|pause|resume|delete ...")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "add":
+		req := &taskv1.CreateScheduleRequest{Template: &taskv1.CreateTaskRequest{Labels: map[string]string{}}}
+		var positional []string
+		for i := 0; i < len(rest); i++ {
+			switch rest[i] {
+			case "--catch-up":
+				req.CatchUp = true
+				continue
+			case "--paused":
+				req.Paused = true
+				continue
+			}
+			if !strings.HasPrefix(rest[i], "--") {
+				positional = append(positional, rest[i])
+				continue
+			}
+			if i+1 >= len(rest) {
+				return fmt.Errorf("%s requires a value", rest[i])
+			}
+			val := rest[i+1]
+			i++
+			switch rest[i-1] {
+			case "--tz":
+				req.TimeZone = val
+			case "--queue":
+				req.Template.Queue = val
+			case "--label":
+				k, v, ok := strings.Cut(val, "=")
+				if !ok {
+					return fmt.Errorf("invalid label: %s", val)
+				}
+				req.Template.Labels[k] = v
+			case "--input":
+				input, err := parseJSONObject(val)
+				if err != nil {
+					return err
+				}
+				req.Template.Input = input
+			case "--overlap":
+				policy, ok := taskv1.OverlapPolicy_value["OVERLAP_POLICY_"+strings.ToUpper(val)]
+				if !ok {
+					return fmt.Errorf("unknown overlap policy: %s", val)
+				}
+				req.OverlapPolicy = taskv1.OverlapPolicy(policy)
+			default:
+				return fmt.Errorf("unknown flag: %s", rest[i-1])
+			}
+		}
+		if len(positional) < 2 {
+			return fmt.Errorf("cron expression and task title are required")
+		}
+		req.Cron = positional[0]
+		req.Template.Title = positional[1]
+		req.Template.Description = strings.Join(positional[2:], " ")
+		sched, err := c.CreateSchedule(ctx, req)
+		if err != nil {
+			return err
+		}
+		log.Printf("Created Schedule ID: %s Next run: %s", sched.GetScheduleId(), sched.GetNextRunAt().AsTime().Local().Format(time.RFC3339))
+	case "list":
+		resp, err := c.ListSchedules(ctx, &taskv1.ListSchedulesRequest{})
+		if err != nil {
+			return err
+		}
+		for _, sched := range resp.GetSchedules() {
+			logSchedule(sched)
+		}
+	case "get":
+		if len(rest) != 1 {
+			return fmt.Errorf("schedule id is required")
+		}
+		sched, err := c.GetSchedule(ctx, &taskv1.GetScheduleRequest{ScheduleId: rest[0]})
+		if err != nil {
+			return err
+		}
+		logSchedule(sched)
+	case "pause", "resume":
+		if len(rest) != 1 {
+			return fmt.Errorf("schedule id is required")
+		}
+		sched, err := c.GetSchedule(ctx, &taskv1.GetScheduleRequest{ScheduleId: rest[0]})
+		if err != nil {
+			return err
+		}
+		sched, err = c.UpdateSchedule(ctx, &taskv1.UpdateScheduleRequest{
+			ScheduleId:    sched.GetScheduleId(),
+			Cron:          sched.GetCron(),
+			TimeZone:      sched.GetTimeZone(),
+			Template:      sched.GetTemplate(),
+			OverlapPolicy: sched.GetOverlapPolicy(),
+			CatchUp:       sched.GetCatchUp(),
+			Paused:        sub == "pause",
+		})
+		if err != nil {
+			return err
+		}
+		logSchedule(sched)
+	case "delete":
+		if len(rest) != 1 {
+			return fmt.Errorf("schedule id is required")
+		}
+		if _, err := c.DeleteSchedule(ctx, &taskv1.DeleteScheduleRequest{ScheduleId: rest[0]}); err != nil {
+			return err
+		}
+		log.Printf("Deleted Schedule ID: %s", rest[0])
+	default:
+		return fmt.Errorf("unknown schedule command: %s", sub)
+	}
+	return nil
+}
+
+func logSchedule(sched *taskv1.Schedule) {
+	next := "paused"
+	if sched.GetNextRunAt() != nil {
+		next = sched.GetNextRunAt().AsTime().Local().Format(time.RFC3339)
+	}
+	log.Printf("Schedule ID: %s Cron: %q TZ: %s Title: %s Overlap: %s Next run: %s Last task: %s Queued runs: %d", sched.GetScheduleId(), sched.GetCron(), sched.GetTimeZone(), sched.GetTemplate().GetTitle(), sched.GetOverlapPolicy(), next, sched.GetLastTaskId(), sched.GetQueuedRuns())
+}
+
+func runDeadLetter(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: deadletter list [queue] | deadletter requeue <task_id>")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "list":
+		req := &taskv1.ListDeadLettersRequest{PageSize: 100}
+		if len(rest) >= 1 {
+			req.Queue = rest[0]
+		}
+		resp, err := c.ListDeadLetters(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.GetTasks() {
+			log.Printf("Task ID: %s Title: %s Queue: %s Attempts: %d Error: %s", task.GetTaskId(), task.GetTitle(), task.GetQueue(), task.GetAttempt(), task.GetError())
+		}
+	case "requeue":
+		if len(rest) != 1 {
+			return fmt.Errorf("task id is required")
+		}
+		task, err := c.RequeueDeadLetter(ctx, &taskv1.RequeueDeadLetterRequest{TaskId: rest[0]})
+		if err != nil {
+			return err
+		}
+		log.Printf("Requeued Task ID: %s Status: %s", task.GetTaskId(), task.GetStatus())
+	default:
+		return fmt.Errorf("unknown deadletter command: %s", sub)
+	}
+	return nil
+}
+
+func runCancel(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: cancel <task_id> [reason]")
+	}
+	task, err := c.CancelTask(ctx, &taskv1.CancelTaskRequest{TaskId: args[0], Reason: strings.Join(args[1:], " ")})
+	if err != nil {
+		return err
+	}
+	log.Printf("Canceled Task ID: %s Status: %s", task.GetTaskId(), task.GetStatus())
+	return nil
+}
+
+func runLogs(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	req := &taskv1.TailTaskLogsRequest{}
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-f", "--follow":
+			req.Follow = true
+		case "-n", "--tail":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a line count", args[i])
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid line count: %s", args[i+1])
+			}
+			req.TailLines = int32(n)
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: logs [-f] [-n lines] <task_id>")
+	}
+	req.TaskId = rest[0]
+	stream, err := c.TailTaskLogs(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(line.GetText())
+	}
+}
+
+func runAttach(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("task id and file path are required")
+	}
+	data, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	stream, err := c.UploadAttachment(ctx)
+	if err != nil {
+		return err
+	}
+	meta := &taskv1.AttachmentMetadata{
+		TaskId:    args[0],
+		Filename:  filepath.Base(args[1]),
+		SizeBytes: int64(len(data)),
+		Sha256:    hex.EncodeToString(sum[:]),
+	}
+	if err := stream.Send(&taskv1.UploadAttachmentRequest{Payload: &taskv1.UploadAttachmentRequest_Metadata{Metadata: meta}}); err != nil {
+		return err
+	}
+	for len(data) > 0 {
+		n := min(64<<10, len(data))
+		if err := stream.Send(&taskv1.UploadAttachmentRequest{Payload: &taskv1.UploadAttachmentRequest_Chunk{Chunk: data[:n]}}); err != nil {
+			// The server aborted; CloseAndRecv reports why.
+			break
+		}
+		data = data[n:]
+	}
+	attachment, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	log.Printf("Uploaded Attachment ID: %s Filename: %s Size: %d SHA-256: %s", attachment.GetAttachmentId(), attachment.GetFilename(), attachment.GetSizeBytes(), attachment.GetSha256())
+	return nil
+}
+
+func runDownload(ctx context.Context, c taskv1.TaskServiceClient, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("task id, attachment id and output path are required")
+	}
+	stream, err := c.DownloadAttachment(ctx, &taskv1.DownloadAttachmentRequest{TaskId: args[0], AttachmentId: args[1]})
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(args[2])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if a := msg.GetAttachment(); a != nil {
+			log.Printf("Downloading %s (%d bytes)", a.GetFilename(), a.GetSizeBytes())
+			continue
+		}
+		if _, err := f.Write(msg.GetChunk()); err != nil {
+			return err
+		}
+	}
+	log.Printf("Saved to %s", args[2])
+	return nil
+}
+
 func main() {
 	var cmd string
 	var args []string
 	var err error
 	if len(os.Args) < 2 {
-		log.Printf("No inputs given. Usage: taskclient create <title> [description] | taskclient get <task_id>")
+		log.Printf("No inputs given. Usage: taskclient create [--input <json>] <title> [description] | taskclient get <task_id>")
 		return
 	} else {
 		cmd = os.Args[1]
@@ -197,6 +1028,13 @@ func main() {
 	defer conn.Close()
 	c := taskv1.NewTaskServiceClient(conn)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	switch cmd {
+	case "watch", "watch-all", "logs", "attach", "download":
+		// Watches and followed logs run until the task finishes, and
+		// attachments of up to 32 MiB take as long as they take.
+		cancel()
+		ctx, cancel = context.WithCancel(context.Background())
+	}
 	defer cancel()
 
 	switch cmd {
@@ -204,16 +1042,44 @@ func main() {
 		err = runCreate(ctx, c, args)
 	case "get":
 		err = runGet(ctx, c, args)
+	case "result":
+		err = runResult(ctx, c, args)
 	case "list":
 		err = runList(ctx, c, args)
+	case "stats":
+		err = runStats(ctx, c, args)
+	case "search":
+		err = runSearch(ctx, c, args)
 	case "watch":
 		err = runWatch(ctx, c, args)
+	case "watch-all":
+		err = runWatchAll(ctx, c, args)
+	case "events":
+		err = runEvents(ctx, c, args)
 	case "bulk-create":
 		err = runBulkCreate(ctx, c, args)
 	case "console":
 		err = runTaskConsole(ctx, c, args)
+	case "comment":
+		err = runComment(ctx, c, args)
+	case "webhook":
+		err = runWebhook(ctx, c, args)
+	case "queue":
+		err = runQueue(ctx, c, args)
+	case "schedule":
+		err = runSchedule(ctx, c, args)
+	case "deadletter":
+		err = runDeadLetter(ctx, c, args)
+	case "cancel":
+		err = runCancel(ctx, c, args)
+	case "logs":
+		err = runLogs(ctx, c, args)
+	case "attach":
+		err = runAttach(ctx, c, args)
+	case "download":
+		err = runDownload(ctx, c, args)
 	default:
-		log.Printf("Unknown command: %s. Usage: taskclient create <title> [description] | taskclient get <task_id>", cmd)
+		log.Printf("Unknown command: %s. Usage: taskclient create [--input <json>] <title> [description] | taskclient get <task_id>", cmd)
 		return
 	}
 	if err != nil {
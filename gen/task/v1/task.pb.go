@@ -9,6 +9,8 @@ package taskv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -31,6 +33,8 @@ const (
 	TaskStatus_TASK_STATUS_COMPLETED   TaskStatus = 3
 	TaskStatus_TASK_STATUS_FAILED      TaskStatus = 4
 	TaskStatus_TASK_STATUS_CANCELED    TaskStatus = 5
+	// Waiting for run_at; becomes PENDING then.
+	TaskStatus_TASK_STATUS_SCHEDULED TaskStatus = 6
 )
 
 // Enum value maps for TaskStatus.
@@ -42,6 +46,7 @@ var (
 		3: "TASK_STATUS_COMPLETED",
 		4: "TASK_STATUS_FAILED",
 		5: "TASK_STATUS_CANCELED",
+		6: "TASK_STATUS_SCHEDULED",
 	}
 	TaskStatus_value = map[string]int32{
 		"TASK_STATUS_UNSPECIFIED": 0,
@@ -50,6 +55,7 @@ var (
 		"TASK_STATUS_COMPLETED":   3,
 		"TASK_STATUS_FAILED":      4,
 		"TASK_STATUS_CANCELED":    5,
+		"TASK_STATUS_SCHEDULED":   6,
 	}
 )
 
@@ -80,14 +86,232 @@ func (TaskStatus) EnumDescriptor() ([]byte, []int) {
 	return file_task_v1_task_proto_rawDescGZIP(), []int{0}
 }
 
+type TaskEventType int32
+
+const (
+	TaskEventType_TASK_EVENT_TYPE_UNSPECIFIED     TaskEventType = 0
+	TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED  TaskEventType = 1
+	TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED   TaskEventType = 2
+	TaskEventType_TASK_EVENT_TYPE_COMMENT_EDITED  TaskEventType = 3
+	TaskEventType_TASK_EVENT_TYPE_COMMENT_DELETED TaskEventType = 4
+	TaskEventType_TASK_EVENT_TYPE_CREATED         TaskEventType = 5
+	TaskEventType_TASK_EVENT_TYPE_UPDATED         TaskEventType = 6
+	// Current state of the task, sent when a watch starts.
+	TaskEventType_TASK_EVENT_TYPE_SNAPSHOT TaskEventType = 7
+	// Keep-alive carrying the revision the stream is current as of and
+	// the server time; no task changed.
+	TaskEventType_TASK_EVENT_TYPE_HEARTBEAT TaskEventType = 8
+	// The server dropped events because the stream fell behind; revision is
+	// the newest one lost. Resume from an earlier revision or re-list.
+	TaskEventType_TASK_EVENT_TYPE_GAP TaskEventType = 9
+	// The worker reported progress; message is the progress message. At
+	// most one is published per task per second unless the step changes,
+	// but task always carries the latest progress.
+	TaskEventType_TASK_EVENT_TYPE_PROGRESS TaskEventType = 10
+)
+
+// Enum value maps for TaskEventType.
+var (
+	TaskEventType_name = map[int32]string{
+		0:  "TASK_EVENT_TYPE_UNSPECIFIED",
+		1:  "TASK_EVENT_TYPE_STATUS_CHANGED",
+		2:  "TASK_EVENT_TYPE_COMMENT_ADDED",
+		3:  "TASK_EVENT_TYPE_COMMENT_EDITED",
+		4:  "TASK_EVENT_TYPE_COMMENT_DELETED",
+		5:  "TASK_EVENT_TYPE_CREATED",
+		6:  "TASK_EVENT_TYPE_UPDATED",
+		7:  "TASK_EVENT_TYPE_SNAPSHOT",
+		8:  "TASK_EVENT_TYPE_HEARTBEAT",
+		9:  "TASK_EVENT_TYPE_GAP",
+		10: "TASK_EVENT_TYPE_PROGRESS",
+	}
+	TaskEventType_value = map[string]int32{
+		"TASK_EVENT_TYPE_UNSPECIFIED":     0,
+		"TASK_EVENT_TYPE_STATUS_CHANGED":  1,
+		"TASK_EVENT_TYPE_COMMENT_ADDED":   2,
+		"TASK_EVENT_TYPE_COMMENT_EDITED":  3,
+		"TASK_EVENT_TYPE_COMMENT_DELETED": 4,
+		"TASK_EVENT_TYPE_CREATED":         5,
+		"TASK_EVENT_TYPE_UPDATED":         6,
+		"TASK_EVENT_TYPE_SNAPSHOT":        7,
+		"TASK_EVENT_TYPE_HEARTBEAT":       8,
+		"TASK_EVENT_TYPE_GAP":             9,
+		"TASK_EVENT_TYPE_PROGRESS":        10,
+	}
+)
+
+func (x TaskEventType) Enum() *TaskEventType {
+	p := new(TaskEventType)
+	*p = x
+	return p
+}
+
+func (x TaskEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_task_v1_task_proto_enumTypes[1].Descriptor()
+}
+
+func (TaskEventType) Type() protoreflect.EnumType {
+	return &file_task_v1_task_proto_enumTypes[1]
+}
+
+func (x TaskEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskEventType.Descriptor instead.
+func (TaskEventType) EnumDescriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{1}
+}
+
+type WebhookDeliveryState int32
+
+const (
+	WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_UNSPECIFIED WebhookDeliveryState = 0
+	WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_PENDING     WebhookDeliveryState = 1
+	WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_SUCCEEDED   WebhookDeliveryState = 2
+	WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_FAILED      WebhookDeliveryState = 3
+)
+
+// Enum value maps for WebhookDeliveryState.
+var (
+	WebhookDeliveryState_name = map[int32]string{
+		0: "WEBHOOK_DELIVERY_STATE_UNSPECIFIED",
+		1: "WEBHOOK_DELIVERY_STATE_PENDING",
+		2: "WEBHOOK_DELIVERY_STATE_SUCCEEDED",
+		3: "WEBHOOK_DELIVERY_STATE_FAILED",
+	}
+	WebhookDeliveryState_value = map[string]int32{
+		"WEBHOOK_DELIVERY_STATE_UNSPECIFIED": 0,
+		"WEBHOOK_DELIVERY_STATE_PENDING":     1,
+		"WEBHOOK_DELIVERY_STATE_SUCCEEDED":   2,
+		"WEBHOOK_DELIVERY_STATE_FAILED":      3,
+	}
+)
+
+func (x WebhookDeliveryState) Enum() *WebhookDeliveryState {
+	p := new(WebhookDeliveryState)
+	*p = x
+	return p
+}
+
+func (x WebhookDeliveryState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WebhookDeliveryState) Descriptor() protoreflect.EnumDescriptor {
+	return file_task_v1_task_proto_enumTypes[2].Descriptor()
+}
+
+func (WebhookDeliveryState) Type() protoreflect.EnumType {
+	return &file_task_v1_task_proto_enumTypes[2]
+}
+
+func (x WebhookDeliveryState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WebhookDeliveryState.Descriptor instead.
+func (WebhookDeliveryState) EnumDescriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{2}
+}
+
+// What a schedule does when a run is due while the task of its previous
+// run is still unfinished.
+type OverlapPolicy int32
+
+const (
+	// Treated as SKIP.
+	OverlapPolicy_OVERLAP_POLICY_UNSPECIFIED OverlapPolicy = 0
+	// Do not create a task for this run.
+	OverlapPolicy_OVERLAP_POLICY_SKIP OverlapPolicy = 1
+	// Hold the run and create its task once the previous task finished.
+	OverlapPolicy_OVERLAP_POLICY_QUEUE OverlapPolicy = 2
+	// Cancel the previous task and create a new one.
+	OverlapPolicy_OVERLAP_POLICY_REPLACE OverlapPolicy = 3
+)
+
+// Enum value maps for OverlapPolicy.
+var (
+	OverlapPolicy_name = map[int32]string{
+		0: "OVERLAP_POLICY_UNSPECIFIED",
+		1: "OVERLAP_POLICY_SKIP",
+		2: "OVERLAP_POLICY_QUEUE",
+		3: "OVERLAP_POLICY_REPLACE",
+	}
+	OverlapPolicy_value = map[string]int32{
+		"OVERLAP_POLICY_UNSPECIFIED": 0,
+		"OVERLAP_POLICY_SKIP":        1,
+		"OVERLAP_POLICY_QUEUE":       2,
+		"OVERLAP_POLICY_REPLACE":     3,
+	}
+)
+
+func (x OverlapPolicy) Enum() *OverlapPolicy {
+	p := new(OverlapPolicy)
+	*p = x
+	return p
+}
+
+func (x OverlapPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OverlapPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_task_v1_task_proto_enumTypes[3].Descriptor()
+}
+
+func (OverlapPolicy) Type() protoreflect.EnumType {
+	return &file_task_v1_task_proto_enumTypes[3]
+}
+
+func (x OverlapPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OverlapPolicy.Descriptor instead.
+func (OverlapPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{3}
+}
+
 type Task struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Status        TaskStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=task.v1.TaskStatus" json:"status,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TaskId      string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status      TaskStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=task.v1.TaskStatus" json:"status,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Input       *structpb.Struct       `protobuf:"bytes,7,opt,name=input,proto3" json:"input,omitempty"`
+	Result      *structpb.Struct       `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	Labels      map[string]string      `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ParentId    string                 `protobuf:"bytes,10,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// The most recent worker lease; only current while RUNNING.
+	Lease *Lease `protobuf:"bytes,11,opt,name=lease,proto3" json:"lease,omitempty"`
+	// Reported by the worker when the task failed.
+	Error string `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	// Name of the queue the task is dispatched from.
+	Queue string `protobuf:"bytes,13,opt,name=queue,proto3" json:"queue,omitempty"`
+	// When the task becomes eligible to run; unset means on creation.
+	RunAt       *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`
+	RetryPolicy *RetryPolicy           `protobuf:"bytes,15,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// Times the task was claimed by a worker.
+	Attempt int32 `protobuf:"varint,16,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// Class of error, as reported with FailTask. The server uses "timeout"
+	// for an attempt that exceeded execution_timeout and "lease_expired"
+	// for one whose worker stopped renewing its lease.
+	ErrorClass string `protobuf:"bytes,17,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	// Set when the task failed for good despite its retry policy. Such
+	// tasks are listed by ListDeadLetters.
+	DeadLetteredAt *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=dead_lettered_at,json=deadLetteredAt,proto3" json:"dead_lettered_at,omitempty"`
+	// Longest an attempt may run, from its claim; unset means no limit. An
+	// attempt that runs longer fails with error_class "timeout".
+	ExecutionTimeout *durationpb.Duration `protobuf:"bytes,19,opt,name=execution_timeout,json=executionTimeout,proto3" json:"execution_timeout,omitempty"`
+	// Latest progress reported for the current attempt.
+	Progress      *TaskProgress `protobuf:"bytes,20,opt,name=progress,proto3" json:"progress,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -164,28 +388,130 @@ func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type CreateTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+func (x *Task) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *Task) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Task) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Task) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Task) GetLease() *Lease {
+	if x != nil {
+		return x.Lease
+	}
+	return nil
+}
+
+func (x *Task) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Task) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *Task) GetRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RunAt
+	}
+	return nil
+}
+
+func (x *Task) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *Task) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Task) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *Task) GetDeadLetteredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeadLetteredAt
+	}
+	return nil
+}
+
+func (x *Task) GetExecutionTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ExecutionTimeout
+	}
+	return nil
+}
+
+func (x *Task) GetProgress() *TaskProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type TaskProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// From 0 to 100.
+	Percent float64 `protobuf:"fixed64,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	// Name of the step the worker is on, e.g. "download".
+	Step          string                 `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTaskRequest) Reset() {
-	*x = CreateTaskRequest{}
+func (x *TaskProgress) Reset() {
+	*x = TaskProgress{}
 	mi := &file_task_v1_task_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskRequest) String() string {
+func (x *TaskProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskRequest) ProtoMessage() {}
+func (*TaskProgress) ProtoMessage() {}
 
-func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+func (x *TaskProgress) ProtoReflect() protoreflect.Message {
 	mi := &file_task_v1_task_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -197,48 +523,74 @@ func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
-func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TaskProgress.ProtoReflect.Descriptor instead.
+func (*TaskProgress) Descriptor() ([]byte, []int) {
 	return file_task_v1_task_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *CreateTaskRequest) GetTitle() string {
+func (x *TaskProgress) GetPercent() float64 {
 	if x != nil {
-		return x.Title
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *TaskProgress) GetStep() string {
+	if x != nil {
+		return x.Step
 	}
 	return ""
 }
 
-func (x *CreateTaskRequest) GetDescription() string {
+func (x *TaskProgress) GetMessage() string {
 	if x != nil {
-		return x.Description
+		return x.Message
 	}
 	return ""
 }
 
-type CreateTaskWithIdRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TaskProgress) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
 }
 
-func (x *CreateTaskWithIdRequest) Reset() {
-	*x = CreateTaskWithIdRequest{}
+// How a failed task is retried. A retry makes the task SCHEDULED with
+// run_at after the backoff, or PENDING straight away when that is zero.
+type RetryPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Attempts in total, including the first; 0 or 1 disables retries.
+	MaxAttempts int32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Delay before the first retry; defaults to 1s.
+	InitialBackoff *durationpb.Duration `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	// Growth of the delay per retry, at least 1; defaults to 2.
+	BackoffMultiplier float64 `protobuf:"fixed64,3,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`
+	// Defaults to 5m, or initial_backoff if that is longer.
+	MaxBackoff *durationpb.Duration `protobuf:"bytes,4,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	// Fraction of each delay, between 0 and 1, that is randomised so tasks
+	// failing together do not retry together.
+	Jitter float64 `protobuf:"fixed64,5,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	// Error classes worth retrying; empty retries every failure.
+	RetryableErrors []string `protobuf:"bytes,6,rep,name=retryable_errors,json=retryableErrors,proto3" json:"retryable_errors,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
 	mi := &file_task_v1_task_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskWithIdRequest) String() string {
+func (x *RetryPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskWithIdRequest) ProtoMessage() {}
+func (*RetryPolicy) ProtoMessage() {}
 
-func (x *CreateTaskWithIdRequest) ProtoReflect() protoreflect.Message {
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
 	mi := &file_task_v1_task_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -250,54 +602,4941 @@ func (x *CreateTaskWithIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskWithIdRequest.ProtoReflect.Descriptor instead.
-func (*CreateTaskWithIdRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
 	return file_task_v1_task_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *CreateTaskWithIdRequest) GetTaskId() string {
+func (x *RetryPolicy) GetMaxAttempts() int32 {
 	if x != nil {
-		return x.TaskId
+		return x.MaxAttempts
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateTaskWithIdRequest) GetTitle() string {
+func (x *RetryPolicy) GetInitialBackoff() *durationpb.Duration {
 	if x != nil {
-		return x.Title
+		return x.InitialBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryableErrors() []string {
+	if x != nil {
+		return x.RetryableErrors
+	}
+	return nil
+}
+
+type Lease struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeaseId       string                 `protobuf:"bytes,1,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	ClaimedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=claimed_at,json=claimedAt,proto3" json:"claimed_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lease) Reset() {
+	*x = Lease{}
+	mi := &file_task_v1_task_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lease) ProtoMessage() {}
+
+func (x *Lease) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lease.ProtoReflect.Descriptor instead.
+func (*Lease) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Lease) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
 	}
 	return ""
 }
 
-func (x *CreateTaskWithIdRequest) GetDescription() string {
+func (x *Lease) GetWorkerId() string {
 	if x != nil {
-		return x.Description
+		return x.WorkerId
 	}
 	return ""
 }
 
-type CreateTaskResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+func (x *Lease) GetClaimedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClaimedAt
+	}
+	return nil
+}
+
+func (x *Lease) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type CreateTaskRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Input       *structpb.Struct       `protobuf:"bytes,3,opt,name=input,proto3" json:"input,omitempty"`
+	Labels      map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ParentId    string                 `protobuf:"bytes,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Defaults to "default"; the queue must exist.
+	Queue string `protobuf:"bytes,6,opt,name=queue,proto3" json:"queue,omitempty"`
+	// A future run_at creates the task SCHEDULED instead of PENDING.
+	RunAt            *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`
+	RetryPolicy      *RetryPolicy           `protobuf:"bytes,8,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	ExecutionTimeout *durationpb.Duration   `protobuf:"bytes,9,opt,name=execution_timeout,json=executionTimeout,proto3" json:"execution_timeout,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RunAt
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetExecutionTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ExecutionTimeout
+	}
+	return nil
+}
+
+type CreateTaskWithIdRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TaskId      string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Input       *structpb.Struct       `protobuf:"bytes,4,opt,name=input,proto3" json:"input,omitempty"`
+	Labels      map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ParentId    string                 `protobuf:"bytes,6,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// As in CreateTaskRequest.
+	Queue            string                 `protobuf:"bytes,7,opt,name=queue,proto3" json:"queue,omitempty"`
+	RunAt            *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`
+	RetryPolicy      *RetryPolicy           `protobuf:"bytes,9,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	ExecutionTimeout *durationpb.Duration   `protobuf:"bytes,10,opt,name=execution_timeout,json=executionTimeout,proto3" json:"execution_timeout,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateTaskWithIdRequest) Reset() {
+	*x = CreateTaskWithIdRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskWithIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskWithIdRequest) ProtoMessage() {}
+
+func (x *CreateTaskWithIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskWithIdRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskWithIdRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateTaskWithIdRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CreateTaskWithIdRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTaskWithIdRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTaskWithIdRequest) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *CreateTaskWithIdRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateTaskWithIdRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateTaskWithIdRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *CreateTaskWithIdRequest) GetRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RunAt
+	}
+	return nil
+}
+
+func (x *CreateTaskWithIdRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *CreateTaskWithIdRequest) GetExecutionTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ExecutionTimeout
+	}
+	return nil
+}
+
+type CreateTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskResponse) Reset() {
+	*x = CreateTaskResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskResponse) ProtoMessage() {}
+
+func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
+func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type GetTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type ListTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type WatchTaskRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Resume after this revision: buffered events are replayed instead of a
+	// snapshot. Fails with OUT_OF_RANGE when that history is gone.
+	SinceRevision int64 `protobuf:"varint,2,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"`
+	// Send HEARTBEAT events at this interval while the stream is idle.
+	// Unset disables heartbeats.
+	HeartbeatInterval *durationpb.Duration `protobuf:"bytes,3,opt,name=heartbeat_interval,json=heartbeatInterval,proto3" json:"heartbeat_interval,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchTaskRequest) Reset() {
+	*x = WatchTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTaskRequest) ProtoMessage() {}
+
+func (x *WatchTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTaskRequest.ProtoReflect.Descriptor instead.
+func (*WatchTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *WatchTaskRequest) GetSinceRevision() int64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+func (x *WatchTaskRequest) GetHeartbeatInterval() *durationpb.Duration {
+	if x != nil {
+		return x.HeartbeatInterval
+	}
+	return nil
+}
+
+type TaskEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Status  TaskStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=task.v1.TaskStatus" json:"status,omitempty"`
+	At      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	Message string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId  string                 `protobuf:"bytes,4,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Type    TaskEventType          `protobuf:"varint,5,opt,name=type,proto3,enum=task.v1.TaskEventType" json:"type,omitempty"`
+	Comment *Comment               `protobuf:"bytes,6,opt,name=comment,proto3" json:"comment,omitempty"`
+	// State of the task after the change.
+	Task *Task `protobuf:"bytes,7,opt,name=task,proto3" json:"task,omitempty"`
+	// Status before the change; equal to status when it did not change.
+	PreviousStatus TaskStatus `protobuf:"varint,8,opt,name=previous_status,json=previousStatus,proto3,enum=task.v1.TaskStatus" json:"previous_status,omitempty"`
+	// Server-wide, monotonically increasing. Snapshots carry the revision
+	// they are current as of.
+	Revision int64 `protobuf:"varint,9,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Unique per change; stays the same when the outbox re-delivers an
+	// event, so consumers can discard duplicates.
+	EventId string `protobuf:"bytes,10,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// User whose call caused the change.
+	Actor         string `protobuf:"bytes,11,opt,name=actor,proto3" json:"actor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_task_v1_task_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TaskEvent) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *TaskEvent) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetType() TaskEventType {
+	if x != nil {
+		return x.Type
+	}
+	return TaskEventType_TASK_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *TaskEvent) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetPreviousStatus() TaskStatus {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *TaskEvent) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *TaskEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+type TaskFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matches any of the listed statuses; empty matches all.
+	Statuses []TaskStatus `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=task.v1.TaskStatus" json:"statuses,omitempty"`
+	// Every listed label must be present with the same value.
+	Labels        map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ParentId      string            `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Queue         string            `protobuf:"bytes,4,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskFilter) Reset() {
+	*x = TaskFilter{}
+	mi := &file_task_v1_task_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskFilter) ProtoMessage() {}
+
+func (x *TaskFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskFilter.ProtoReflect.Descriptor instead.
+func (*TaskFilter) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TaskFilter) GetStatuses() []TaskStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *TaskFilter) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *TaskFilter) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *TaskFilter) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type WatchTasksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *TaskFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Send a SNAPSHOT event for every currently matching task before live events.
+	SendInitialSnapshot bool `protobuf:"varint,2,opt,name=send_initial_snapshot,json=sendInitialSnapshot,proto3" json:"send_initial_snapshot,omitempty"`
+	// As in WatchTaskRequest.
+	SinceRevision     int64                `protobuf:"varint,3,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"`
+	HeartbeatInterval *durationpb.Duration `protobuf:"bytes,4,opt,name=heartbeat_interval,json=heartbeatInterval,proto3" json:"heartbeat_interval,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchTasksRequest) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetSendInitialSnapshot() bool {
+	if x != nil {
+		return x.SendInitialSnapshot
+	}
+	return false
+}
+
+func (x *WatchTasksRequest) GetSinceRevision() int64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+func (x *WatchTasksRequest) GetHeartbeatInterval() *durationpb.Duration {
+	if x != nil {
+		return x.HeartbeatInterval
+	}
+	return nil
+}
+
+type BulkCreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreatedCount  int32                  `protobuf:"varint,1,opt,name=created_count,json=createdCount,proto3" json:"created_count,omitempty"`
+	TaskIds       []string               `protobuf:"bytes,2,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateResponse) Reset() {
+	*x = BulkCreateResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateResponse) ProtoMessage() {}
+
+func (x *BulkCreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateResponse.ProtoReflect.Descriptor instead.
+func (*BulkCreateResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BulkCreateResponse) GetCreatedCount() int32 {
+	if x != nil {
+		return x.CreatedCount
+	}
+	return 0
+}
+
+func (x *BulkCreateResponse) GetTaskIds() []string {
+	if x != nil {
+		return x.TaskIds
+	}
+	return nil
+}
+
+type ConsoleMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsoleMessage) Reset() {
+	*x = ConsoleMessage{}
+	mi := &file_task_v1_task_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsoleMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsoleMessage) ProtoMessage() {}
+
+func (x *ConsoleMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsoleMessage.ProtoReflect.Descriptor instead.
+func (*ConsoleMessage) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConsoleMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommentId     string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_task_v1_task_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Comment) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *Comment) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Comment) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Comment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type AddCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCommentRequest) Reset() {
+	*x = AddCommentRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCommentRequest) ProtoMessage() {}
+
+func (x *AddCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCommentRequest.ProtoReflect.Descriptor instead.
+func (*AddCommentRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AddCommentRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AddCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type ListCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsRequest) Reset() {
+	*x = ListCommentsRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsRequest) ProtoMessage() {}
+
+func (x *ListCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListCommentsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsResponse) Reset() {
+	*x = ListCommentsResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsResponse) ProtoMessage() {}
+
+func (x *ListCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *ListCommentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type EditCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditCommentRequest) Reset() {
+	*x = EditCommentRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditCommentRequest) ProtoMessage() {}
+
+func (x *EditCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditCommentRequest.ProtoReflect.Descriptor instead.
+func (*EditCommentRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EditCommentRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *EditCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *EditCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type DeleteCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentRequest) Reset() {
+	*x = DeleteCommentRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentRequest) ProtoMessage() {}
+
+func (x *DeleteCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommentRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteCommentRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *DeleteCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+type DeleteCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCommentResponse) Reset() {
+	*x = DeleteCommentResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentResponse) ProtoMessage() {}
+
+func (x *DeleteCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCommentResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{22}
+}
+
+type SetTaskResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Result        *structpb.Struct       `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTaskResultRequest) Reset() {
+	*x = SetTaskResultRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTaskResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTaskResultRequest) ProtoMessage() {}
+
+func (x *SetTaskResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTaskResultRequest.ProtoReflect.Descriptor instead.
+func (*SetTaskResultRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetTaskResultRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *SetTaskResultRequest) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type SearchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTasksRequest) Reset() {
+	*x = SearchTasksRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTasksRequest) ProtoMessage() {}
+
+func (x *SearchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTasksRequest.ProtoReflect.Descriptor instead.
+func (*SearchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SearchTasksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Task  *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Score float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// HTML-escaped text around the first match, with matching words in
+	// <em></em>.
+	Snippet       string `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_task_v1_task_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SearchResult) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTasksResponse) Reset() {
+	*x = SearchTasksResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTasksResponse) ProtoMessage() {}
+
+func (x *SearchTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTasksResponse.ProtoReflect.Descriptor instead.
+func (*SearchTasksResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SearchTasksResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetTaskStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskStatsRequest) Reset() {
+	*x = GetTaskStatsRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatsRequest) ProtoMessage() {}
+
+func (x *GetTaskStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskStatsRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{27}
+}
+
+type ThroughputWindow struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Window             *durationpb.Duration   `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Created            int64                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Completed          int64                  `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	CreatedPerMinute   float64                `protobuf:"fixed64,4,opt,name=created_per_minute,json=createdPerMinute,proto3" json:"created_per_minute,omitempty"`
+	CompletedPerMinute float64                `protobuf:"fixed64,5,opt,name=completed_per_minute,json=completedPerMinute,proto3" json:"completed_per_minute,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ThroughputWindow) Reset() {
+	*x = ThroughputWindow{}
+	mi := &file_task_v1_task_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThroughputWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThroughputWindow) ProtoMessage() {}
+
+func (x *ThroughputWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThroughputWindow.ProtoReflect.Descriptor instead.
+func (*ThroughputWindow) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ThroughputWindow) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *ThroughputWindow) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ThroughputWindow) GetCompleted() int64 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *ThroughputWindow) GetCreatedPerMinute() float64 {
+	if x != nil {
+		return x.CreatedPerMinute
+	}
+	return 0
+}
+
+func (x *ThroughputWindow) GetCompletedPerMinute() float64 {
+	if x != nil {
+		return x.CompletedPerMinute
+	}
+	return 0
+}
+
+type StatusDuration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        TaskStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=task.v1.TaskStatus" json:"status,omitempty"`
+	Samples       int64                  `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"`
+	Median        *durationpb.Duration   `protobuf:"bytes,3,opt,name=median,proto3" json:"median,omitempty"`
+	P95           *durationpb.Duration   `protobuf:"bytes,4,opt,name=p95,proto3" json:"p95,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusDuration) Reset() {
+	*x = StatusDuration{}
+	mi := &file_task_v1_task_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusDuration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusDuration) ProtoMessage() {}
+
+func (x *StatusDuration) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusDuration.ProtoReflect.Descriptor instead.
+func (*StatusDuration) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StatusDuration) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *StatusDuration) GetSamples() int64 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *StatusDuration) GetMedian() *durationpb.Duration {
+	if x != nil {
+		return x.Median
+	}
+	return nil
+}
+
+func (x *StatusDuration) GetP95() *durationpb.Duration {
+	if x != nil {
+		return x.P95
+	}
+	return nil
+}
+
+type TaskStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Total int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	// Keyed by TaskStatus name.
+	ByStatus map[string]int64 `protobuf:"bytes,2,rep,name=by_status,json=byStatus,proto3" json:"by_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Keyed by "key=value".
+	ByLabel      map[string]int64    `protobuf:"bytes,3,rep,name=by_label,json=byLabel,proto3" json:"by_label,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Throughput   []*ThroughputWindow `protobuf:"bytes,4,rep,name=throughput,proto3" json:"throughput,omitempty"`
+	TimeInStatus []*StatusDuration   `protobuf:"bytes,5,rep,name=time_in_status,json=timeInStatus,proto3" json:"time_in_status,omitempty"`
+	WatchStreams []*WatchStreamStats `protobuf:"bytes,6,rep,name=watch_streams,json=watchStreams,proto3" json:"watch_streams,omitempty"`
+	// Events dropped by slow watchers, including closed streams.
+	DroppedEventsTotal int64 `protobuf:"varint,7,opt,name=dropped_events_total,json=droppedEventsTotal,proto3" json:"dropped_events_total,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TaskStats) Reset() {
+	*x = TaskStats{}
+	mi := &file_task_v1_task_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStats) ProtoMessage() {}
+
+func (x *TaskStats) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStats.ProtoReflect.Descriptor instead.
+func (*TaskStats) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TaskStats) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *TaskStats) GetByStatus() map[string]int64 {
+	if x != nil {
+		return x.ByStatus
+	}
+	return nil
+}
+
+func (x *TaskStats) GetByLabel() map[string]int64 {
+	if x != nil {
+		return x.ByLabel
+	}
+	return nil
+}
+
+func (x *TaskStats) GetThroughput() []*ThroughputWindow {
+	if x != nil {
+		return x.Throughput
+	}
+	return nil
+}
+
+func (x *TaskStats) GetTimeInStatus() []*StatusDuration {
+	if x != nil {
+		return x.TimeInStatus
+	}
+	return nil
+}
+
+func (x *TaskStats) GetWatchStreams() []*WatchStreamStats {
+	if x != nil {
+		return x.WatchStreams
+	}
+	return nil
+}
+
+func (x *TaskStats) GetDroppedEventsTotal() int64 {
+	if x != nil {
+		return x.DroppedEventsTotal
+	}
+	return 0
+}
+
+type WatchStreamStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	Queued        int64                  `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`
+	Dropped       int64                  `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStreamStats) Reset() {
+	*x = WatchStreamStats{}
+	mi := &file_task_v1_task_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStreamStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStreamStats) ProtoMessage() {}
+
+func (x *WatchStreamStats) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStreamStats.ProtoReflect.Descriptor instead.
+func (*WatchStreamStats) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *WatchStreamStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WatchStreamStats) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *WatchStreamStats) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *WatchStreamStats) GetDropped() int64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+type Webhook struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Url       string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Empty means every event type.
+	EventTypes []TaskEventType `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=task.v1.TaskEventType" json:"event_types,omitempty"`
+	// When set, only task changes matching the filter are delivered.
+	Filter *TaskFilter `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	// HMAC-SHA256 key for X-Webhook-Signature. Only returned by CreateWebhook.
+	Secret        string                 `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_task_v1_task_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Webhook) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []TaskEventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Webhook) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+type CreateWebhookRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Url        string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes []TaskEventType        `protobuf:"varint,2,rep,packed,name=event_types,json=eventTypes,proto3,enum=task.v1.TaskEventType" json:"event_types,omitempty"`
+	Filter     *TaskFilter            `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Generated by the server when empty.
+	Secret        string `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEventTypes() []TaskEventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *CreateWebhookRequest) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CreateWebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{34}
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{37}
+}
+
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId     string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	WebhookId      string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event          *TaskEvent             `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	State          WebhookDeliveryState   `protobuf:"varint,4,opt,name=state,proto3,enum=task.v1.WebhookDeliveryState" json:"state,omitempty"`
+	Attempts       int32                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastStatusCode int32                  `protobuf:"varint,6,opt,name=last_status_code,json=lastStatusCode,proto3" json:"last_status_code,omitempty"`
+	LastError      string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Set when this delivery was created by ReplayWebhookDelivery.
+	ReplayOf      string `protobuf:"bytes,10,opt,name=replay_of,json=replayOf,proto3" json:"replay_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_task_v1_task_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *WebhookDelivery) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEvent() *TaskEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetState() WebhookDeliveryState {
+	if x != nil {
+		return x.State
+	}
+	return WebhookDeliveryState_WEBHOOK_DELIVERY_STATE_UNSPECIFIED
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastStatusCode() int32 {
+	if x != nil {
+		return x.LastStatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetReplayOf() string {
+	if x != nil {
+		return x.ReplayOf
+	}
+	return ""
+}
+
+// Body POSTed to webhook receivers, encoded as JSON.
+type WebhookPayload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId    string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event         *TaskEvent             `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookPayload) Reset() {
+	*x = WebhookPayload{}
+	mi := &file_task_v1_task_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookPayload) ProtoMessage() {}
+
+func (x *WebhookPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookPayload.ProtoReflect.Descriptor instead.
+func (*WebhookPayload) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *WebhookPayload) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *WebhookPayload) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookPayload) GetEvent() *TaskEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *ListWebhookDeliveriesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ReplayWebhookDeliveryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId    string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookDeliveryRequest) Reset() {
+	*x = ReplayWebhookDeliveryRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookDeliveryRequest) ProtoMessage() {}
+
+func (x *ReplayWebhookDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ReplayWebhookDeliveryRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+type AttachmentMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Sha256        string                 `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentMetadata) Reset() {
+	*x = AttachmentMetadata{}
+	mi := &file_task_v1_task_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentMetadata) ProtoMessage() {}
+
+func (x *AttachmentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentMetadata.ProtoReflect.Descriptor instead.
+func (*AttachmentMetadata) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *AttachmentMetadata) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *AttachmentMetadata) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type UploadAttachmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*UploadAttachmentRequest_Metadata
+	//	*UploadAttachmentRequest_Chunk
+	Payload       isUploadAttachmentRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentRequest) Reset() {
+	*x = UploadAttachmentRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentRequest) ProtoMessage() {}
+
+func (x *UploadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *UploadAttachmentRequest) GetPayload() isUploadAttachmentRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *UploadAttachmentRequest) GetMetadata() *AttachmentMetadata {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadAttachmentRequest_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *UploadAttachmentRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadAttachmentRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadAttachmentRequest_Payload interface {
+	isUploadAttachmentRequest_Payload()
+}
+
+type UploadAttachmentRequest_Metadata struct {
+	Metadata *AttachmentMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type UploadAttachmentRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadAttachmentRequest_Metadata) isUploadAttachmentRequest_Payload() {}
+
+func (*UploadAttachmentRequest_Chunk) isUploadAttachmentRequest_Payload() {}
+
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AttachmentId  string                 `protobuf:"bytes,1,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Sha256        string                 `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	UploadedBy    string                 `protobuf:"bytes,7,opt,name=uploaded_by,json=uploadedBy,proto3" json:"uploaded_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_task_v1_task_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Attachment) GetAttachmentId() string {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return ""
+}
+
+func (x *Attachment) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Attachment) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Attachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Attachment) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Attachment) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Attachment) GetUploadedBy() string {
+	if x != nil {
+		return x.UploadedBy
+	}
+	return ""
+}
+
+func (x *Attachment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type DownloadAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	AttachmentId  string                 `protobuf:"bytes,2,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentRequest) Reset() {
+	*x = DownloadAttachmentRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentRequest) ProtoMessage() {}
+
+func (x *DownloadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DownloadAttachmentRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *DownloadAttachmentRequest) GetAttachmentId() string {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return ""
+}
+
+type DownloadAttachmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*DownloadAttachmentResponse_Attachment
+	//	*DownloadAttachmentResponse_Chunk
+	Payload       isDownloadAttachmentResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentResponse) Reset() {
+	*x = DownloadAttachmentResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentResponse) ProtoMessage() {}
+
+func (x *DownloadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DownloadAttachmentResponse) GetPayload() isDownloadAttachmentResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DownloadAttachmentResponse) GetAttachment() *Attachment {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadAttachmentResponse_Attachment); ok {
+			return x.Attachment
+		}
+	}
+	return nil
+}
+
+func (x *DownloadAttachmentResponse) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadAttachmentResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isDownloadAttachmentResponse_Payload interface {
+	isDownloadAttachmentResponse_Payload()
+}
+
+type DownloadAttachmentResponse_Attachment struct {
+	Attachment *Attachment `protobuf:"bytes,1,opt,name=attachment,proto3,oneof"`
+}
+
+type DownloadAttachmentResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*DownloadAttachmentResponse_Attachment) isDownloadAttachmentResponse_Payload() {}
+
+func (*DownloadAttachmentResponse_Chunk) isDownloadAttachmentResponse_Payload() {}
+
+type ListEventsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Labels and parent are matched against the task as of the event.
+	Filter *TaskFilter `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Empty matches every type.
+	Types []TaskEventType `protobuf:"varint,3,rep,packed,name=types,proto3,enum=task.v1.TaskEventType" json:"types,omitempty"`
+	Actor string          `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`
+	// Inclusive lower and exclusive upper bound on the event time.
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListEventsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetTypes() []TaskEventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Oldest first.
+	Events        []*TaskEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string       `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsResponse) Reset() {
+	*x = ListEventsResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsResponse) ProtoMessage() {}
+
+func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListEventsResponse) GetEvents() []*TaskEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ClaimTaskRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	// Defaults to 30s; between 1s and 10m.
+	LeaseDuration *durationpb.Duration `protobuf:"bytes,2,opt,name=lease_duration,json=leaseDuration,proto3" json:"lease_duration,omitempty"`
+	// Only claim from these queues; empty claims from any.
+	Queues        []string `protobuf:"bytes,3,rep,name=queues,proto3" json:"queues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimTaskRequest) Reset() {
+	*x = ClaimTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimTaskRequest) ProtoMessage() {}
+
+func (x *ClaimTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimTaskRequest.ProtoReflect.Descriptor instead.
+func (*ClaimTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ClaimTaskRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ClaimTaskRequest) GetLeaseDuration() *durationpb.Duration {
+	if x != nil {
+		return x.LeaseDuration
+	}
+	return nil
+}
+
+func (x *ClaimTaskRequest) GetQueues() []string {
+	if x != nil {
+		return x.Queues
+	}
+	return nil
+}
+
+type ClaimTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset when no task can be claimed.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimTaskResponse) Reset() {
+	*x = ClaimTaskResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimTaskResponse) ProtoMessage() {}
+
+func (x *ClaimTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimTaskResponse.ProtoReflect.Descriptor instead.
+func (*ClaimTaskResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ClaimTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type HeartbeatTaskRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TaskId   string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	LeaseId  string                 `protobuf:"bytes,3,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	// Extends the lease to now plus this; defaults as in ClaimTaskRequest.
+	LeaseDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=lease_duration,json=leaseDuration,proto3" json:"lease_duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatTaskRequest) Reset() {
+	*x = HeartbeatTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatTaskRequest) ProtoMessage() {}
+
+func (x *HeartbeatTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatTaskRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *HeartbeatTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *HeartbeatTaskRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *HeartbeatTaskRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *HeartbeatTaskRequest) GetLeaseDuration() *durationpb.Duration {
+	if x != nil {
+		return x.LeaseDuration
+	}
+	return nil
+}
+
+type CompleteTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	LeaseId       string                 `protobuf:"bytes,3,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	Result        *structpb.Struct       `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteTaskRequest) Reset() {
+	*x = CompleteTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteTaskRequest) ProtoMessage() {}
+
+func (x *CompleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*CompleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CompleteTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CompleteTaskRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *CompleteTaskRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *CompleteTaskRequest) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type FailTaskRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TaskId   string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	LeaseId  string                 `protobuf:"bytes,3,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	Error    string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// Matched against the task's retryable_errors, e.g. "transient". The
+	// server's own classes, "timeout" and "lease_expired", are rejected.
+	ErrorClass    string `protobuf:"bytes,5,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailTaskRequest) Reset() {
+	*x = FailTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailTaskRequest) ProtoMessage() {}
+
+func (x *FailTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailTaskRequest.ProtoReflect.Descriptor instead.
+func (*FailTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *FailTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *FailTaskRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *FailTaskRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *FailTaskRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FailTaskRequest) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+type WorkerHello struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	// Most tasks assigned to the session at once.
+	Capacity int32 `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// Task types the worker handles, matched against the task's "type"
+	// label. Empty accepts every task.
+	Capabilities []string `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// As in ClaimTaskRequest.
+	LeaseDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=lease_duration,json=leaseDuration,proto3" json:"lease_duration,omitempty"`
+	Queues        []string             `protobuf:"bytes,5,rep,name=queues,proto3" json:"queues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerHello) Reset() {
+	*x = WorkerHello{}
+	mi := &file_task_v1_task_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerHello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerHello) ProtoMessage() {}
+
+func (x *WorkerHello) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerHello.ProtoReflect.Descriptor instead.
+func (*WorkerHello) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *WorkerHello) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *WorkerHello) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *WorkerHello) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *WorkerHello) GetLeaseDuration() *durationpb.Duration {
+	if x != nil {
+		return x.LeaseDuration
+	}
+	return nil
+}
+
+func (x *WorkerHello) GetQueues() []string {
+	if x != nil {
+		return x.Queues
+	}
+	return nil
+}
+
+// Renews the leases of every task assigned to the session.
+type WorkerHeartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerHeartbeat) Reset() {
+	*x = WorkerHeartbeat{}
+	mi := &file_task_v1_task_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerHeartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerHeartbeat) ProtoMessage() {}
+
+func (x *WorkerHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerHeartbeat.ProtoReflect.Descriptor instead.
+func (*WorkerHeartbeat) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{56}
+}
+
+// As in ReportProgressRequest; also renews the lease.
+type WorkerProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	LeaseId       string                 `protobuf:"bytes,2,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Percent       float64                `protobuf:"fixed64,4,opt,name=percent,proto3" json:"percent,omitempty"`
+	Step          string                 `protobuf:"bytes,5,opt,name=step,proto3" json:"step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerProgress) Reset() {
+	*x = WorkerProgress{}
+	mi := &file_task_v1_task_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerProgress) ProtoMessage() {}
+
+func (x *WorkerProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerProgress.ProtoReflect.Descriptor instead.
+func (*WorkerProgress) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *WorkerProgress) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *WorkerProgress) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *WorkerProgress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *WorkerProgress) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *WorkerProgress) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+type WorkerResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	TaskId  string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	LeaseId string                 `protobuf:"bytes,2,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*WorkerResult_Result
+	//	*WorkerResult_Error
+	Outcome isWorkerResult_Outcome `protobuf_oneof:"outcome"`
+	// As in FailTaskRequest.
+	ErrorClass    string `protobuf:"bytes,5,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerResult) Reset() {
+	*x = WorkerResult{}
+	mi := &file_task_v1_task_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerResult) ProtoMessage() {}
+
+func (x *WorkerResult) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerResult.ProtoReflect.Descriptor instead.
+func (*WorkerResult) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *WorkerResult) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *WorkerResult) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *WorkerResult) GetOutcome() isWorkerResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *WorkerResult) GetResult() *structpb.Struct {
+	if x != nil {
+		if x, ok := x.Outcome.(*WorkerResult_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *WorkerResult) GetError() string {
+	if x != nil {
+		if x, ok := x.Outcome.(*WorkerResult_Error); ok {
+			return x.Error
+		}
+	}
+	return ""
+}
+
+func (x *WorkerResult) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+type isWorkerResult_Outcome interface {
+	isWorkerResult_Outcome()
+}
+
+type WorkerResult_Result struct {
+	Result *structpb.Struct `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+}
+
+type WorkerResult_Error struct {
+	Error string `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*WorkerResult_Result) isWorkerResult_Outcome() {}
+
+func (*WorkerResult_Error) isWorkerResult_Outcome() {}
+
+type WorkerMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The first message of a session must be hello.
+	//
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*WorkerMessage_Hello
+	//	*WorkerMessage_Heartbeat
+	//	*WorkerMessage_Progress
+	//	*WorkerMessage_Result
+	Msg           isWorkerMessage_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerMessage) Reset() {
+	*x = WorkerMessage{}
+	mi := &file_task_v1_task_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerMessage) ProtoMessage() {}
+
+func (x *WorkerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerMessage.ProtoReflect.Descriptor instead.
+func (*WorkerMessage) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *WorkerMessage) GetMsg() isWorkerMessage_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *WorkerMessage) GetHello() *WorkerHello {
+	if x != nil {
+		if x, ok := x.Msg.(*WorkerMessage_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *WorkerMessage) GetHeartbeat() *WorkerHeartbeat {
+	if x != nil {
+		if x, ok := x.Msg.(*WorkerMessage_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *WorkerMessage) GetProgress() *WorkerProgress {
+	if x != nil {
+		if x, ok := x.Msg.(*WorkerMessage_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *WorkerMessage) GetResult() *WorkerResult {
+	if x != nil {
+		if x, ok := x.Msg.(*WorkerMessage_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isWorkerMessage_Msg interface {
+	isWorkerMessage_Msg()
+}
+
+type WorkerMessage_Hello struct {
+	Hello *WorkerHello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"`
+}
+
+type WorkerMessage_Heartbeat struct {
+	Heartbeat *WorkerHeartbeat `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+type WorkerMessage_Progress struct {
+	Progress *WorkerProgress `protobuf:"bytes,3,opt,name=progress,proto3,oneof"`
+}
+
+type WorkerMessage_Result struct {
+	Result *WorkerResult `protobuf:"bytes,4,opt,name=result,proto3,oneof"`
+}
+
+func (*WorkerMessage_Hello) isWorkerMessage_Msg() {}
+
+func (*WorkerMessage_Heartbeat) isWorkerMessage_Msg() {}
+
+func (*WorkerMessage_Progress) isWorkerMessage_Msg() {}
+
+func (*WorkerMessage_Result) isWorkerMessage_Msg() {}
+
+type TaskAssignment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// RUNNING, with the lease to quote in results.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskAssignment) Reset() {
+	*x = TaskAssignment{}
+	mi := &file_task_v1_task_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskAssignment) ProtoMessage() {}
+
+func (x *TaskAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskAssignment.ProtoReflect.Descriptor instead.
+func (*TaskAssignment) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *TaskAssignment) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// The worker no longer holds the task and must stop working on it.
+type TaskRevoked struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskRevoked) Reset() {
+	*x = TaskRevoked{}
+	mi := &file_task_v1_task_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskRevoked) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRevoked) ProtoMessage() {}
+
+func (x *TaskRevoked) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRevoked.ProtoReflect.Descriptor instead.
+func (*TaskRevoked) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *TaskRevoked) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskRevoked) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DispatchMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*DispatchMessage_Assignment
+	//	*DispatchMessage_Revoked
+	Msg           isDispatchMessage_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DispatchMessage) Reset() {
+	*x = DispatchMessage{}
+	mi := &file_task_v1_task_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DispatchMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DispatchMessage) ProtoMessage() {}
+
+func (x *DispatchMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DispatchMessage.ProtoReflect.Descriptor instead.
+func (*DispatchMessage) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DispatchMessage) GetMsg() isDispatchMessage_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *DispatchMessage) GetAssignment() *TaskAssignment {
+	if x != nil {
+		if x, ok := x.Msg.(*DispatchMessage_Assignment); ok {
+			return x.Assignment
+		}
+	}
+	return nil
+}
+
+func (x *DispatchMessage) GetRevoked() *TaskRevoked {
+	if x != nil {
+		if x, ok := x.Msg.(*DispatchMessage_Revoked); ok {
+			return x.Revoked
+		}
+	}
+	return nil
+}
+
+type isDispatchMessage_Msg interface {
+	isDispatchMessage_Msg()
+}
+
+type DispatchMessage_Assignment struct {
+	Assignment *TaskAssignment `protobuf:"bytes,1,opt,name=assignment,proto3,oneof"`
+}
+
+type DispatchMessage_Revoked struct {
+	Revoked *TaskRevoked `protobuf:"bytes,2,opt,name=revoked,proto3,oneof"`
+}
+
+func (*DispatchMessage_Assignment) isDispatchMessage_Msg() {}
+
+func (*DispatchMessage_Revoked) isDispatchMessage_Msg() {}
+
+type Queue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Most tasks of the queue RUNNING at once; 0 means no limit.
+	MaxConcurrency int32                  `protobuf:"varint,2,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Current counts, filled in by ListQueues.
+	Pending       int32 `protobuf:"varint,4,opt,name=pending,proto3" json:"pending,omitempty"`
+	Running       int32 `protobuf:"varint,5,opt,name=running,proto3" json:"running,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Queue) Reset() {
+	*x = Queue{}
+	mi := &file_task_v1_task_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Queue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Queue) ProtoMessage() {}
+
+func (x *Queue) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Queue.ProtoReflect.Descriptor instead.
+func (*Queue) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *Queue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Queue) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *Queue) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Queue) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *Queue) GetRunning() int32 {
+	if x != nil {
+		return x.Running
+	}
+	return 0
+}
+
+type CreateQueueRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MaxConcurrency int32                  `protobuf:"varint,2,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateQueueRequest) Reset() {
+	*x = CreateQueueRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQueueRequest) ProtoMessage() {}
+
+func (x *CreateQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQueueRequest.ProtoReflect.Descriptor instead.
+func (*CreateQueueRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *CreateQueueRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateQueueRequest) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+type ListQueuesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueuesRequest) Reset() {
+	*x = ListQueuesRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueuesRequest) ProtoMessage() {}
+
+func (x *ListQueuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueuesRequest.ProtoReflect.Descriptor instead.
+func (*ListQueuesRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{65}
+}
+
+type ListQueuesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queues        []*Queue               `protobuf:"bytes,1,rep,name=queues,proto3" json:"queues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueuesResponse) Reset() {
+	*x = ListQueuesResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueuesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueuesResponse) ProtoMessage() {}
+
+func (x *ListQueuesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueuesResponse.ProtoReflect.Descriptor instead.
+func (*ListQueuesResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListQueuesResponse) GetQueues() []*Queue {
+	if x != nil {
+		return x.Queues
+	}
+	return nil
+}
+
+type UpdateQueueRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MaxConcurrency int32                  `protobuf:"varint,2,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateQueueRequest) Reset() {
+	*x = UpdateQueueRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQueueRequest) ProtoMessage() {}
+
+func (x *UpdateQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQueueRequest.ProtoReflect.Descriptor instead.
+func (*UpdateQueueRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *UpdateQueueRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateQueueRequest) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+type Schedule struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	// Five fields, "minute hour day-of-month month day-of-week", or a macro
+	// such as @daily.
+	Cron string `protobuf:"bytes,2,opt,name=cron,proto3" json:"cron,omitempty"`
+	// IANA name such as "Europe/Berlin" the cron expression is read in;
+	// defaults to UTC.
+	TimeZone string `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Every run creates a task from this, labelled schedule_id=<id>.
+	// run_at must be unset.
+	Template      *CreateTaskRequest `protobuf:"bytes,4,opt,name=template,proto3" json:"template,omitempty"`
+	OverlapPolicy OverlapPolicy      `protobuf:"varint,5,opt,name=overlap_policy,json=overlapPolicy,proto3,enum=task.v1.OverlapPolicy" json:"overlap_policy,omitempty"`
+	// When runs were missed, e.g. while the server was down, create a task
+	// for each of them (at most 100) instead of only for the latest.
+	CatchUp   bool                   `protobuf:"varint,6,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
+	Paused    bool                   `protobuf:"varint,7,opt,name=paused,proto3" json:"paused,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CreatedBy string                 `protobuf:"bytes,9,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	// Unset while paused.
+	NextRunAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	LastRunAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	// Task created by the latest run that was not skipped.
+	LastTaskId string `protobuf:"bytes,12,opt,name=last_task_id,json=lastTaskId,proto3" json:"last_task_id,omitempty"`
+	// Runs held by OVERLAP_POLICY_QUEUE until last_task_id finishes, at
+	// most 100.
+	QueuedRuns    int32 `protobuf:"varint,13,opt,name=queued_runs,json=queuedRuns,proto3" json:"queued_runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_task_v1_task_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *Schedule) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *Schedule) GetCron() string {
+	if x != nil {
+		return x.Cron
+	}
+	return ""
+}
+
+func (x *Schedule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Schedule) GetTemplate() *CreateTaskRequest {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *Schedule) GetOverlapPolicy() OverlapPolicy {
+	if x != nil {
+		return x.OverlapPolicy
+	}
+	return OverlapPolicy_OVERLAP_POLICY_UNSPECIFIED
+}
+
+func (x *Schedule) GetCatchUp() bool {
+	if x != nil {
+		return x.CatchUp
+	}
+	return false
+}
+
+func (x *Schedule) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Schedule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Schedule) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Schedule) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+func (x *Schedule) GetLastRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return nil
+}
+
+func (x *Schedule) GetLastTaskId() string {
+	if x != nil {
+		return x.LastTaskId
+	}
+	return ""
+}
+
+func (x *Schedule) GetQueuedRuns() int32 {
+	if x != nil {
+		return x.QueuedRuns
+	}
+	return 0
+}
+
+type CreateScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cron          string                 `protobuf:"bytes,1,opt,name=cron,proto3" json:"cron,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,2,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	Template      *CreateTaskRequest     `protobuf:"bytes,3,opt,name=template,proto3" json:"template,omitempty"`
+	OverlapPolicy OverlapPolicy          `protobuf:"varint,4,opt,name=overlap_policy,json=overlapPolicy,proto3,enum=task.v1.OverlapPolicy" json:"overlap_policy,omitempty"`
+	CatchUp       bool                   `protobuf:"varint,5,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
+	Paused        bool                   `protobuf:"varint,6,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateScheduleRequest) Reset() {
+	*x = CreateScheduleRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScheduleRequest) ProtoMessage() {}
+
+func (x *CreateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *CreateScheduleRequest) GetCron() string {
+	if x != nil {
+		return x.Cron
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetTemplate() *CreateTaskRequest {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *CreateScheduleRequest) GetOverlapPolicy() OverlapPolicy {
+	if x != nil {
+		return x.OverlapPolicy
+	}
+	return OverlapPolicy_OVERLAP_POLICY_UNSPECIFIED
+}
+
+func (x *CreateScheduleRequest) GetCatchUp() bool {
+	if x != nil {
+		return x.CatchUp
+	}
+	return false
+}
+
+func (x *CreateScheduleRequest) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetScheduleRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+type ListSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesRequest) Reset() {
+	*x = ListSchedulesRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesRequest) ProtoMessage() {}
+
+func (x *ListSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{71}
+}
+
+type ListSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*Schedule            `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesResponse) Reset() {
+	*x = ListSchedulesResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesResponse) ProtoMessage() {}
+
+func (x *ListSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ListSchedulesResponse) GetSchedules() []*Schedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+// Replaces every field of the schedule. The next run is computed afresh, so
+// runs missed while paused are not caught up.
+type UpdateScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Cron          string                 `protobuf:"bytes,2,opt,name=cron,proto3" json:"cron,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	Template      *CreateTaskRequest     `protobuf:"bytes,4,opt,name=template,proto3" json:"template,omitempty"`
+	OverlapPolicy OverlapPolicy          `protobuf:"varint,5,opt,name=overlap_policy,json=overlapPolicy,proto3,enum=task.v1.OverlapPolicy" json:"overlap_policy,omitempty"`
+	CatchUp       bool                   `protobuf:"varint,6,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
+	Paused        bool                   `protobuf:"varint,7,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateScheduleRequest) Reset() {
+	*x = UpdateScheduleRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateScheduleRequest) ProtoMessage() {}
+
+func (x *UpdateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *UpdateScheduleRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *UpdateScheduleRequest) GetCron() string {
+	if x != nil {
+		return x.Cron
+	}
+	return ""
+}
+
+func (x *UpdateScheduleRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *UpdateScheduleRequest) GetTemplate() *CreateTaskRequest {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *UpdateScheduleRequest) GetOverlapPolicy() OverlapPolicy {
+	if x != nil {
+		return x.OverlapPolicy
+	}
+	return OverlapPolicy_OVERLAP_POLICY_UNSPECIFIED
+}
+
+func (x *UpdateScheduleRequest) GetCatchUp() bool {
+	if x != nil {
+		return x.CatchUp
+	}
+	return false
+}
+
+func (x *UpdateScheduleRequest) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type DeleteScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteScheduleRequest) Reset() {
+	*x = DeleteScheduleRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteScheduleRequest) ProtoMessage() {}
+
+func (x *DeleteScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *DeleteScheduleRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+type DeleteScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteScheduleResponse) Reset() {
+	*x = DeleteScheduleResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteScheduleResponse) ProtoMessage() {}
+
+func (x *DeleteScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteScheduleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{75}
+}
+
+type ListDeadLettersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only tasks of this queue; empty lists all.
+	Queue         string `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ListDeadLettersRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ListDeadLettersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeadLettersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateTaskResponse) Reset() {
-	*x = CreateTaskResponse{}
-	mi := &file_task_v1_task_proto_msgTypes[3]
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateTaskResponse) String() string {
+func (x *ListDeadLettersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateTaskResponse) ProtoMessage() {}
+func (*ListDeadLettersResponse) ProtoMessage() {}
 
-func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[3]
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -308,40 +5547,48 @@ func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
-func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *CreateTaskResponse) GetTask() *Task {
+func (x *ListDeadLettersResponse) GetTasks() []*Task {
 	if x != nil {
-		return x.Task
+		return x.Tasks
 	}
 	return nil
 }
 
-type GetTaskRequest struct {
+func (x *ListDeadLettersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Makes a dead-lettered task PENDING again with a fresh attempt count.
+type RequeueDeadLetterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskRequest) Reset() {
-	*x = GetTaskRequest{}
-	mi := &file_task_v1_task_proto_msgTypes[4]
+func (x *RequeueDeadLetterRequest) Reset() {
+	*x = RequeueDeadLetterRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskRequest) String() string {
+func (x *RequeueDeadLetterRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskRequest) ProtoMessage() {}
+func (*RequeueDeadLetterRequest) ProtoMessage() {}
 
-func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[4]
+func (x *RequeueDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -352,41 +5599,43 @@ func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
-func (*GetTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use RequeueDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *GetTaskRequest) GetTaskId() string {
+func (x *RequeueDeadLetterRequest) GetTaskId() string {
 	if x != nil {
 		return x.TaskId
 	}
 	return ""
 }
 
-type ListTasksRequest struct {
+// Cancels a task that has not finished. A worker holding its lease loses
+// it; a session is sent TaskRevoked with the reason.
+type CancelTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksRequest) Reset() {
-	*x = ListTasksRequest{}
-	mi := &file_task_v1_task_proto_msgTypes[5]
+func (x *CancelTaskRequest) Reset() {
+	*x = CancelTaskRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksRequest) String() string {
+func (x *CancelTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksRequest) ProtoMessage() {}
+func (*CancelTaskRequest) ProtoMessage() {}
 
-func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[5]
+func (x *CancelTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -397,48 +5646,55 @@ func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
-func (*ListTasksRequest) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use CancelTaskRequest.ProtoReflect.Descriptor instead.
+func (*CancelTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *ListTasksRequest) GetPageSize() int32 {
+func (x *CancelTaskRequest) GetTaskId() string {
 	if x != nil {
-		return x.PageSize
+		return x.TaskId
 	}
-	return 0
+	return ""
 }
 
-func (x *ListTasksRequest) GetPageToken() string {
+func (x *CancelTaskRequest) GetReason() string {
 	if x != nil {
-		return x.PageToken
+		return x.Reason
 	}
 	return ""
 }
 
-type ListTasksResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+type ReportProgressRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TaskId   string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	LeaseId  string                 `protobuf:"bytes,3,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	// From 0 to 100.
+	Percent float64 `protobuf:"fixed64,4,opt,name=percent,proto3" json:"percent,omitempty"`
+	// At most 128 bytes.
+	Step string `protobuf:"bytes,5,opt,name=step,proto3" json:"step,omitempty"`
+	// At most 1024 bytes.
+	Message       string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksResponse) Reset() {
-	*x = ListTasksResponse{}
-	mi := &file_task_v1_task_proto_msgTypes[6]
+func (x *ReportProgressRequest) Reset() {
+	*x = ReportProgressRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksResponse) String() string {
+func (x *ReportProgressRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksResponse) ProtoMessage() {}
+func (*ReportProgressRequest) ProtoMessage() {}
 
-func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[6]
+func (x *ReportProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -449,47 +5705,82 @@ func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
-func (*ListTasksResponse) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ReportProgressRequest.ProtoReflect.Descriptor instead.
+func (*ReportProgressRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *ListTasksResponse) GetTasks() []*Task {
+func (x *ReportProgressRequest) GetTaskId() string {
 	if x != nil {
-		return x.Tasks
+		return x.TaskId
 	}
-	return nil
+	return ""
 }
 
-func (x *ListTasksResponse) GetNextPageToken() string {
+func (x *ReportProgressRequest) GetWorkerId() string {
 	if x != nil {
-		return x.NextPageToken
+		return x.WorkerId
 	}
 	return ""
 }
 
-type WatchTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+func (x *ReportProgressRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *ReportProgressRequest) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *ReportProgressRequest) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *ReportProgressRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// A line of a task's output.
+type TaskLogLine struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Numbers the task's lines from 1, across attempts.
+	Sequence int64                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	At       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"`
+	// Attempt that wrote the line.
+	Attempt       int32  `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Text          string `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchTaskRequest) Reset() {
-	*x = WatchTaskRequest{}
-	mi := &file_task_v1_task_proto_msgTypes[7]
+func (x *TaskLogLine) Reset() {
+	*x = TaskLogLine{}
+	mi := &file_task_v1_task_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchTaskRequest) String() string {
+func (x *TaskLogLine) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchTaskRequest) ProtoMessage() {}
+func (*TaskLogLine) ProtoMessage() {}
 
-func (x *WatchTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[7]
+func (x *TaskLogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -500,42 +5791,74 @@ func (x *WatchTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchTaskRequest.ProtoReflect.Descriptor instead.
-func (*WatchTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use TaskLogLine.ProtoReflect.Descriptor instead.
+func (*TaskLogLine) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *WatchTaskRequest) GetTaskId() string {
+func (x *TaskLogLine) GetTaskId() string {
 	if x != nil {
 		return x.TaskId
 	}
 	return ""
 }
 
-type TaskEvent struct {
+func (x *TaskLogLine) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TaskLogLine) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *TaskLogLine) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *TaskLogLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Sent by the worker holding the task's lease. task_id, worker_id and
+// lease_id are required in the first message; later messages may leave them
+// empty. Lines longer than 8 KiB are truncated.
+type AppendTaskLogsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        TaskStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=task.v1.TaskStatus" json:"status,omitempty"`
-	At            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	LeaseId       string                 `protobuf:"bytes,3,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	Lines         []string               `protobuf:"bytes,4,rep,name=lines,proto3" json:"lines,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskEvent) Reset() {
-	*x = TaskEvent{}
-	mi := &file_task_v1_task_proto_msgTypes[8]
+func (x *AppendTaskLogsRequest) Reset() {
+	*x = AppendTaskLogsRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskEvent) String() string {
+func (x *AppendTaskLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskEvent) ProtoMessage() {}
+func (*AppendTaskLogsRequest) ProtoMessage() {}
 
-func (x *TaskEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[8]
+func (x *AppendTaskLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -546,55 +5869,64 @@ func (x *TaskEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
-func (*TaskEvent) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use AppendTaskLogsRequest.ProtoReflect.Descriptor instead.
+func (*AppendTaskLogsRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *TaskEvent) GetStatus() TaskStatus {
+func (x *AppendTaskLogsRequest) GetTaskId() string {
 	if x != nil {
-		return x.Status
+		return x.TaskId
 	}
-	return TaskStatus_TASK_STATUS_UNSPECIFIED
+	return ""
 }
 
-func (x *TaskEvent) GetAt() *timestamppb.Timestamp {
+func (x *AppendTaskLogsRequest) GetWorkerId() string {
 	if x != nil {
-		return x.At
+		return x.WorkerId
 	}
-	return nil
+	return ""
 }
 
-func (x *TaskEvent) GetMessage() string {
+func (x *AppendTaskLogsRequest) GetLeaseId() string {
 	if x != nil {
-		return x.Message
+		return x.LeaseId
 	}
 	return ""
 }
 
-type BulkCreateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CreatedCount  int32                  `protobuf:"varint,1,opt,name=created_count,json=createdCount,proto3" json:"created_count,omitempty"`
-	TaskIds       []string               `protobuf:"bytes,2,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`
+func (x *AppendTaskLogsRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type AppendTaskLogsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Lines stored by this call.
+	LinesAppended int64 `protobuf:"varint,1,opt,name=lines_appended,json=linesAppended,proto3" json:"lines_appended,omitempty"`
+	// Sequence of the task's newest line.
+	LastSequence  int64 `protobuf:"varint,2,opt,name=last_sequence,json=lastSequence,proto3" json:"last_sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BulkCreateResponse) Reset() {
-	*x = BulkCreateResponse{}
-	mi := &file_task_v1_task_proto_msgTypes[9]
+func (x *AppendTaskLogsResponse) Reset() {
+	*x = AppendTaskLogsResponse{}
+	mi := &file_task_v1_task_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BulkCreateResponse) String() string {
+func (x *AppendTaskLogsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BulkCreateResponse) ProtoMessage() {}
+func (*AppendTaskLogsResponse) ProtoMessage() {}
 
-func (x *BulkCreateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[9]
+func (x *AppendTaskLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -605,47 +5937,54 @@ func (x *BulkCreateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BulkCreateResponse.ProtoReflect.Descriptor instead.
-func (*BulkCreateResponse) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use AppendTaskLogsResponse.ProtoReflect.Descriptor instead.
+func (*AppendTaskLogsResponse) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *BulkCreateResponse) GetCreatedCount() int32 {
+func (x *AppendTaskLogsResponse) GetLinesAppended() int64 {
 	if x != nil {
-		return x.CreatedCount
+		return x.LinesAppended
 	}
 	return 0
 }
 
-func (x *BulkCreateResponse) GetTaskIds() []string {
+func (x *AppendTaskLogsResponse) GetLastSequence() int64 {
 	if x != nil {
-		return x.TaskIds
+		return x.LastSequence
 	}
-	return nil
+	return 0
 }
 
-type ConsoleMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+// Only the newest lines of a task are retained; older ones are dropped a
+// segment at a time.
+type TailTaskLogsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Start with the last tail_lines lines; 0 starts from the oldest
+	// retained line.
+	TailLines int32 `protobuf:"varint,2,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"`
+	// Keep sending new lines until the task reaches a terminal status.
+	Follow        bool `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConsoleMessage) Reset() {
-	*x = ConsoleMessage{}
-	mi := &file_task_v1_task_proto_msgTypes[10]
+func (x *TailTaskLogsRequest) Reset() {
+	*x = TailTaskLogsRequest{}
+	mi := &file_task_v1_task_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConsoleMessage) String() string {
+func (x *TailTaskLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConsoleMessage) ProtoMessage() {}
+func (*TailTaskLogsRequest) ProtoMessage() {}
 
-func (x *ConsoleMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_task_v1_task_proto_msgTypes[10]
+func (x *TailTaskLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_v1_task_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -656,23 +5995,37 @@ func (x *ConsoleMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConsoleMessage.ProtoReflect.Descriptor instead.
-func (*ConsoleMessage) Descriptor() ([]byte, []int) {
-	return file_task_v1_task_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use TailTaskLogsRequest.ProtoReflect.Descriptor instead.
+func (*TailTaskLogsRequest) Descriptor() ([]byte, []int) {
+	return file_task_v1_task_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *ConsoleMessage) GetText() string {
+func (x *TailTaskLogsRequest) GetTaskId() string {
 	if x != nil {
-		return x.Text
+		return x.TaskId
 	}
 	return ""
 }
 
+func (x *TailTaskLogsRequest) GetTailLines() int32 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+func (x *TailTaskLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
 var File_task_v1_task_proto protoreflect.FileDescriptor
 
 const file_task_v1_task_proto_rawDesc = "" +
 	"\n" +
-	"\x12task/v1/task.proto\x12\atask.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfa\x01\n" +
+	"\x12task/v1/task.proto\x12\atask.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9f\a\n" +
 	"\x04Task\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -681,14 +6034,75 @@ const file_task_v1_task_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"K\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12-\n" +
+	"\x05input\x18\a \x01(\v2\x17.google.protobuf.StructR\x05input\x12/\n" +
+	"\x06result\x18\b \x01(\v2\x17.google.protobuf.StructR\x06result\x121\n" +
+	"\x06labels\x18\t \x03(\v2\x19.task.v1.Task.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\tparent_id\x18\n" +
+	" \x01(\tR\bparentId\x12$\n" +
+	"\x05lease\x18\v \x01(\v2\x0e.task.v1.LeaseR\x05lease\x12\x14\n" +
+	"\x05error\x18\f \x01(\tR\x05error\x12\x14\n" +
+	"\x05queue\x18\r \x01(\tR\x05queue\x121\n" +
+	"\x06run_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x05runAt\x127\n" +
+	"\fretry_policy\x18\x0f \x01(\v2\x14.task.v1.RetryPolicyR\vretryPolicy\x12\x18\n" +
+	"\aattempt\x18\x10 \x01(\x05R\aattempt\x12\x1f\n" +
+	"\verror_class\x18\x11 \x01(\tR\n" +
+	"errorClass\x12D\n" +
+	"\x10dead_lettered_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\x0edeadLetteredAt\x12F\n" +
+	"\x11execution_timeout\x18\x13 \x01(\v2\x19.google.protobuf.DurationR\x10executionTimeout\x121\n" +
+	"\bprogress\x18\x14 \x01(\v2\x15.task.v1.TaskProgressR\bprogress\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x91\x01\n" +
+	"\fTaskProgress\x12\x18\n" +
+	"\apercent\x18\x01 \x01(\x01R\apercent\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\tR\x04step\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa2\x02\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12B\n" +
+	"\x0finitial_backoff\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x0einitialBackoff\x12-\n" +
+	"\x12backoff_multiplier\x18\x03 \x01(\x01R\x11backoffMultiplier\x12:\n" +
+	"\vmax_backoff\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxBackoff\x12\x16\n" +
+	"\x06jitter\x18\x05 \x01(\x01R\x06jitter\x12)\n" +
+	"\x10retryable_errors\x18\x06 \x03(\tR\x0fretryableErrors\"\xb5\x01\n" +
+	"\x05Lease\x12\x19\n" +
+	"\blease_id\x18\x01 \x01(\tR\aleaseId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x129\n" +
+	"\n" +
+	"claimed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tclaimedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xdc\x03\n" +
 	"\x11CreateTaskRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"j\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12-\n" +
+	"\x05input\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x05input\x12>\n" +
+	"\x06labels\x18\x04 \x03(\v2&.task.v1.CreateTaskRequest.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\tparent_id\x18\x05 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05queue\x18\x06 \x01(\tR\x05queue\x121\n" +
+	"\x06run_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x05runAt\x127\n" +
+	"\fretry_policy\x18\b \x01(\v2\x14.task.v1.RetryPolicyR\vretryPolicy\x12F\n" +
+	"\x11execution_timeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\x10executionTimeout\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x81\x04\n" +
 	"\x17CreateTaskWithIdRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"7\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12-\n" +
+	"\x05input\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x05input\x12D\n" +
+	"\x06labels\x18\x05 \x03(\v2,.task.v1.CreateTaskWithIdRequest.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\tparent_id\x18\x06 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05queue\x18\a \x01(\tR\x05queue\x121\n" +
+	"\x06run_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x05runAt\x127\n" +
+	"\fretry_policy\x18\t \x01(\v2\x14.task.v1.RetryPolicyR\vretryPolicy\x12F\n" +
+	"\x11execution_timeout\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\x10executionTimeout\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"7\n" +
 	"\x12CreateTaskResponse\x12!\n" +
 	"\x04task\x18\x01 \x01(\v2\r.task.v1.TaskR\x04task\")\n" +
 	"\x0eGetTaskRequest\x12\x17\n" +
@@ -699,18 +6113,393 @@ const file_task_v1_task_proto_rawDesc = "" +
 	"page_token\x18\x02 \x01(\tR\tpageToken\"`\n" +
 	"\x11ListTasksResponse\x12#\n" +
 	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"+\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x9c\x01\n" +
 	"\x10WatchTaskRequest\x12\x17\n" +
-	"\atask_id\x18\x01 \x01(\tR\x06taskId\"~\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12%\n" +
+	"\x0esince_revision\x18\x02 \x01(\x03R\rsinceRevision\x12H\n" +
+	"\x12heartbeat_interval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x11heartbeatInterval\"\x9d\x03\n" +
 	"\tTaskEvent\x12+\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x13.task.v1.TaskStatusR\x06status\x12*\n" +
 	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"T\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x17\n" +
+	"\atask_id\x18\x04 \x01(\tR\x06taskId\x12*\n" +
+	"\x04type\x18\x05 \x01(\x0e2\x16.task.v1.TaskEventTypeR\x04type\x12*\n" +
+	"\acomment\x18\x06 \x01(\v2\x10.task.v1.CommentR\acomment\x12!\n" +
+	"\x04task\x18\a \x01(\v2\r.task.v1.TaskR\x04task\x12<\n" +
+	"\x0fprevious_status\x18\b \x01(\x0e2\x13.task.v1.TaskStatusR\x0epreviousStatus\x12\x1a\n" +
+	"\brevision\x18\t \x01(\x03R\brevision\x12\x19\n" +
+	"\bevent_id\x18\n" +
+	" \x01(\tR\aeventId\x12\x14\n" +
+	"\x05actor\x18\v \x01(\tR\x05actor\"\xe4\x01\n" +
+	"\n" +
+	"TaskFilter\x12/\n" +
+	"\bstatuses\x18\x01 \x03(\x0e2\x13.task.v1.TaskStatusR\bstatuses\x127\n" +
+	"\x06labels\x18\x02 \x03(\v2\x1f.task.v1.TaskFilter.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\tparent_id\x18\x03 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05queue\x18\x04 \x01(\tR\x05queue\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe5\x01\n" +
+	"\x11WatchTasksRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.task.v1.TaskFilterR\x06filter\x122\n" +
+	"\x15send_initial_snapshot\x18\x02 \x01(\bR\x13sendInitialSnapshot\x12%\n" +
+	"\x0esince_revision\x18\x03 \x01(\x03R\rsinceRevision\x12H\n" +
+	"\x12heartbeat_interval\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x11heartbeatInterval\"T\n" +
 	"\x12BulkCreateResponse\x12#\n" +
 	"\rcreated_count\x18\x01 \x01(\x05R\fcreatedCount\x12\x19\n" +
 	"\btask_ids\x18\x02 \x03(\tR\ataskIds\"$\n" +
 	"\x0eConsoleMessage\x12\x12\n" +
-	"\x04text\x18\x01 \x01(\tR\x04text*\xa8\x01\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"\xe3\x01\n" +
+	"\aComment\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x01 \x01(\tR\tcommentId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"@\n" +
+	"\x11AddCommentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\"j\n" +
+	"\x13ListCommentsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"l\n" +
+	"\x14ListCommentsResponse\x12,\n" +
+	"\bcomments\x18\x01 \x03(\v2\x10.task.v1.CommentR\bcomments\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"`\n" +
+	"\x12EditCommentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\"N\n" +
+	"\x14DeleteCommentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\"\x17\n" +
+	"\x15DeleteCommentResponse\"`\n" +
+	"\x14SetTaskResultRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12/\n" +
+	"\x06result\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06result\"f\n" +
+	"\x12SearchTasksRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"a\n" +
+	"\fSearchResult\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.task.v1.TaskR\x04task\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\"n\n" +
+	"\x13SearchTasksResponse\x12/\n" +
+	"\aresults\x18\x01 \x03(\v2\x15.task.v1.SearchResultR\aresults\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x15\n" +
+	"\x13GetTaskStatsRequest\"\xdd\x01\n" +
+	"\x10ThroughputWindow\x121\n" +
+	"\x06window\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x03R\acreated\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\x03R\tcompleted\x12,\n" +
+	"\x12created_per_minute\x18\x04 \x01(\x01R\x10createdPerMinute\x120\n" +
+	"\x14completed_per_minute\x18\x05 \x01(\x01R\x12completedPerMinute\"\xb7\x01\n" +
+	"\x0eStatusDuration\x12+\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x13.task.v1.TaskStatusR\x06status\x12\x18\n" +
+	"\asamples\x18\x02 \x01(\x03R\asamples\x121\n" +
+	"\x06median\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06median\x12+\n" +
+	"\x03p95\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x03p95\"\x81\x04\n" +
+	"\tTaskStats\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12=\n" +
+	"\tby_status\x18\x02 \x03(\v2 .task.v1.TaskStats.ByStatusEntryR\bbyStatus\x12:\n" +
+	"\bby_label\x18\x03 \x03(\v2\x1f.task.v1.TaskStats.ByLabelEntryR\abyLabel\x129\n" +
+	"\n" +
+	"throughput\x18\x04 \x03(\v2\x19.task.v1.ThroughputWindowR\n" +
+	"throughput\x12=\n" +
+	"\x0etime_in_status\x18\x05 \x03(\v2\x17.task.v1.StatusDurationR\ftimeInStatus\x12>\n" +
+	"\rwatch_streams\x18\x06 \x03(\v2\x19.task.v1.WatchStreamStatsR\fwatchStreams\x120\n" +
+	"\x14dropped_events_total\x18\a \x01(\x03R\x12droppedEventsTotal\x1a;\n" +
+	"\rByStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\x1a:\n" +
+	"\fByLabelEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"p\n" +
+	"\x10WatchStreamStats\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\x03R\x06queued\x12\x18\n" +
+	"\adropped\x18\x04 \x01(\x03R\adropped\"\x92\x02\n" +
+	"\aWebhook\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x127\n" +
+	"\vevent_types\x18\x03 \x03(\x0e2\x16.task.v1.TaskEventTypeR\n" +
+	"eventTypes\x12+\n" +
+	"\x06filter\x18\x04 \x01(\v2\x13.task.v1.TaskFilterR\x06filter\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\tR\x06secret\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\"\xa6\x01\n" +
+	"\x14CreateWebhookRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x127\n" +
+	"\vevent_types\x18\x02 \x03(\x0e2\x16.task.v1.TaskEventTypeR\n" +
+	"eventTypes\x12+\n" +
+	"\x06filter\x18\x03 \x01(\v2\x13.task.v1.TaskFilterR\x06filter\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\"\x15\n" +
+	"\x13ListWebhooksRequest\"D\n" +
+	"\x14ListWebhooksResponse\x12,\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x10.task.v1.WebhookR\bwebhooks\"5\n" +
+	"\x14DeleteWebhookRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\"\x17\n" +
+	"\x15DeleteWebhookResponse\"\xa8\x03\n" +
+	"\x0fWebhookDelivery\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12(\n" +
+	"\x05event\x18\x03 \x01(\v2\x12.task.v1.TaskEventR\x05event\x123\n" +
+	"\x05state\x18\x04 \x01(\x0e2\x1d.task.v1.WebhookDeliveryStateR\x05state\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12(\n" +
+	"\x10last_status_code\x18\x06 \x01(\x05R\x0elastStatusCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1b\n" +
+	"\treplay_of\x18\n" +
+	" \x01(\tR\breplayOf\"z\n" +
+	"\x0eWebhookPayload\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12(\n" +
+	"\x05event\x18\x03 \x01(\v2\x12.task.v1.TaskEventR\x05event\"y\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\x81\x01\n" +
+	"\x1dListWebhookDeliveriesResponse\x128\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x18.task.v1.WebhookDeliveryR\n" +
+	"deliveries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"?\n" +
+	"\x1cReplayWebhookDeliveryRequest\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\"\xa3\x01\n" +
+	"\x12AttachmentMetadata\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\"w\n" +
+	"\x17UploadAttachmentRequest\x129\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1b.task.v1.AttachmentMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"\x9c\x02\n" +
+	"\n" +
+	"Attachment\x12#\n" +
+	"\rattachment_id\x18\x01 \x01(\tR\fattachmentId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x12\x1f\n" +
+	"\vuploaded_by\x18\a \x01(\tR\n" +
+	"uploadedBy\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Y\n" +
+	"\x19DownloadAttachmentRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12#\n" +
+	"\rattachment_id\x18\x02 \x01(\tR\fattachmentId\"v\n" +
+	"\x1aDownloadAttachmentResponse\x125\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x13.task.v1.AttachmentH\x00R\n" +
+	"attachment\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"\xcb\x02\n" +
+	"\x11ListEventsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12+\n" +
+	"\x06filter\x18\x02 \x01(\v2\x13.task.v1.TaskFilterR\x06filter\x12,\n" +
+	"\x05types\x18\x03 \x03(\x0e2\x16.task.v1.TaskEventTypeR\x05types\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\x129\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\"h\n" +
+	"\x12ListEventsResponse\x12*\n" +
+	"\x06events\x18\x01 \x03(\v2\x12.task.v1.TaskEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x89\x01\n" +
+	"\x10ClaimTaskRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12@\n" +
+	"\x0elease_duration\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\rleaseDuration\x12\x16\n" +
+	"\x06queues\x18\x03 \x03(\tR\x06queues\"6\n" +
+	"\x11ClaimTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.task.v1.TaskR\x04task\"\xa9\x01\n" +
+	"\x14HeartbeatTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x19\n" +
+	"\blease_id\x18\x03 \x01(\tR\aleaseId\x12@\n" +
+	"\x0elease_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rleaseDuration\"\x97\x01\n" +
+	"\x13CompleteTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x19\n" +
+	"\blease_id\x18\x03 \x01(\tR\aleaseId\x12/\n" +
+	"\x06result\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06result\"\x99\x01\n" +
+	"\x0fFailTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x19\n" +
+	"\blease_id\x18\x03 \x01(\tR\aleaseId\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1f\n" +
+	"\verror_class\x18\x05 \x01(\tR\n" +
+	"errorClass\"\xc4\x01\n" +
+	"\vWorkerHello\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1a\n" +
+	"\bcapacity\x18\x02 \x01(\x05R\bcapacity\x12\"\n" +
+	"\fcapabilities\x18\x03 \x03(\tR\fcapabilities\x12@\n" +
+	"\x0elease_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rleaseDuration\x12\x16\n" +
+	"\x06queues\x18\x05 \x03(\tR\x06queues\"\x11\n" +
+	"\x0fWorkerHeartbeat\"\x8c\x01\n" +
+	"\x0eWorkerProgress\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x19\n" +
+	"\blease_id\x18\x02 \x01(\tR\aleaseId\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x18\n" +
+	"\apercent\x18\x04 \x01(\x01R\apercent\x12\x12\n" +
+	"\x04step\x18\x05 \x01(\tR\x04step\"\xb9\x01\n" +
+	"\fWorkerResult\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x19\n" +
+	"\blease_id\x18\x02 \x01(\tR\aleaseId\x121\n" +
+	"\x06result\x18\x03 \x01(\v2\x17.google.protobuf.StructH\x00R\x06result\x12\x16\n" +
+	"\x05error\x18\x04 \x01(\tH\x00R\x05error\x12\x1f\n" +
+	"\verror_class\x18\x05 \x01(\tR\n" +
+	"errorClassB\t\n" +
+	"\aoutcome\"\xe6\x01\n" +
+	"\rWorkerMessage\x12,\n" +
+	"\x05hello\x18\x01 \x01(\v2\x14.task.v1.WorkerHelloH\x00R\x05hello\x128\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x18.task.v1.WorkerHeartbeatH\x00R\theartbeat\x125\n" +
+	"\bprogress\x18\x03 \x01(\v2\x17.task.v1.WorkerProgressH\x00R\bprogress\x12/\n" +
+	"\x06result\x18\x04 \x01(\v2\x15.task.v1.WorkerResultH\x00R\x06resultB\x05\n" +
+	"\x03msg\"3\n" +
+	"\x0eTaskAssignment\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.task.v1.TaskR\x04task\">\n" +
+	"\vTaskRevoked\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x85\x01\n" +
+	"\x0fDispatchMessage\x129\n" +
+	"\n" +
+	"assignment\x18\x01 \x01(\v2\x17.task.v1.TaskAssignmentH\x00R\n" +
+	"assignment\x120\n" +
+	"\arevoked\x18\x02 \x01(\v2\x14.task.v1.TaskRevokedH\x00R\arevokedB\x05\n" +
+	"\x03msg\"\xb3\x01\n" +
+	"\x05Queue\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x0fmax_concurrency\x18\x02 \x01(\x05R\x0emaxConcurrency\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x18\n" +
+	"\apending\x18\x04 \x01(\x05R\apending\x12\x18\n" +
+	"\arunning\x18\x05 \x01(\x05R\arunning\"Q\n" +
+	"\x12CreateQueueRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x0fmax_concurrency\x18\x02 \x01(\x05R\x0emaxConcurrency\"\x13\n" +
+	"\x11ListQueuesRequest\"<\n" +
+	"\x12ListQueuesResponse\x12&\n" +
+	"\x06queues\x18\x01 \x03(\v2\x0e.task.v1.QueueR\x06queues\"Q\n" +
+	"\x12UpdateQueueRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x0fmax_concurrency\x18\x02 \x01(\x05R\x0emaxConcurrency\"\x9b\x04\n" +
+	"\bSchedule\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x12\x12\n" +
+	"\x04cron\x18\x02 \x01(\tR\x04cron\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x126\n" +
+	"\btemplate\x18\x04 \x01(\v2\x1a.task.v1.CreateTaskRequestR\btemplate\x12=\n" +
+	"\x0eoverlap_policy\x18\x05 \x01(\x0e2\x16.task.v1.OverlapPolicyR\roverlapPolicy\x12\x19\n" +
+	"\bcatch_up\x18\x06 \x01(\bR\acatchUp\x12\x16\n" +
+	"\x06paused\x18\a \x01(\bR\x06paused\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\t \x01(\tR\tcreatedBy\x12:\n" +
+	"\vnext_run_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\x12:\n" +
+	"\vlast_run_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x12 \n" +
+	"\flast_task_id\x18\f \x01(\tR\n" +
+	"lastTaskId\x12\x1f\n" +
+	"\vqueued_runs\x18\r \x01(\x05R\n" +
+	"queuedRuns\"\xf2\x01\n" +
+	"\x15CreateScheduleRequest\x12\x12\n" +
+	"\x04cron\x18\x01 \x01(\tR\x04cron\x12\x1b\n" +
+	"\ttime_zone\x18\x02 \x01(\tR\btimeZone\x126\n" +
+	"\btemplate\x18\x03 \x01(\v2\x1a.task.v1.CreateTaskRequestR\btemplate\x12=\n" +
+	"\x0eoverlap_policy\x18\x04 \x01(\x0e2\x16.task.v1.OverlapPolicyR\roverlapPolicy\x12\x19\n" +
+	"\bcatch_up\x18\x05 \x01(\bR\acatchUp\x12\x16\n" +
+	"\x06paused\x18\x06 \x01(\bR\x06paused\"5\n" +
+	"\x12GetScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\"\x16\n" +
+	"\x14ListSchedulesRequest\"H\n" +
+	"\x15ListSchedulesResponse\x12/\n" +
+	"\tschedules\x18\x01 \x03(\v2\x11.task.v1.ScheduleR\tschedules\"\x93\x02\n" +
+	"\x15UpdateScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x12\x12\n" +
+	"\x04cron\x18\x02 \x01(\tR\x04cron\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x126\n" +
+	"\btemplate\x18\x04 \x01(\v2\x1a.task.v1.CreateTaskRequestR\btemplate\x12=\n" +
+	"\x0eoverlap_policy\x18\x05 \x01(\x0e2\x16.task.v1.OverlapPolicyR\roverlapPolicy\x12\x19\n" +
+	"\bcatch_up\x18\x06 \x01(\bR\acatchUp\x12\x16\n" +
+	"\x06paused\x18\a \x01(\bR\x06paused\"8\n" +
+	"\x15DeleteScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\"\x18\n" +
+	"\x16DeleteScheduleResponse\"j\n" +
+	"\x16ListDeadLettersRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"f\n" +
+	"\x17ListDeadLettersResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"3\n" +
+	"\x18RequeueDeadLetterRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"D\n" +
+	"\x11CancelTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xb0\x01\n" +
+	"\x15ReportProgressRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x19\n" +
+	"\blease_id\x18\x03 \x01(\tR\aleaseId\x12\x18\n" +
+	"\apercent\x18\x04 \x01(\x01R\apercent\x12\x12\n" +
+	"\x04step\x18\x05 \x01(\tR\x04step\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"\x9c\x01\n" +
+	"\vTaskLogLine\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x03R\bsequence\x12*\n" +
+	"\x02at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x18\n" +
+	"\aattempt\x18\x04 \x01(\x05R\aattempt\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\"~\n" +
+	"\x15AppendTaskLogsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x19\n" +
+	"\blease_id\x18\x03 \x01(\tR\aleaseId\x12\x14\n" +
+	"\x05lines\x18\x04 \x03(\tR\x05lines\"d\n" +
+	"\x16AppendTaskLogsResponse\x12%\n" +
+	"\x0elines_appended\x18\x01 \x01(\x03R\rlinesAppended\x12#\n" +
+	"\rlast_sequence\x18\x02 \x01(\x03R\flastSequence\"e\n" +
+	"\x13TailTaskLogsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1d\n" +
+	"\n" +
+	"tail_lines\x18\x02 \x01(\x05R\ttailLines\x12\x16\n" +
+	"\x06follow\x18\x03 \x01(\bR\x06follow*\xc3\x01\n" +
 	"\n" +
 	"TaskStatus\x12\x1b\n" +
 	"\x17TASK_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
@@ -718,17 +6507,81 @@ const file_task_v1_task_proto_rawDesc = "" +
 	"\x13TASK_STATUS_RUNNING\x10\x02\x12\x19\n" +
 	"\x15TASK_STATUS_COMPLETED\x10\x03\x12\x16\n" +
 	"\x12TASK_STATUS_FAILED\x10\x04\x12\x18\n" +
-	"\x14TASK_STATUS_CANCELED\x10\x052\xea\x03\n" +
+	"\x14TASK_STATUS_CANCELED\x10\x05\x12\x19\n" +
+	"\x15TASK_STATUS_SCHEDULED\x10\x06*\xee\x02\n" +
+	"\rTaskEventType\x12\x1f\n" +
+	"\x1bTASK_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eTASK_EVENT_TYPE_STATUS_CHANGED\x10\x01\x12!\n" +
+	"\x1dTASK_EVENT_TYPE_COMMENT_ADDED\x10\x02\x12\"\n" +
+	"\x1eTASK_EVENT_TYPE_COMMENT_EDITED\x10\x03\x12#\n" +
+	"\x1fTASK_EVENT_TYPE_COMMENT_DELETED\x10\x04\x12\x1b\n" +
+	"\x17TASK_EVENT_TYPE_CREATED\x10\x05\x12\x1b\n" +
+	"\x17TASK_EVENT_TYPE_UPDATED\x10\x06\x12\x1c\n" +
+	"\x18TASK_EVENT_TYPE_SNAPSHOT\x10\a\x12\x1d\n" +
+	"\x19TASK_EVENT_TYPE_HEARTBEAT\x10\b\x12\x17\n" +
+	"\x13TASK_EVENT_TYPE_GAP\x10\t\x12\x1c\n" +
+	"\x18TASK_EVENT_TYPE_PROGRESS\x10\n" +
+	"*\xab\x01\n" +
+	"\x14WebhookDeliveryState\x12&\n" +
+	"\"WEBHOOK_DELIVERY_STATE_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eWEBHOOK_DELIVERY_STATE_PENDING\x10\x01\x12$\n" +
+	" WEBHOOK_DELIVERY_STATE_SUCCEEDED\x10\x02\x12!\n" +
+	"\x1dWEBHOOK_DELIVERY_STATE_FAILED\x10\x03*~\n" +
+	"\rOverlapPolicy\x12\x1e\n" +
+	"\x1aOVERLAP_POLICY_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13OVERLAP_POLICY_SKIP\x10\x01\x12\x18\n" +
+	"\x14OVERLAP_POLICY_QUEUE\x10\x02\x12\x1a\n" +
+	"\x16OVERLAP_POLICY_REPLACE\x10\x032\xbc\x17\n" +
 	"\vTaskService\x12E\n" +
 	"\n" +
 	"CreateTask\x12\x1a.task.v1.CreateTaskRequest\x1a\x1b.task.v1.CreateTaskResponse\x121\n" +
 	"\aGetTask\x12\x17.task.v1.GetTaskRequest\x1a\r.task.v1.Task\x12B\n" +
 	"\tListTasks\x12\x19.task.v1.ListTasksRequest\x1a\x1a.task.v1.ListTasksResponse\x12Q\n" +
 	"\x10CreateTaskWithId\x12 .task.v1.CreateTaskWithIdRequest\x1a\x1b.task.v1.CreateTaskResponse\x12<\n" +
-	"\tWatchTask\x12\x19.task.v1.WatchTaskRequest\x1a\x12.task.v1.TaskEvent0\x01\x12G\n" +
+	"\tWatchTask\x12\x19.task.v1.WatchTaskRequest\x1a\x12.task.v1.TaskEvent0\x01\x12>\n" +
+	"\n" +
+	"WatchTasks\x12\x1a.task.v1.WatchTasksRequest\x1a\x12.task.v1.TaskEvent0\x01\x12G\n" +
 	"\n" +
 	"BulkCreate\x12\x1a.task.v1.CreateTaskRequest\x1a\x1b.task.v1.BulkCreateResponse(\x01\x12C\n" +
-	"\vTaskConsole\x12\x17.task.v1.ConsoleMessage\x1a\x17.task.v1.ConsoleMessage(\x010\x01B\x1dZ\x1bgrpc-lab/gen/task/v1;taskv1b\x06proto3"
+	"\vTaskConsole\x12\x17.task.v1.ConsoleMessage\x1a\x17.task.v1.ConsoleMessage(\x010\x01\x12:\n" +
+	"\n" +
+	"AddComment\x12\x1a.task.v1.AddCommentRequest\x1a\x10.task.v1.Comment\x12K\n" +
+	"\fListComments\x12\x1c.task.v1.ListCommentsRequest\x1a\x1d.task.v1.ListCommentsResponse\x12<\n" +
+	"\vEditComment\x12\x1b.task.v1.EditCommentRequest\x1a\x10.task.v1.Comment\x12N\n" +
+	"\rDeleteComment\x12\x1d.task.v1.DeleteCommentRequest\x1a\x1e.task.v1.DeleteCommentResponse\x12=\n" +
+	"\rSetTaskResult\x12\x1d.task.v1.SetTaskResultRequest\x1a\r.task.v1.Task\x12H\n" +
+	"\vSearchTasks\x12\x1b.task.v1.SearchTasksRequest\x1a\x1c.task.v1.SearchTasksResponse\x12@\n" +
+	"\fGetTaskStats\x12\x1c.task.v1.GetTaskStatsRequest\x1a\x12.task.v1.TaskStats\x12@\n" +
+	"\rCreateWebhook\x12\x1d.task.v1.CreateWebhookRequest\x1a\x10.task.v1.Webhook\x12K\n" +
+	"\fListWebhooks\x12\x1c.task.v1.ListWebhooksRequest\x1a\x1d.task.v1.ListWebhooksResponse\x12N\n" +
+	"\rDeleteWebhook\x12\x1d.task.v1.DeleteWebhookRequest\x1a\x1e.task.v1.DeleteWebhookResponse\x12f\n" +
+	"\x15ListWebhookDeliveries\x12%.task.v1.ListWebhookDeliveriesRequest\x1a&.task.v1.ListWebhookDeliveriesResponse\x12X\n" +
+	"\x15ReplayWebhookDelivery\x12%.task.v1.ReplayWebhookDeliveryRequest\x1a\x18.task.v1.WebhookDelivery\x12E\n" +
+	"\n" +
+	"ListEvents\x12\x1a.task.v1.ListEventsRequest\x1a\x1b.task.v1.ListEventsResponse\x12B\n" +
+	"\tClaimTask\x12\x19.task.v1.ClaimTaskRequest\x1a\x1a.task.v1.ClaimTaskResponse\x12>\n" +
+	"\rHeartbeatTask\x12\x1d.task.v1.HeartbeatTaskRequest\x1a\x0e.task.v1.Lease\x12;\n" +
+	"\fCompleteTask\x12\x1c.task.v1.CompleteTaskRequest\x1a\r.task.v1.Task\x123\n" +
+	"\bFailTask\x12\x18.task.v1.FailTaskRequest\x1a\r.task.v1.Task\x12E\n" +
+	"\rWorkerSession\x12\x16.task.v1.WorkerMessage\x1a\x18.task.v1.DispatchMessage(\x010\x01\x12:\n" +
+	"\vCreateQueue\x12\x1b.task.v1.CreateQueueRequest\x1a\x0e.task.v1.Queue\x12E\n" +
+	"\n" +
+	"ListQueues\x12\x1a.task.v1.ListQueuesRequest\x1a\x1b.task.v1.ListQueuesResponse\x12:\n" +
+	"\vUpdateQueue\x12\x1b.task.v1.UpdateQueueRequest\x1a\x0e.task.v1.Queue\x12C\n" +
+	"\x0eCreateSchedule\x12\x1e.task.v1.CreateScheduleRequest\x1a\x11.task.v1.Schedule\x12=\n" +
+	"\vGetSchedule\x12\x1b.task.v1.GetScheduleRequest\x1a\x11.task.v1.Schedule\x12N\n" +
+	"\rListSchedules\x12\x1d.task.v1.ListSchedulesRequest\x1a\x1e.task.v1.ListSchedulesResponse\x12C\n" +
+	"\x0eUpdateSchedule\x12\x1e.task.v1.UpdateScheduleRequest\x1a\x11.task.v1.Schedule\x12Q\n" +
+	"\x0eDeleteSchedule\x12\x1e.task.v1.DeleteScheduleRequest\x1a\x1f.task.v1.DeleteScheduleResponse\x12T\n" +
+	"\x0fListDeadLetters\x12\x1f.task.v1.ListDeadLettersRequest\x1a .task.v1.ListDeadLettersResponse\x12E\n" +
+	"\x11RequeueDeadLetter\x12!.task.v1.RequeueDeadLetterRequest\x1a\r.task.v1.Task\x12K\n" +
+	"\x10UploadAttachment\x12 .task.v1.UploadAttachmentRequest\x1a\x13.task.v1.Attachment(\x01\x12_\n" +
+	"\x12DownloadAttachment\x12\".task.v1.DownloadAttachmentRequest\x1a#.task.v1.DownloadAttachmentResponse0\x01\x12S\n" +
+	"\x0eAppendTaskLogs\x12\x1e.task.v1.AppendTaskLogsRequest\x1a\x1f.task.v1.AppendTaskLogsResponse(\x01\x12D\n" +
+	"\fTailTaskLogs\x12\x1c.task.v1.TailTaskLogsRequest\x1a\x14.task.v1.TaskLogLine0\x01\x12?\n" +
+	"\x0eReportProgress\x12\x1e.task.v1.ReportProgressRequest\x1a\r.task.v1.Task\x127\n" +
+	"\n" +
+	"CancelTask\x12\x1a.task.v1.CancelTaskRequest\x1a\r.task.v1.TaskB\x1dZ\x1bgrpc-lab/gen/task/v1;taskv1b\x06proto3"
 
 var (
 	file_task_v1_task_proto_rawDescOnce sync.Once
@@ -742,50 +6595,300 @@ func file_task_v1_task_proto_rawDescGZIP() []byte {
 	return file_task_v1_task_proto_rawDescData
 }
 
-var file_task_v1_task_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_task_v1_task_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_task_v1_task_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_task_v1_task_proto_msgTypes = make([]protoimpl.MessageInfo, 91)
 var file_task_v1_task_proto_goTypes = []any{
-	(TaskStatus)(0),                 // 0: task.v1.TaskStatus
-	(*Task)(nil),                    // 1: task.v1.Task
-	(*CreateTaskRequest)(nil),       // 2: task.v1.CreateTaskRequest
-	(*CreateTaskWithIdRequest)(nil), // 3: task.v1.CreateTaskWithIdRequest
-	(*CreateTaskResponse)(nil),      // 4: task.v1.CreateTaskResponse
-	(*GetTaskRequest)(nil),          // 5: task.v1.GetTaskRequest
-	(*ListTasksRequest)(nil),        // 6: task.v1.ListTasksRequest
-	(*ListTasksResponse)(nil),       // 7: task.v1.ListTasksResponse
-	(*WatchTaskRequest)(nil),        // 8: task.v1.WatchTaskRequest
-	(*TaskEvent)(nil),               // 9: task.v1.TaskEvent
-	(*BulkCreateResponse)(nil),      // 10: task.v1.BulkCreateResponse
-	(*ConsoleMessage)(nil),          // 11: task.v1.ConsoleMessage
-	(*timestamppb.Timestamp)(nil),   // 12: google.protobuf.Timestamp
+	(TaskStatus)(0),                       // 0: task.v1.TaskStatus
+	(TaskEventType)(0),                    // 1: task.v1.TaskEventType
+	(WebhookDeliveryState)(0),             // 2: task.v1.WebhookDeliveryState
+	(OverlapPolicy)(0),                    // 3: task.v1.OverlapPolicy
+	(*Task)(nil),                          // 4: task.v1.Task
+	(*TaskProgress)(nil),                  // 5: task.v1.TaskProgress
+	(*RetryPolicy)(nil),                   // 6: task.v1.RetryPolicy
+	(*Lease)(nil),                         // 7: task.v1.Lease
+	(*CreateTaskRequest)(nil),             // 8: task.v1.CreateTaskRequest
+	(*CreateTaskWithIdRequest)(nil),       // 9: task.v1.CreateTaskWithIdRequest
+	(*CreateTaskResponse)(nil),            // 10: task.v1.CreateTaskResponse
+	(*GetTaskRequest)(nil),                // 11: task.v1.GetTaskRequest
+	(*ListTasksRequest)(nil),              // 12: task.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),             // 13: task.v1.ListTasksResponse
+	(*WatchTaskRequest)(nil),              // 14: task.v1.WatchTaskRequest
+	(*TaskEvent)(nil),                     // 15: task.v1.TaskEvent
+	(*TaskFilter)(nil),                    // 16: task.v1.TaskFilter
+	(*WatchTasksRequest)(nil),             // 17: task.v1.WatchTasksRequest
+	(*BulkCreateResponse)(nil),            // 18: task.v1.BulkCreateResponse
+	(*ConsoleMessage)(nil),                // 19: task.v1.ConsoleMessage
+	(*Comment)(nil),                       // 20: task.v1.Comment
+	(*AddCommentRequest)(nil),             // 21: task.v1.AddCommentRequest
+	(*ListCommentsRequest)(nil),           // 22: task.v1.ListCommentsRequest
+	(*ListCommentsResponse)(nil),          // 23: task.v1.ListCommentsResponse
+	(*EditCommentRequest)(nil),            // 24: task.v1.EditCommentRequest
+	(*DeleteCommentRequest)(nil),          // 25: task.v1.DeleteCommentRequest
+	(*DeleteCommentResponse)(nil),         // 26: task.v1.DeleteCommentResponse
+	(*SetTaskResultRequest)(nil),          // 27: task.v1.SetTaskResultRequest
+	(*SearchTasksRequest)(nil),            // 28: task.v1.SearchTasksRequest
+	(*SearchResult)(nil),                  // 29: task.v1.SearchResult
+	(*SearchTasksResponse)(nil),           // 30: task.v1.SearchTasksResponse
+	(*GetTaskStatsRequest)(nil),           // 31: task.v1.GetTaskStatsRequest
+	(*ThroughputWindow)(nil),              // 32: task.v1.ThroughputWindow
+	(*StatusDuration)(nil),                // 33: task.v1.StatusDuration
+	(*TaskStats)(nil),                     // 34: task.v1.TaskStats
+	(*WatchStreamStats)(nil),              // 35: task.v1.WatchStreamStats
+	(*Webhook)(nil),                       // 36: task.v1.Webhook
+	(*CreateWebhookRequest)(nil),          // 37: task.v1.CreateWebhookRequest
+	(*ListWebhooksRequest)(nil),           // 38: task.v1.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),          // 39: task.v1.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),          // 40: task.v1.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),         // 41: task.v1.DeleteWebhookResponse
+	(*WebhookDelivery)(nil),               // 42: task.v1.WebhookDelivery
+	(*WebhookPayload)(nil),                // 43: task.v1.WebhookPayload
+	(*ListWebhookDeliveriesRequest)(nil),  // 44: task.v1.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil), // 45: task.v1.ListWebhookDeliveriesResponse
+	(*ReplayWebhookDeliveryRequest)(nil),  // 46: task.v1.ReplayWebhookDeliveryRequest
+	(*AttachmentMetadata)(nil),            // 47: task.v1.AttachmentMetadata
+	(*UploadAttachmentRequest)(nil),       // 48: task.v1.UploadAttachmentRequest
+	(*Attachment)(nil),                    // 49: task.v1.Attachment
+	(*DownloadAttachmentRequest)(nil),     // 50: task.v1.DownloadAttachmentRequest
+	(*DownloadAttachmentResponse)(nil),    // 51: task.v1.DownloadAttachmentResponse
+	(*ListEventsRequest)(nil),             // 52: task.v1.ListEventsRequest
+	(*ListEventsResponse)(nil),            // 53: task.v1.ListEventsResponse
+	(*ClaimTaskRequest)(nil),              // 54: task.v1.ClaimTaskRequest
+	(*ClaimTaskResponse)(nil),             // 55: task.v1.ClaimTaskResponse
+	(*HeartbeatTaskRequest)(nil),          // 56: task.v1.HeartbeatTaskRequest
+	(*CompleteTaskRequest)(nil),           // 57: task.v1.CompleteTaskRequest
+	(*FailTaskRequest)(nil),               // 58: task.v1.FailTaskRequest
+	(*WorkerHello)(nil),                   // 59: task.v1.WorkerHello
+	(*WorkerHeartbeat)(nil),               // 60: task.v1.WorkerHeartbeat
+	(*WorkerProgress)(nil),                // 61: task.v1.WorkerProgress
+	(*WorkerResult)(nil),                  // 62: task.v1.WorkerResult
+	(*WorkerMessage)(nil),                 // 63: task.v1.WorkerMessage
+	(*TaskAssignment)(nil),                // 64: task.v1.TaskAssignment
+	(*TaskRevoked)(nil),                   // 65: task.v1.TaskRevoked
+	(*DispatchMessage)(nil),               // 66: task.v1.DispatchMessage
+	(*Queue)(nil),                         // 67: task.v1.Queue
+	(*CreateQueueRequest)(nil),            // 68: task.v1.CreateQueueRequest
+	(*ListQueuesRequest)(nil),             // 69: task.v1.ListQueuesRequest
+	(*ListQueuesResponse)(nil),            // 70: task.v1.ListQueuesResponse
+	(*UpdateQueueRequest)(nil),            // 71: task.v1.UpdateQueueRequest
+	(*Schedule)(nil),                      // 72: task.v1.Schedule
+	(*CreateScheduleRequest)(nil),         // 73: task.v1.CreateScheduleRequest
+	(*GetScheduleRequest)(nil),            // 74: task.v1.GetScheduleRequest
+	(*ListSchedulesRequest)(nil),          // 75: task.v1.ListSchedulesRequest
+	(*ListSchedulesResponse)(nil),         // 76: task.v1.ListSchedulesResponse
+	(*UpdateScheduleRequest)(nil),         // 77: task.v1.UpdateScheduleRequest
+	(*DeleteScheduleRequest)(nil),         // 78: task.v1.DeleteScheduleRequest
+	(*DeleteScheduleResponse)(nil),        // 79: task.v1.DeleteScheduleResponse
+	(*ListDeadLettersRequest)(nil),        // 80: task.v1.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),       // 81: task.v1.ListDeadLettersResponse
+	(*RequeueDeadLetterRequest)(nil),      // 82: task.v1.RequeueDeadLetterRequest
+	(*CancelTaskRequest)(nil),             // 83: task.v1.CancelTaskRequest
+	(*ReportProgressRequest)(nil),         // 84: task.v1.ReportProgressRequest
+	(*TaskLogLine)(nil),                   // 85: task.v1.TaskLogLine
+	(*AppendTaskLogsRequest)(nil),         // 86: task.v1.AppendTaskLogsRequest
+	(*AppendTaskLogsResponse)(nil),        // 87: task.v1.AppendTaskLogsResponse
+	(*TailTaskLogsRequest)(nil),           // 88: task.v1.TailTaskLogsRequest
+	nil,                                   // 89: task.v1.Task.LabelsEntry
+	nil,                                   // 90: task.v1.CreateTaskRequest.LabelsEntry
+	nil,                                   // 91: task.v1.CreateTaskWithIdRequest.LabelsEntry
+	nil,                                   // 92: task.v1.TaskFilter.LabelsEntry
+	nil,                                   // 93: task.v1.TaskStats.ByStatusEntry
+	nil,                                   // 94: task.v1.TaskStats.ByLabelEntry
+	(*timestamppb.Timestamp)(nil),         // 95: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),               // 96: google.protobuf.Struct
+	(*durationpb.Duration)(nil),           // 97: google.protobuf.Duration
 }
 var file_task_v1_task_proto_depIdxs = []int32{
-	0,  // 0: task.v1.Task.status:type_name -> task.v1.TaskStatus
-	12, // 1: task.v1.Task.created_at:type_name -> google.protobuf.Timestamp
-	12, // 2: task.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
-	1,  // 3: task.v1.CreateTaskResponse.task:type_name -> task.v1.Task
-	1,  // 4: task.v1.ListTasksResponse.tasks:type_name -> task.v1.Task
-	0,  // 5: task.v1.TaskEvent.status:type_name -> task.v1.TaskStatus
-	12, // 6: task.v1.TaskEvent.at:type_name -> google.protobuf.Timestamp
-	2,  // 7: task.v1.TaskService.CreateTask:input_type -> task.v1.CreateTaskRequest
-	5,  // 8: task.v1.TaskService.GetTask:input_type -> task.v1.GetTaskRequest
-	6,  // 9: task.v1.TaskService.ListTasks:input_type -> task.v1.ListTasksRequest
-	3,  // 10: task.v1.TaskService.CreateTaskWithId:input_type -> task.v1.CreateTaskWithIdRequest
-	8,  // 11: task.v1.TaskService.WatchTask:input_type -> task.v1.WatchTaskRequest
-	2,  // 12: task.v1.TaskService.BulkCreate:input_type -> task.v1.CreateTaskRequest
-	11, // 13: task.v1.TaskService.TaskConsole:input_type -> task.v1.ConsoleMessage
-	4,  // 14: task.v1.TaskService.CreateTask:output_type -> task.v1.CreateTaskResponse
-	1,  // 15: task.v1.TaskService.GetTask:output_type -> task.v1.Task
-	7,  // 16: task.v1.TaskService.ListTasks:output_type -> task.v1.ListTasksResponse
-	4,  // 17: task.v1.TaskService.CreateTaskWithId:output_type -> task.v1.CreateTaskResponse
-	9,  // 18: task.v1.TaskService.WatchTask:output_type -> task.v1.TaskEvent
-	10, // 19: task.v1.TaskService.BulkCreate:output_type -> task.v1.BulkCreateResponse
-	11, // 20: task.v1.TaskService.TaskConsole:output_type -> task.v1.ConsoleMessage
-	14, // [14:21] is the sub-list for method output_type
-	7,  // [7:14] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	0,   // 0: task.v1.Task.status:type_name -> task.v1.TaskStatus
+	95,  // 1: task.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	95,  // 2: task.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	96,  // 3: task.v1.Task.input:type_name -> google.protobuf.Struct
+	96,  // 4: task.v1.Task.result:type_name -> google.protobuf.Struct
+	89,  // 5: task.v1.Task.labels:type_name -> task.v1.Task.LabelsEntry
+	7,   // 6: task.v1.Task.lease:type_name -> task.v1.Lease
+	95,  // 7: task.v1.Task.run_at:type_name -> google.protobuf.Timestamp
+	6,   // 8: task.v1.Task.retry_policy:type_name -> task.v1.RetryPolicy
+	95,  // 9: task.v1.Task.dead_lettered_at:type_name -> google.protobuf.Timestamp
+	97,  // 10: task.v1.Task.execution_timeout:type_name -> google.protobuf.Duration
+	5,   // 11: task.v1.Task.progress:type_name -> task.v1.TaskProgress
+	95,  // 12: task.v1.TaskProgress.updated_at:type_name -> google.protobuf.Timestamp
+	97,  // 13: task.v1.RetryPolicy.initial_backoff:type_name -> google.protobuf.Duration
+	97,  // 14: task.v1.RetryPolicy.max_backoff:type_name -> google.protobuf.Duration
+	95,  // 15: task.v1.Lease.claimed_at:type_name -> google.protobuf.Timestamp
+	95,  // 16: task.v1.Lease.expires_at:type_name -> google.protobuf.Timestamp
+	96,  // 17: task.v1.CreateTaskRequest.input:type_name -> google.protobuf.Struct
+	90,  // 18: task.v1.CreateTaskRequest.labels:type_name -> task.v1.CreateTaskRequest.LabelsEntry
+	95,  // 19: task.v1.CreateTaskRequest.run_at:type_name -> google.protobuf.Timestamp
+	6,   // 20: task.v1.CreateTaskRequest.retry_policy:type_name -> task.v1.RetryPolicy
+	97,  // 21: task.v1.CreateTaskRequest.execution_timeout:type_name -> google.protobuf.Duration
+	96,  // 22: task.v1.CreateTaskWithIdRequest.input:type_name -> google.protobuf.Struct
+	91,  // 23: task.v1.CreateTaskWithIdRequest.labels:type_name -> task.v1.CreateTaskWithIdRequest.LabelsEntry
+	95,  // 24: task.v1.CreateTaskWithIdRequest.run_at:type_name -> google.protobuf.Timestamp
+	6,   // 25: task.v1.CreateTaskWithIdRequest.retry_policy:type_name -> task.v1.RetryPolicy
+	97,  // 26: task.v1.CreateTaskWithIdRequest.execution_timeout:type_name -> google.protobuf.Duration
+	4,   // 27: task.v1.CreateTaskResponse.task:type_name -> task.v1.Task
+	4,   // 28: task.v1.ListTasksResponse.tasks:type_name -> task.v1.Task
+	97,  // 29: task.v1.WatchTaskRequest.heartbeat_interval:type_name -> google.protobuf.Duration
+	0,   // 30: task.v1.TaskEvent.status:type_name -> task.v1.TaskStatus
+	95,  // 31: task.v1.TaskEvent.at:type_name -> google.protobuf.Timestamp
+	1,   // 32: task.v1.TaskEvent.type:type_name -> task.v1.TaskEventType
+	20,  // 33: task.v1.TaskEvent.comment:type_name -> task.v1.Comment
+	4,   // 34: task.v1.TaskEvent.task:type_name -> task.v1.Task
+	0,   // 35: task.v1.TaskEvent.previous_status:type_name -> task.v1.TaskStatus
+	0,   // 36: task.v1.TaskFilter.statuses:type_name -> task.v1.TaskStatus
+	92,  // 37: task.v1.TaskFilter.labels:type_name -> task.v1.TaskFilter.LabelsEntry
+	16,  // 38: task.v1.WatchTasksRequest.filter:type_name -> task.v1.TaskFilter
+	97,  // 39: task.v1.WatchTasksRequest.heartbeat_interval:type_name -> google.protobuf.Duration
+	95,  // 40: task.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	95,  // 41: task.v1.Comment.updated_at:type_name -> google.protobuf.Timestamp
+	20,  // 42: task.v1.ListCommentsResponse.comments:type_name -> task.v1.Comment
+	96,  // 43: task.v1.SetTaskResultRequest.result:type_name -> google.protobuf.Struct
+	4,   // 44: task.v1.SearchResult.task:type_name -> task.v1.Task
+	29,  // 45: task.v1.SearchTasksResponse.results:type_name -> task.v1.SearchResult
+	97,  // 46: task.v1.ThroughputWindow.window:type_name -> google.protobuf.Duration
+	0,   // 47: task.v1.StatusDuration.status:type_name -> task.v1.TaskStatus
+	97,  // 48: task.v1.StatusDuration.median:type_name -> google.protobuf.Duration
+	97,  // 49: task.v1.StatusDuration.p95:type_name -> google.protobuf.Duration
+	93,  // 50: task.v1.TaskStats.by_status:type_name -> task.v1.TaskStats.ByStatusEntry
+	94,  // 51: task.v1.TaskStats.by_label:type_name -> task.v1.TaskStats.ByLabelEntry
+	32,  // 52: task.v1.TaskStats.throughput:type_name -> task.v1.ThroughputWindow
+	33,  // 53: task.v1.TaskStats.time_in_status:type_name -> task.v1.StatusDuration
+	35,  // 54: task.v1.TaskStats.watch_streams:type_name -> task.v1.WatchStreamStats
+	1,   // 55: task.v1.Webhook.event_types:type_name -> task.v1.TaskEventType
+	16,  // 56: task.v1.Webhook.filter:type_name -> task.v1.TaskFilter
+	95,  // 57: task.v1.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	1,   // 58: task.v1.CreateWebhookRequest.event_types:type_name -> task.v1.TaskEventType
+	16,  // 59: task.v1.CreateWebhookRequest.filter:type_name -> task.v1.TaskFilter
+	36,  // 60: task.v1.ListWebhooksResponse.webhooks:type_name -> task.v1.Webhook
+	15,  // 61: task.v1.WebhookDelivery.event:type_name -> task.v1.TaskEvent
+	2,   // 62: task.v1.WebhookDelivery.state:type_name -> task.v1.WebhookDeliveryState
+	95,  // 63: task.v1.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	95,  // 64: task.v1.WebhookDelivery.updated_at:type_name -> google.protobuf.Timestamp
+	15,  // 65: task.v1.WebhookPayload.event:type_name -> task.v1.TaskEvent
+	42,  // 66: task.v1.ListWebhookDeliveriesResponse.deliveries:type_name -> task.v1.WebhookDelivery
+	47,  // 67: task.v1.UploadAttachmentRequest.metadata:type_name -> task.v1.AttachmentMetadata
+	95,  // 68: task.v1.Attachment.created_at:type_name -> google.protobuf.Timestamp
+	49,  // 69: task.v1.DownloadAttachmentResponse.attachment:type_name -> task.v1.Attachment
+	16,  // 70: task.v1.ListEventsRequest.filter:type_name -> task.v1.TaskFilter
+	1,   // 71: task.v1.ListEventsRequest.types:type_name -> task.v1.TaskEventType
+	95,  // 72: task.v1.ListEventsRequest.start_time:type_name -> google.protobuf.Timestamp
+	95,  // 73: task.v1.ListEventsRequest.end_time:type_name -> google.protobuf.Timestamp
+	15,  // 74: task.v1.ListEventsResponse.events:type_name -> task.v1.TaskEvent
+	97,  // 75: task.v1.ClaimTaskRequest.lease_duration:type_name -> google.protobuf.Duration
+	4,   // 76: task.v1.ClaimTaskResponse.task:type_name -> task.v1.Task
+	97,  // 77: task.v1.HeartbeatTaskRequest.lease_duration:type_name -> google.protobuf.Duration
+	96,  // 78: task.v1.CompleteTaskRequest.result:type_name -> google.protobuf.Struct
+	97,  // 79: task.v1.WorkerHello.lease_duration:type_name -> google.protobuf.Duration
+	96,  // 80: task.v1.WorkerResult.result:type_name -> google.protobuf.Struct
+	59,  // 81: task.v1.WorkerMessage.hello:type_name -> task.v1.WorkerHello
+	60,  // 82: task.v1.WorkerMessage.heartbeat:type_name -> task.v1.WorkerHeartbeat
+	61,  // 83: task.v1.WorkerMessage.progress:type_name -> task.v1.WorkerProgress
+	62,  // 84: task.v1.WorkerMessage.result:type_name -> task.v1.WorkerResult
+	4,   // 85: task.v1.TaskAssignment.task:type_name -> task.v1.Task
+	64,  // 86: task.v1.DispatchMessage.assignment:type_name -> task.v1.TaskAssignment
+	65,  // 87: task.v1.DispatchMessage.revoked:type_name -> task.v1.TaskRevoked
+	95,  // 88: task.v1.Queue.created_at:type_name -> google.protobuf.Timestamp
+	67,  // 89: task.v1.ListQueuesResponse.queues:type_name -> task.v1.Queue
+	8,   // 90: task.v1.Schedule.template:type_name -> task.v1.CreateTaskRequest
+	3,   // 91: task.v1.Schedule.overlap_policy:type_name -> task.v1.OverlapPolicy
+	95,  // 92: task.v1.Schedule.created_at:type_name -> google.protobuf.Timestamp
+	95,  // 93: task.v1.Schedule.next_run_at:type_name -> google.protobuf.Timestamp
+	95,  // 94: task.v1.Schedule.last_run_at:type_name -> google.protobuf.Timestamp
+	8,   // 95: task.v1.CreateScheduleRequest.template:type_name -> task.v1.CreateTaskRequest
+	3,   // 96: task.v1.CreateScheduleRequest.overlap_policy:type_name -> task.v1.OverlapPolicy
+	72,  // 97: task.v1.ListSchedulesResponse.schedules:type_name -> task.v1.Schedule
+	8,   // 98: task.v1.UpdateScheduleRequest.template:type_name -> task.v1.CreateTaskRequest
+	3,   // 99: task.v1.UpdateScheduleRequest.overlap_policy:type_name -> task.v1.OverlapPolicy
+	4,   // 100: task.v1.ListDeadLettersResponse.tasks:type_name -> task.v1.Task
+	95,  // 101: task.v1.TaskLogLine.at:type_name -> google.protobuf.Timestamp
+	8,   // 102: task.v1.TaskService.CreateTask:input_type -> task.v1.CreateTaskRequest
+	11,  // 103: task.v1.TaskService.GetTask:input_type -> task.v1.GetTaskRequest
+	12,  // 104: task.v1.TaskService.ListTasks:input_type -> task.v1.ListTasksRequest
+	9,   // 105: task.v1.TaskService.CreateTaskWithId:input_type -> task.v1.CreateTaskWithIdRequest
+	14,  // 106: task.v1.TaskService.WatchTask:input_type -> task.v1.WatchTaskRequest
+	17,  // 107: task.v1.TaskService.WatchTasks:input_type -> task.v1.WatchTasksRequest
+	8,   // 108: task.v1.TaskService.BulkCreate:input_type -> task.v1.CreateTaskRequest
+	19,  // 109: task.v1.TaskService.TaskConsole:input_type -> task.v1.ConsoleMessage
+	21,  // 110: task.v1.TaskService.AddComment:input_type -> task.v1.AddCommentRequest
+	22,  // 111: task.v1.TaskService.ListComments:input_type -> task.v1.ListCommentsRequest
+	24,  // 112: task.v1.TaskService.EditComment:input_type -> task.v1.EditCommentRequest
+	25,  // 113: task.v1.TaskService.DeleteComment:input_type -> task.v1.DeleteCommentRequest
+	27,  // 114: task.v1.TaskService.SetTaskResult:input_type -> task.v1.SetTaskResultRequest
+	28,  // 115: task.v1.TaskService.SearchTasks:input_type -> task.v1.SearchTasksRequest
+	31,  // 116: task.v1.TaskService.GetTaskStats:input_type -> task.v1.GetTaskStatsRequest
+	37,  // 117: task.v1.TaskService.CreateWebhook:input_type -> task.v1.CreateWebhookRequest
+	38,  // 118: task.v1.TaskService.ListWebhooks:input_type -> task.v1.ListWebhooksRequest
+	40,  // 119: task.v1.TaskService.DeleteWebhook:input_type -> task.v1.DeleteWebhookRequest
+	44,  // 120: task.v1.TaskService.ListWebhookDeliveries:input_type -> task.v1.ListWebhookDeliveriesRequest
+	46,  // 121: task.v1.TaskService.ReplayWebhookDelivery:input_type -> task.v1.ReplayWebhookDeliveryRequest
+	52,  // 122: task.v1.TaskService.ListEvents:input_type -> task.v1.ListEventsRequest
+	54,  // 123: task.v1.TaskService.ClaimTask:input_type -> task.v1.ClaimTaskRequest
+	56,  // 124: task.v1.TaskService.HeartbeatTask:input_type -> task.v1.HeartbeatTaskRequest
+	57,  // 125: task.v1.TaskService.CompleteTask:input_type -> task.v1.CompleteTaskRequest
+	58,  // 126: task.v1.TaskService.FailTask:input_type -> task.v1.FailTaskRequest
+	63,  // 127: task.v1.TaskService.WorkerSession:input_type -> task.v1.WorkerMessage
+	68,  // 128: task.v1.TaskService.CreateQueue:input_type -> task.v1.CreateQueueRequest
+	69,  // 129: task.v1.TaskService.ListQueues:input_type -> task.v1.ListQueuesRequest
+	71,  // 130: task.v1.TaskService.UpdateQueue:input_type -> task.v1.UpdateQueueRequest
+	73,  // 131: task.v1.TaskService.CreateSchedule:input_type -> task.v1.CreateScheduleRequest
+	74,  // 132: task.v1.TaskService.GetSchedule:input_type -> task.v1.GetScheduleRequest
+	75,  // 133: task.v1.TaskService.ListSchedules:input_type -> task.v1.ListSchedulesRequest
+	77,  // 134: task.v1.TaskService.UpdateSchedule:input_type -> task.v1.UpdateScheduleRequest
+	78,  // 135: task.v1.TaskService.DeleteSchedule:input_type -> task.v1.DeleteScheduleRequest
+	80,  // 136: task.v1.TaskService.ListDeadLetters:input_type -> task.v1.ListDeadLettersRequest
+	82,  // 137: task.v1.TaskService.RequeueDeadLetter:input_type -> task.v1.RequeueDeadLetterRequest
+	48,  // 138: task.v1.TaskService.UploadAttachment:input_type -> task.v1.UploadAttachmentRequest
+	50,  // 139: task.v1.TaskService.DownloadAttachment:input_type -> task.v1.DownloadAttachmentRequest
+	86,  // 140: task.v1.TaskService.AppendTaskLogs:input_type -> task.v1.AppendTaskLogsRequest
+	88,  // 141: task.v1.TaskService.TailTaskLogs:input_type -> task.v1.TailTaskLogsRequest
+	84,  // 142: task.v1.TaskService.ReportProgress:input_type -> task.v1.ReportProgressRequest
+	83,  // 143: task.v1.TaskService.CancelTask:input_type -> task.v1.CancelTaskRequest
+	10,  // 144: task.v1.TaskService.CreateTask:output_type -> task.v1.CreateTaskResponse
+	4,   // 145: task.v1.TaskService.GetTask:output_type -> task.v1.Task
+	13,  // 146: task.v1.TaskService.ListTasks:output_type -> task.v1.ListTasksResponse
+	10,  // 147: task.v1.TaskService.CreateTaskWithId:output_type -> task.v1.CreateTaskResponse
+	15,  // 148: task.v1.TaskService.WatchTask:output_type -> task.v1.TaskEvent
+	15,  // 149: task.v1.TaskService.WatchTasks:output_type -> task.v1.TaskEvent
+	18,  // 150: task.v1.TaskService.BulkCreate:output_type -> task.v1.BulkCreateResponse
+	19,  // 151: task.v1.TaskService.TaskConsole:output_type -> task.v1.ConsoleMessage
+	20,  // 152: task.v1.TaskService.AddComment:output_type -> task.v1.Comment
+	23,  // 153: task.v1.TaskService.ListComments:output_type -> task.v1.ListCommentsResponse
+	20,  // 154: task.v1.TaskService.EditComment:output_type -> task.v1.Comment
+	26,  // 155: task.v1.TaskService.DeleteComment:output_type -> task.v1.DeleteCommentResponse
+	4,   // 156: task.v1.TaskService.SetTaskResult:output_type -> task.v1.Task
+	30,  // 157: task.v1.TaskService.SearchTasks:output_type -> task.v1.SearchTasksResponse
+	34,  // 158: task.v1.TaskService.GetTaskStats:output_type -> task.v1.TaskStats
+	36,  // 159: task.v1.TaskService.CreateWebhook:output_type -> task.v1.Webhook
+	39,  // 160: task.v1.TaskService.ListWebhooks:output_type -> task.v1.ListWebhooksResponse
+	41,  // 161: task.v1.TaskService.DeleteWebhook:output_type -> task.v1.DeleteWebhookResponse
+	45,  // 162: task.v1.TaskService.ListWebhookDeliveries:output_type -> task.v1.ListWebhookDeliveriesResponse
+	42,  // 163: task.v1.TaskService.ReplayWebhookDelivery:output_type -> task.v1.WebhookDelivery
+	53,  // 164: task.v1.TaskService.ListEvents:output_type -> task.v1.ListEventsResponse
+	55,  // 165: task.v1.TaskService.ClaimTask:output_type -> task.v1.ClaimTaskResponse
+	7,   // 166: task.v1.TaskService.HeartbeatTask:output_type -> task.v1.Lease
+	4,   // 167: task.v1.TaskService.CompleteTask:output_type -> task.v1.Task
+	4,   // 168: task.v1.TaskService.FailTask:output_type -> task.v1.Task
+	66,  // 169: task.v1.TaskService.WorkerSession:output_type -> task.v1.DispatchMessage
+	67,  // 170: task.v1.TaskService.CreateQueue:output_type -> task.v1.Queue
+	70,  // 171: task.v1.TaskService.ListQueues:output_type -> task.v1.ListQueuesResponse
+	67,  // 172: task.v1.TaskService.UpdateQueue:output_type -> task.v1.Queue
+	72,  // 173: task.v1.TaskService.CreateSchedule:output_type -> task.v1.Schedule
+	72,  // 174: task.v1.TaskService.GetSchedule:output_type -> task.v1.Schedule
+	76,  // 175: task.v1.TaskService.ListSchedules:output_type -> task.v1.ListSchedulesResponse
+	72,  // 176: task.v1.TaskService.UpdateSchedule:output_type -> task.v1.Schedule
+	79,  // 177: task.v1.TaskService.DeleteSchedule:output_type -> task.v1.DeleteScheduleResponse
+	81,  // 178: task.v1.TaskService.ListDeadLetters:output_type -> task.v1.ListDeadLettersResponse
+	4,   // 179: task.v1.TaskService.RequeueDeadLetter:output_type -> task.v1.Task
+	49,  // 180: task.v1.TaskService.UploadAttachment:output_type -> task.v1.Attachment
+	51,  // 181: task.v1.TaskService.DownloadAttachment:output_type -> task.v1.DownloadAttachmentResponse
+	87,  // 182: task.v1.TaskService.AppendTaskLogs:output_type -> task.v1.AppendTaskLogsResponse
+	85,  // 183: task.v1.TaskService.TailTaskLogs:output_type -> task.v1.TaskLogLine
+	4,   // 184: task.v1.TaskService.ReportProgress:output_type -> task.v1.Task
+	4,   // 185: task.v1.TaskService.CancelTask:output_type -> task.v1.Task
+	144, // [144:186] is the sub-list for method output_type
+	102, // [102:144] is the sub-list for method input_type
+	102, // [102:102] is the sub-list for extension type_name
+	102, // [102:102] is the sub-list for extension extendee
+	0,   // [0:102] is the sub-list for field type_name
 }
 
 func init() { file_task_v1_task_proto_init() }
@@ -793,13 +6896,35 @@ func file_task_v1_task_proto_init() {
 	if File_task_v1_task_proto != nil {
 		return
 	}
+	file_task_v1_task_proto_msgTypes[44].OneofWrappers = []any{
+		(*UploadAttachmentRequest_Metadata)(nil),
+		(*UploadAttachmentRequest_Chunk)(nil),
+	}
+	file_task_v1_task_proto_msgTypes[47].OneofWrappers = []any{
+		(*DownloadAttachmentResponse_Attachment)(nil),
+		(*DownloadAttachmentResponse_Chunk)(nil),
+	}
+	file_task_v1_task_proto_msgTypes[58].OneofWrappers = []any{
+		(*WorkerResult_Result)(nil),
+		(*WorkerResult_Error)(nil),
+	}
+	file_task_v1_task_proto_msgTypes[59].OneofWrappers = []any{
+		(*WorkerMessage_Hello)(nil),
+		(*WorkerMessage_Heartbeat)(nil),
+		(*WorkerMessage_Progress)(nil),
+		(*WorkerMessage_Result)(nil),
+	}
+	file_task_v1_task_proto_msgTypes[62].OneofWrappers = []any{
+		(*DispatchMessage_Assignment)(nil),
+		(*DispatchMessage_Revoked)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_task_v1_task_proto_rawDesc), len(file_task_v1_task_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      4,
+			NumMessages:   91,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,107 @@
+// Package blobstore keeps immutable blobs on local disk, addressed by the
+// hex SHA-256 of their content.
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"os"
+	"path/filepath"
+)
+
+var ErrInvalidSum = errors.New("blobstore: invalid sha256")
+
+type Store struct {
+	dir string
+}
+
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Create starts a new blob. The content only becomes visible once Commit
+// succeeds; Abort discards it.
+func (s *Store) Create() (*Writer, error) {
+	f, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{store: s, f: f, h: sha256.New()}, nil
+}
+
+// Open returns the blob with the given hex SHA-256.
+func (s *Store) Open(sum string) (*os.File, error) {
+	path, err := s.path(sum)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *Store) path(sum string) (string, error) {
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return "", ErrInvalidSum
+	}
+	return filepath.Join(s.dir, sum[:2], sum), nil
+}
+
+type Writer struct {
+	store *Store
+	f     *os.File
+	h     hash.Hash
+	n     int64
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.f.Write(p)
+	w.h.Write(p[:n])
+	w.n += int64(n)
+	return n, err
+}
+
+// Size reports the number of bytes written so far.
+func (w *Writer) Size() int64 {
+	return w.n
+}
+
+// Sum returns the hex SHA-256 of the bytes written so far.
+func (w *Writer) Sum() string {
+	return hex.EncodeToString(w.h.Sum(nil))
+}
+
+// Commit moves the blob into place and returns its sum. Identical content
+// that is already stored is kept and the new copy dropped.
+func (w *Writer) Commit() (string, error) {
+	sum := w.Sum()
+	if err := w.f.Sync(); err != nil {
+		w.Abort()
+		return "", err
+	}
+	if err := w.f.Close(); err != nil {
+		os.Remove(w.f.Name())
+		return "", err
+	}
+	path, _ := w.store.path(sum)
+	if _, err := os.Stat(path); err == nil {
+		return sum, os.Remove(w.f.Name())
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		os.Remove(w.f.Name())
+		return "", err
+	}
+	if err := os.Rename(w.f.Name(), path); err != nil {
+		os.Remove(w.f.Name())
+		return "", err
+	}
+	return sum, nil
+}
+
+func (w *Writer) Abort() error {
+	w.f.Close()
+	return os.Remove(w.f.Name())
+}
@@ -0,0 +1,110 @@
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func put(t *testing.T, s *Store, data string) string {
+	t.Helper()
+	w, err := s.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	sum, err := w.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	return sum
+}
+
+func TestStore_CommitThenOpen(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	w, err := s.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	io.WriteString(w, "hello ")
+	io.WriteString(w, "world")
+	want := sha256.Sum256([]byte("hello world"))
+	if w.Size() != 11 || w.Sum() != hex.EncodeToString(want[:]) {
+		t.Fatalf("expected size 11 and the content's sum, got %d %s", w.Size(), w.Sum())
+	}
+	sum, err := w.Commit()
+	if err != nil || sum != hex.EncodeToString(want[:]) {
+		t.Fatalf("Commit = %s, %v", sum, err)
+	}
+
+	f, err := s.Open(sum)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+	got, _ := io.ReadAll(f)
+	if string(got) != "hello world" {
+		t.Fatalf("expected the committed content, got %q", got)
+	}
+}
+
+func TestStore_IdenticalContentStoredOnce(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	first := put(t, s, "same")
+	if second := put(t, s, "same"); second != first {
+		t.Fatalf("expected the same sum, got %s and %s", first, second)
+	}
+	blobs, _ := os.ReadDir(filepath.Join(dir, first[:2]))
+	temps, _ := os.ReadDir(filepath.Join(dir, "tmp"))
+	if len(blobs) != 1 || len(temps) != 0 {
+		t.Fatalf("expected one blob and no leftovers, got %d blobs and %d temp files", len(blobs), len(temps))
+	}
+}
+
+func TestStore_AbortDiscards(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	w, err := s.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	io.WriteString(w, "partial")
+	sum := w.Sum()
+	if err := w.Abort(); err != nil {
+		t.Fatalf("Abort failed: %v", err)
+	}
+	if temps, _ := os.ReadDir(filepath.Join(dir, "tmp")); len(temps) != 0 {
+		t.Fatalf("expected the upload removed, got %d temp files", len(temps))
+	}
+	if _, err := s.Open(sum); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected an aborted blob not to exist, got %v", err)
+	}
+}
+
+func TestStore_OpenRejectsInvalidSum(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for _, sum := range []string{"", "abc", "../../etc/passwd", "zz" + hex.EncodeToString(make([]byte, sha256.Size-1))} {
+		if _, err := s.Open(sum); !errors.Is(err, ErrInvalidSum) {
+			t.Errorf("Open(%q): expected ErrInvalidSum, got %v", sum, err)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// Package cdc exports task changes as JSON lines to size- and time-rotated
+// files. Rotated files are gzipped and listed in manifest.json; a checkpoint
+// records the last exported revision so a restarted exporter continues
+// exactly where it stopped.
+package cdc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	activeName     = "active.jsonl"
+	manifestName   = "manifest.json"
+	checkpointName = "checkpoint.json"
+)
+
+type Options struct {
+	Dir string
+	// MaxBytes and MaxAge trigger rotation of the active file; zero
+	// disables either.
+	MaxBytes int64
+	MaxAge   time.Duration
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// File describes one rotated, gzipped file in the manifest.
+type File struct {
+	Name          string    `json:"name"`
+	FirstRevision int64     `json:"first_revision"`
+	LastRevision  int64     `json:"last_revision"`
+	Records       int64     `json:"records"`
+	OpenedAt      time.Time `json:"opened_at"`
+	RotatedAt     time.Time `json:"rotated_at"`
+}
+
+type Manifest struct {
+	Files []File `json:"files"`
+}
+
+type checkpoint struct {
+	Revision int64 `json:"revision"`
+}
+
+// Sink is not safe for concurrent use.
+type Sink struct {
+	opts     Options
+	manifest Manifest
+	// revision is the last revision handled, exported or skipped.
+	revision int64
+
+	active   *os.File
+	size     int64
+	records  int64
+	first    int64
+	last     int64
+	openedAt time.Time
+}
+
+var marshal = protojson.MarshalOptions{UseProtoNames: true}
+
+// Open prepares dir, recovering from a crash at any point of a write or a
+// rotation.
+func Open(opts Options) (*Sink, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &Sink{opts: opts}
+	if err := readJSON(filepath.Join(opts.Dir, manifestName), &s.manifest); err != nil {
+		return nil, err
+	}
+	var cp checkpoint
+	if err := readJSON(filepath.Join(opts.Dir, checkpointName), &cp); err != nil {
+		return nil, err
+	}
+	s.revision = cp.Revision
+	if n := len(s.manifest.Files); n > 0 {
+		s.revision = max(s.revision, s.manifest.Files[n-1].LastRevision)
+	}
+	if err := s.recoverActive(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// recoverActive reopens the active file, dropping a torn last line. Its
+// records count as exported even if the checkpoint was not updated after
+// them; a file the manifest already lists was rotated just before a crash
+// and is discarded.
+func (s *Sink) recoverActive() error {
+	path := filepath.Join(s.opts.Dir, activeName)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data = data[:bytes.LastIndexByte(data, '\n')+1]
+	var first, last, records int64
+	for line := range bytes.Lines(data) {
+		var rec struct {
+			Revision int64 `json:"revision,string"`
+		}
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("cdc: corrupt record in %s: %w", path, err)
+		}
+		if first == 0 {
+			first = rec.Revision
+		}
+		last = rec.Revision
+		records++
+	}
+	if n := len(s.manifest.Files); records > 0 && n > 0 && first <= s.manifest.Files[n-1].LastRevision {
+		data, first, last, records = nil, 0, 0, 0
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(int64(len(data))); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return err
+	}
+	s.active, s.size, s.records, s.first, s.last = f, int64(len(data)), records, first, last
+	s.revision = max(s.revision, last)
+	// The real opening time is unknown, so the age limit restarts now.
+	s.openedAt = s.opts.Now()
+	return nil
+}
+
+// Checkpoint returns the last revision handled. Write skips anything up to
+// and including it.
+func (s *Sink) Checkpoint() int64 {
+	return s.revision
+}
+
+// Write appends the task changes among events, in order, and moves the
+// checkpoint past all of them. Events at or before the checkpoint and
+// events that change no task are skipped. It also rotates the active file
+// when it is due, so calling it with no events rotates an idle file.
+func (s *Sink) Write(events []*taskv1.TaskEvent) error {
+	if err := s.rotateIfDue(); err != nil {
+		return err
+	}
+	var buf []byte
+	var first, last, records int64
+	revision := s.revision
+	for _, ev := range events {
+		if ev.GetRevision() <= revision {
+			continue
+		}
+		revision = ev.GetRevision()
+		if ev.GetTask() == nil {
+			continue
+		}
+		line, err := marshal.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+		if first == 0 {
+			first = revision
+		}
+		last = revision
+		records++
+	}
+	if revision == s.revision {
+		return nil
+	}
+	if len(buf) > 0 {
+		if _, err := s.active.Write(buf); err != nil {
+			return err
+		}
+		if err := s.active.Sync(); err != nil {
+			return err
+		}
+		if s.first == 0 {
+			s.first = first
+		}
+		s.size += int64(len(buf))
+		s.last = last
+		s.records += records
+		// The records are durable and count as exported from here on.
+		s.revision = last
+	}
+	if err := writeJSON(filepath.Join(s.opts.Dir, checkpointName), checkpoint{Revision: revision}); err != nil {
+		return err
+	}
+	s.revision = revision
+	return s.rotateIfDue()
+}
+
+func (s *Sink) rotateIfDue() error {
+	if s.records == 0 {
+		return nil
+	}
+	if (s.opts.MaxBytes > 0 && s.size >= s.opts.MaxBytes) || (s.opts.MaxAge > 0 && s.opts.Now().Sub(s.openedAt) >= s.opts.MaxAge) {
+		return s.Rotate()
+	}
+	return nil
+}
+
+// Rotate gzips the active file into the next numbered file, lists it in the
+// manifest and starts a new active file. It does nothing when the active
+// file is empty.
+func (s *Sink) Rotate() error {
+	if s.records == 0 {
+		return nil
+	}
+	now := s.opts.Now()
+	file := File{
+		Name:          fmt.Sprintf("changes-%06d.jsonl.gz", len(s.manifest.Files)+1),
+		FirstRevision: s.first,
+		LastRevision:  s.last,
+		Records:       s.records,
+		OpenedAt:      s.openedAt,
+		RotatedAt:     now,
+	}
+	if err := s.compress(file.Name); err != nil {
+		return err
+	}
+	manifest := Manifest{Files: append(s.manifest.Files[:len(s.manifest.Files):len(s.manifest.Files)], file)}
+	if err := writeJSON(filepath.Join(s.opts.Dir, manifestName), manifest); err != nil {
+		return err
+	}
+	s.manifest = manifest
+	if err := s.active.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := s.active.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	s.size, s.records, s.first, s.last, s.openedAt = 0, 0, 0, 0, now
+	return nil
+}
+
+func (s *Sink) compress(name string) error {
+	if _, err := s.active.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.opts.Dir, name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	zw := gzip.NewWriter(tmp)
+	if _, err := io.Copy(zw, bufio.NewReader(s.active)); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.opts.Dir, name))
+}
+
+// Manifest returns the rotated files so far.
+func (s *Sink) Manifest() Manifest {
+	return s.manifest
+}
+
+func (s *Sink) Close() error {
+	return s.active.Close()
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSON replaces path atomically.
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,149 @@
+package cdc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+)
+
+func change(revision int64) *taskv1.TaskEvent {
+	return &taskv1.TaskEvent{
+		Revision: revision,
+		TaskId:   "t",
+		Type:     taskv1.TaskEventType_TASK_EVENT_TYPE_UPDATED,
+		Task:     &taskv1.Task{TaskId: "t", Title: "some title"},
+	}
+}
+
+// exported returns the revisions in the rotated files followed by the
+// active file.
+func exported(t *testing.T, dir string, m Manifest) []int64 {
+	t.Helper()
+	var revisions []int64
+	read := func(f *bufio.Scanner) {
+		for f.Scan() {
+			var rec struct {
+				Revision int64 `json:"revision,string"`
+			}
+			if err := json.Unmarshal(f.Bytes(), &rec); err != nil {
+				t.Fatalf("bad record %q: %v", f.Text(), err)
+			}
+			revisions = append(revisions, rec.Revision)
+		}
+	}
+	for _, file := range m.Files {
+		f, err := os.Open(filepath.Join(dir, file.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("%s is not gzipped: %v", file.Name, err)
+		}
+		read(bufio.NewScanner(zr))
+		f.Close()
+	}
+	f, err := os.Open(filepath.Join(dir, activeName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	read(bufio.NewScanner(f))
+	return revisions
+}
+
+func TestSink_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Options{Dir: dir, MaxBytes: 1})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+	comment := &taskv1.TaskEvent{Revision: 2, TaskId: "t", Type: taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED}
+	if err := s.Write([]*taskv1.TaskEvent{change(1), comment, change(3)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	m := s.Manifest()
+	if len(m.Files) != 1 || m.Files[0].FirstRevision != 1 || m.Files[0].LastRevision != 3 || m.Files[0].Records != 2 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if got := exported(t, dir, m); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("expected revisions 1 and 3, got %v", got)
+	}
+	if s.Checkpoint() != 3 {
+		t.Fatalf("expected checkpoint 3, got %d", s.Checkpoint())
+	}
+}
+
+func TestSink_RotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1000, 0)
+	s, err := Open(Options{Dir: dir, MaxAge: time.Minute, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+	if err := s.Write([]*taskv1.TaskEvent{change(1)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(s.Manifest().Files) != 0 {
+		t.Fatalf("rotated too early")
+	}
+	now = now.Add(time.Minute)
+	if err := s.Write(nil); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(s.Manifest().Files) != 1 {
+		t.Fatalf("expected an idle file to rotate once it is old enough")
+	}
+}
+
+func TestSink_ResumesWithoutDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := s.Write([]*taskv1.TaskEvent{change(1), change(2)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := s.Rotate(); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if err := s.Write([]*taskv1.TaskEvent{change(3)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	s.Close()
+	// Simulate a crash after the data was written but before the
+	// checkpoint caught up, plus a torn line.
+	if err := writeJSON(filepath.Join(dir, checkpointName), checkpoint{Revision: 2}); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.OpenFile(filepath.Join(dir, activeName), os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"revision":"4","ta`)
+	f.Close()
+
+	s, err = Open(Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer s.Close()
+	if s.Checkpoint() != 3 {
+		t.Fatalf("expected checkpoint 3 from the active file, got %d", s.Checkpoint())
+	}
+	// A restarted server re-sends everything after its own view of the
+	// checkpoint; nothing may be written twice.
+	if err := s.Write([]*taskv1.TaskEvent{change(2), change(3), change(4)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	got := exported(t, dir, s.Manifest())
+	if len(got) != 4 || got[0] != 1 || got[1] != 2 || got[2] != 3 || got[3] != 4 {
+		t.Fatalf("expected revisions 1..4 exactly once, got %v", got)
+	}
+}
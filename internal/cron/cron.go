@@ -0,0 +1,209 @@
+// Package cron parses standard five-field cron expressions and computes
+// when they next fire.
+package cron
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far Next looks for a match, so expressions that
+// can never fire, such as "0 0 30 2 *", end the search.
+const searchYears = 5
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// A "*" day field places no restriction. When both day fields are
+	// restricted a day matching either one fires, as in Vixie cron.
+	domStar, dowStar bool
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	doms    = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Both 0 and 7 are Sunday.
+	dows = bounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse accepts "minute hour day-of-month month day-of-week", where each
+// field is "*" or a comma-separated list of values, ranges "a-b" and steps
+// "*/n" or "a-b/n". Months and weekdays may be given by their English
+// three-letter names. The macros @yearly, @monthly, @weekly, @daily and
+// @hourly are accepted too.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if m, ok := macros[strings.ToLower(expr)]; ok {
+		expr = m
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.New("cron: expected 5 fields, got " + strconv.Itoa(len(fields)))
+	}
+	s := &Schedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], doms); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dows); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+	return s, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step_s, has_step := strings.Cut(part, "/")
+		step := 1
+		if has_step {
+			n, err := strconv.Atoi(step_s)
+			if err != nil || n <= 0 {
+				return 0, errors.New("cron: invalid step in " + strconv.Quote(part))
+			}
+			step = n
+		}
+		lo, hi := b.min, b.max
+		if rng != "*" {
+			lo_s, hi_s, is_range := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseValue(lo_s, b); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if is_range {
+				if hi, err = parseValue(hi_s, b); err != nil {
+					return 0, err
+				}
+			} else if has_step {
+				// "a/n" means from a to the end of the range.
+				hi = b.max
+			}
+			if lo > hi {
+				return 0, errors.New("cron: empty range " + strconv.Quote(rng))
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < b.min || v > b.max {
+		return 0, errors.New("cron: value " + strconv.Quote(s) + " out of range " + strconv.Itoa(b.min) + "-" + strconv.Itoa(b.max))
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that the schedule fires, in t's
+// location, or the zero time if it does not fire within the next few years.
+// Wall-clock times skipped by a daylight saving change do not fire.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + searchYears
+	added := false
+
+wrap:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+	for s.month&(1<<uint(t.Month())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		// Midnight may not exist on a daylight saving day.
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(-time.Duration(t.Hour()) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
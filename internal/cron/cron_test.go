@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) *Schedule {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", expr, err)
+	}
+	return s
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2025, time.March, 14, 10, 17, 42, 0, time.UTC) // a Friday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.March, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, time.March, 15, 2, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * *", time.Date(2025, time.March, 14, 13, 30, 0, 0, time.UTC)},
+		{"0 0 * * mon-wed", time.Date(2025, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan,jul *", time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matching fires.
+		{"0 0 20 * fri", time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.expr).Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestNext_NeverFires(t *testing.T) {
+	if got := mustParse(t, "0 0 30 2 *").Next(time.Now()); !got.IsZero() {
+		t.Fatalf("expected February 30th never to fire, got %v", got)
+	}
+}
+
+func TestNext_TimeZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+	s := mustParse(t, "30 2 * * *")
+	// 02:30 does not exist on the day clocks go forward.
+	got := s.Next(time.Date(2025, time.March, 29, 12, 0, 0, 0, berlin))
+	if want := time.Date(2025, time.March, 31, 2, 30, 0, 0, berlin); !got.Equal(want) {
+		t.Fatalf("Next across spring forward = %v, want %v", got, want)
+	}
+	got = mustParse(t, "0 9 * * *").Next(time.Date(2025, time.March, 30, 0, 0, 0, 0, berlin))
+	if want := time.Date(2025, time.March, 30, 9, 0, 0, 0, berlin); !got.Equal(want) {
+		t.Fatalf("Next on the spring forward day = %v, want %v", got, want)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+// Package eventbus fans task events out to in-process subscribers and keeps
+// a bounded history so subscribers can resume from a revision.
+package eventbus
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	taskv1 "grpc-lab/gen/task/v1"
+)
+
+const (
+	DefaultBuffer  = 256
+	defaultHistory = 1024
+)
+
+// ErrCompacted is returned when the requested revision is older than the
+// retained history.
+var ErrCompacted = errors.New("eventbus: revision has been compacted")
+
+// ErrFutureRevision is returned when the requested revision has not been
+// published yet, e.g. because the server restarted.
+var ErrFutureRevision = errors.New("eventbus: revision is newer than the current revision")
+
+// ErrOverflow is returned by Drain once a Disconnect subscriber fell behind.
+var ErrOverflow = errors.New("eventbus: subscriber buffer overflowed")
+
+// Policy decides what happens when a subscriber's buffer is full.
+type Policy int
+
+const (
+	// DropOldest discards the oldest queued event and tells the subscriber
+	// about the gap with a GAP event.
+	DropOldest Policy = iota
+	// Coalesce replaces a queued state change of a task with a newer state
+	// change of the same task, falling back to DropOldest otherwise.
+	Coalesce
+	// Disconnect ends the subscription with ErrOverflow.
+	Disconnect
+)
+
+func (p Policy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case Coalesce:
+		return "coalesce"
+	case Disconnect:
+		return "disconnect"
+	}
+	return "Policy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// ParsePolicy is the inverse of Policy.String.
+func ParsePolicy(s string) (Policy, error) {
+	for _, p := range []Policy{DropOldest, Coalesce, Disconnect} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, errors.New("eventbus: unknown policy " + strconv.Quote(s))
+}
+
+type Options struct {
+	// Name identifies the subscriber in Stats.
+	Name string
+	// Buffer bounds the queued events; DefaultBuffer when zero.
+	Buffer int
+	Policy Policy
+	// Filter selects events; nil accepts every event.
+	Filter func(*taskv1.TaskEvent) bool
+}
+
+type Bus struct {
+	mu       sync.Mutex
+	subs     map[*Subscription]struct{}
+	revision int64
+	history  []*taskv1.TaskEvent // ring buffer ordered by revision
+	start    int                 // index of the oldest event in history
+	dropped  int64               // by subscribers that have since closed
+}
+
+func New() *Bus {
+	return NewWithHistory(defaultHistory)
+}
+
+// NewWithHistory creates a bus that retains the last n events for replay.
+func NewWithHistory(n int) *Bus {
+	return &Bus{
+		subs:    make(map[*Subscription]struct{}),
+		history: make([]*taskv1.TaskEvent, 0, n),
+	}
+}
+
+// Subscription queues the events accepted by its filter. Ready is signalled
+// whenever Drain has something to return.
+type Subscription struct {
+	bus   *Bus
+	opts  Options
+	ready chan struct{}
+
+	// Guarded by bus.mu.
+	queue      []*taskv1.TaskEvent
+	gap        int64 // events dropped since the last Drain
+	gapRev     int64 // revision of the newest of those
+	dropped    int64
+	overflowed bool
+}
+
+// Subscribe registers a subscriber.
+func (b *Bus) Subscribe(opts Options) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscribeLocked(opts)
+}
+
+// SubscribeSince registers a subscriber and returns the retained events
+// after revision that match the filter. No event is both replayed and
+// queued, and none is missed in between.
+func (b *Bus) SubscribeSince(revision int64, opts Options) (*Subscription, []*taskv1.TaskEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if revision > b.revision {
+		return nil, nil, ErrFutureRevision
+	}
+	if revision < b.oldestLocked()-1 {
+		return nil, nil, ErrCompacted
+	}
+	var replay []*taskv1.TaskEvent
+	for i := range len(b.history) {
+		ev := b.history[(b.start+i)%cap(b.history)]
+		if ev.GetRevision() > revision && (opts.Filter == nil || opts.Filter(ev)) {
+			replay = append(replay, ev)
+		}
+	}
+	return b.subscribeLocked(opts), replay, nil
+}
+
+func (b *Bus) subscribeLocked(opts Options) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	sub := &Subscription{bus: b, opts: opts, ready: make(chan struct{}, 1)}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// oldestLocked returns the revision of the oldest retained event, or the
+// next revision when nothing is retained.
+func (b *Bus) oldestLocked() int64 {
+	if len(b.history) == 0 {
+		return b.revision + 1
+	}
+	return b.history[b.start].GetRevision()
+}
+
+// Revision returns the revision of the most recently published event.
+func (b *Bus) Revision() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.revision
+}
+
+// SetRevision makes numbering continue after revision, e.g. when events up
+// to it were published before a restart. Resuming from an older revision
+// then fails with ErrCompacted. It must be called before Publish.
+func (b *Bus) SetRevision(revision int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.revision = revision
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if _, ok := s.bus.subs[s]; ok {
+		delete(s.bus.subs, s)
+		s.bus.dropped += s.dropped
+	}
+}
+
+// Ready is signalled when events are queued or the subscription overflowed.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Drain removes and returns the queued events, preceded by a GAP event if
+// any were dropped since the last call.
+func (s *Subscription) Drain() ([]*taskv1.TaskEvent, error) {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if s.overflowed {
+		return nil, ErrOverflow
+	}
+	events := s.queue
+	s.queue = nil
+	if s.gap > 0 {
+		gap := &taskv1.TaskEvent{
+			Type:     taskv1.TaskEventType_TASK_EVENT_TYPE_GAP,
+			Revision: s.gapRev,
+			Message:  strconv.FormatInt(s.gap, 10) + " events dropped",
+		}
+		events = append([]*taskv1.TaskEvent{gap}, events...)
+		s.gap, s.gapRev = 0, 0
+	}
+	return events, nil
+}
+
+// Len reports the number of queued events.
+func (s *Subscription) Len() int {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return len(s.queue)
+}
+
+// Dropped reports how many events this subscriber has lost.
+func (s *Subscription) Dropped() int64 {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.dropped
+}
+
+func (s *Subscription) enqueueLocked(ev *taskv1.TaskEvent) {
+	if s.overflowed {
+		return
+	}
+	if len(s.queue) >= s.opts.Buffer {
+		switch s.opts.Policy {
+		case Disconnect:
+			s.overflowed = true
+			s.queue = nil
+			s.dropped++
+			s.signal()
+			return
+		case Coalesce:
+			if i := s.supersededLocked(ev); i >= 0 {
+				s.queue = append(s.queue[:i], s.queue[i+1:]...)
+				s.dropped++
+				break
+			}
+			fallthrough
+		default:
+			s.gap++
+			s.gapRev = s.queue[0].GetRevision()
+			s.queue = s.queue[1:]
+			s.dropped++
+		}
+	}
+	s.queue = append(s.queue, ev)
+	s.signal()
+}
+
+// supersededLocked finds a queued task change that ev can supersede. Only
+// an event carrying the task's state replaces another such event for the
+// same task; anything else, such as a comment, must not hide a status
+// change.
+func (s *Subscription) supersededLocked(ev *taskv1.TaskEvent) int {
+	if ev.GetTask() == nil {
+		return -1
+	}
+	for i, q := range s.queue {
+		if q.GetTaskId() == ev.GetTaskId() && q.GetTask() != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Subscription) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Publish assigns ev the next revision, records it in the history and
+// queues it for all matching subscribers. It never blocks on a subscriber.
+func (b *Bus) Publish(ev *taskv1.TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.revision++
+	ev.Revision = b.revision
+	if len(b.history) < cap(b.history) {
+		b.history = append(b.history, ev)
+	} else if cap(b.history) > 0 {
+		b.history[b.start] = ev
+		b.start = (b.start + 1) % cap(b.history)
+	}
+	for sub := range b.subs {
+		if sub.opts.Filter != nil && !sub.opts.Filter(ev) {
+			continue
+		}
+		sub.enqueueLocked(ev)
+	}
+}
+
+type SubscriberStats struct {
+	Name    string
+	Policy  Policy
+	Queued  int
+	Dropped int64
+}
+
+// Stats reports per-subscriber queue depth and drops, plus the drops of
+// subscribers that already closed.
+func (b *Bus) Stats() (subs []SubscriberStats, droppedTotal int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	droppedTotal = b.dropped
+	for sub := range b.subs {
+		droppedTotal += sub.dropped
+		subs = append(subs, SubscriberStats{
+			Name:    sub.opts.Name,
+			Policy:  sub.opts.Policy,
+			Queued:  len(sub.queue),
+			Dropped: sub.dropped,
+		})
+	}
+	return subs, droppedTotal
+}
+
+// ForTask returns a filter matching events about a single task.
+func ForTask(task_id string) func(*taskv1.TaskEvent) bool {
+	return func(ev *taskv1.TaskEvent) bool {
+		return ev.GetTaskId() == task_id
+	}
+}
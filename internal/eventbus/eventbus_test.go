@@ -0,0 +1,163 @@
+package eventbus
+
+import (
+	"testing"
+
+	taskv1 "grpc-lab/gen/task/v1"
+)
+
+func TestBus_FilterAndClose(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(Options{Filter: ForTask("a")})
+
+	bus.Publish(&taskv1.TaskEvent{TaskId: "b"})
+	bus.Publish(&taskv1.TaskEvent{TaskId: "a", Message: "hit"})
+
+	<-sub.Ready()
+	events, err := sub.Drain()
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(events) != 1 || events[0].GetMessage() != "hit" {
+		t.Fatalf("expected only task a's event, got %v", events)
+	}
+	sub.Close()
+	bus.Publish(&taskv1.TaskEvent{TaskId: "a"})
+	if sub.Len() != 0 {
+		t.Fatalf("expected closed subscription to receive nothing")
+	}
+	sub.Close()
+}
+
+func TestBus_SubscribeSince(t *testing.T) {
+	bus := NewWithHistory(3)
+	for i := 0; i < 5; i++ {
+		bus.Publish(&taskv1.TaskEvent{TaskId: "a"})
+	}
+	if bus.Revision() != 5 {
+		t.Fatalf("expected revision 5, got %d", bus.Revision())
+	}
+
+	sub, replay, err := bus.SubscribeSince(3, Options{})
+	if err != nil {
+		t.Fatalf("SubscribeSince(3) failed: %v", err)
+	}
+	defer sub.Close()
+	if len(replay) != 2 || replay[0].GetRevision() != 4 || replay[1].GetRevision() != 5 {
+		t.Fatalf("expected revisions 4 and 5, got %v", replay)
+	}
+
+	// Revision 2 is the oldest the caller may resume after: 3..5 are retained.
+	if _, _, err := bus.SubscribeSince(2, Options{}); err != nil {
+		t.Fatalf("SubscribeSince(2) failed: %v", err)
+	}
+	if _, _, err := bus.SubscribeSince(1, Options{}); err != ErrCompacted {
+		t.Fatalf("expected ErrCompacted, got %v", err)
+	}
+	if _, _, err := bus.SubscribeSince(6, Options{}); err != ErrFutureRevision {
+		t.Fatalf("expected ErrFutureRevision, got %v", err)
+	}
+}
+
+func TestBus_SetRevision(t *testing.T) {
+	bus := NewWithHistory(4)
+	bus.SetRevision(10)
+	ev := &taskv1.TaskEvent{TaskId: "a"}
+	bus.Publish(ev)
+	if ev.GetRevision() != 11 {
+		t.Fatalf("expected revision 11, got %d", ev.GetRevision())
+	}
+	if _, _, err := bus.SubscribeSince(9, Options{}); err != ErrCompacted {
+		t.Fatalf("expected ErrCompacted for a revision before the restart, got %v", err)
+	}
+	if _, replay, err := bus.SubscribeSince(10, Options{}); err != nil || len(replay) != 1 {
+		t.Fatalf("expected to replay the one event after 10, got %v, %v", replay, err)
+	}
+}
+
+func taskChange(id string, st taskv1.TaskStatus) *taskv1.TaskEvent {
+	return &taskv1.TaskEvent{TaskId: id, Status: st, Task: &taskv1.Task{TaskId: id, Status: st}}
+}
+
+func TestBus_DropOldestSignalsGap(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(Options{Name: "slow", Buffer: 2, Policy: DropOldest})
+	defer sub.Close()
+	for i := 0; i < 5; i++ {
+		bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_PENDING))
+	}
+
+	events, err := sub.Drain()
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected gap plus 2 events, got %v", events)
+	}
+	if events[0].GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_GAP || events[0].GetRevision() != 3 {
+		t.Fatalf("expected GAP up to revision 3, got %v", events[0])
+	}
+	if events[1].GetRevision() != 4 || events[2].GetRevision() != 5 {
+		t.Fatalf("expected the newest events to survive, got %v", events[1:])
+	}
+	stats, total := bus.Stats()
+	if len(stats) != 1 || stats[0].Name != "slow" || stats[0].Dropped != 3 || total != 3 {
+		t.Fatalf("unexpected stats %v total %d", stats, total)
+	}
+}
+
+func TestBus_CoalesceKeepsLatestPerTask(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(Options{Buffer: 2, Policy: Coalesce})
+	defer sub.Close()
+	bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_PENDING))
+	bus.Publish(taskChange("b", taskv1.TaskStatus_TASK_STATUS_PENDING))
+	bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_RUNNING))
+	bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_COMPLETED))
+
+	events, err := sub.Drain()
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+	if events[0].GetTaskId() != "b" || events[1].GetTaskId() != "a" || events[1].GetStatus() != taskv1.TaskStatus_TASK_STATUS_COMPLETED {
+		t.Fatalf("expected b then a's latest state, got %v", events)
+	}
+	if sub.Dropped() != 2 {
+		t.Fatalf("expected 2 coalesced events counted as dropped, got %d", sub.Dropped())
+	}
+}
+
+func TestBus_CoalesceKeepsStateOverOtherEvents(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(Options{Buffer: 1, Policy: Coalesce})
+	defer sub.Close()
+	bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_COMPLETED))
+	bus.Publish(&taskv1.TaskEvent{Type: taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED, TaskId: "a"})
+
+	events, err := sub.Drain()
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(events) != 2 || events[0].GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_GAP || events[0].GetRevision() != 1 {
+		t.Fatalf("expected a GAP for the dropped state change, got %v", events)
+	}
+	if events[1].GetType() != taskv1.TaskEventType_TASK_EVENT_TYPE_COMMENT_ADDED {
+		t.Fatalf("expected the comment after the gap, got %v", events[1])
+	}
+}
+
+func TestBus_DisconnectOnOverflow(t *testing.T) {
+	bus := New()
+	sub := bus.Subscribe(Options{Buffer: 1, Policy: Disconnect})
+	defer sub.Close()
+	bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_PENDING))
+	bus.Publish(taskChange("a", taskv1.TaskStatus_TASK_STATUS_RUNNING))
+
+	<-sub.Ready()
+	if _, err := sub.Drain(); err != ErrOverflow {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+}
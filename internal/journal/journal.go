@@ -0,0 +1,164 @@
+// Package journal is an append-only log of typed records on local disk. Each
+// Append is written and synced as a unit; a record torn by a crash is
+// discarded the next time the log is opened.
+package journal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Record is one journal entry. Kind is free for the caller to define.
+type Record struct {
+	Kind byte
+	Data []byte
+}
+
+// headerSize is the length and CRC-32 preceding each record's kind and data.
+const headerSize = 8
+
+var ErrClosed = errors.New("journal: closed")
+
+type Log struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+// Open replays every intact record in the log at path through fn, creating
+// the file if needed, and returns the log ready for appends. Anything after
+// the last intact record is truncated.
+func Open(path string, fn func(Record) error) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	good, err := replay(f, fn)
+	if err == nil {
+		err = f.Truncate(good)
+	}
+	if err == nil {
+		_, err = f.Seek(good, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Log{path: path, f: f, size: good}, nil
+}
+
+// replay returns the offset just past the last intact record.
+func replay(r io.Reader, fn func(Record) error) (int64, error) {
+	br := bufio.NewReader(r)
+	var off int64
+	var hdr [headerSize]byte
+	for {
+		if _, err := io.ReadFull(br, hdr[:]); err != nil {
+			return off, nil
+		}
+		n := binary.BigEndian.Uint32(hdr[0:4])
+		if n == 0 {
+			return off, nil
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return off, nil
+		}
+		if crc32.ChecksumIEEE(buf) != binary.BigEndian.Uint32(hdr[4:8]) {
+			return off, nil
+		}
+		if err := fn(Record{Kind: buf[0], Data: buf[1:]}); err != nil {
+			return off, err
+		}
+		off += headerSize + int64(n)
+	}
+}
+
+func encode(dst []byte, recs []Record) []byte {
+	for _, rec := range recs {
+		body := append([]byte{rec.Kind}, rec.Data...)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(body)))
+		dst = binary.BigEndian.AppendUint32(dst, crc32.ChecksumIEEE(body))
+		dst = append(dst, body...)
+	}
+	return dst
+}
+
+// Append durably writes recs. Either all of them survive a crash or, on
+// replay, the torn tail is dropped with them. It returns only once the
+// records are synced to disk.
+func (l *Log) Append(recs ...Record) error {
+	buf := encode(nil, recs)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return ErrClosed
+	}
+	n, err := l.f.Write(buf)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+// Size returns the length of the log in bytes, e.g. to decide when to
+// Rewrite it.
+func (l *Log) Size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size
+}
+
+// Rewrite atomically replaces the whole log with recs, e.g. to compact it
+// down to the current state.
+func (l *Log) Rewrite(recs []Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return ErrClosed
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	buf := encode(nil, recs)
+	_, err = tmp.Write(buf)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), l.path)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	l.f.Close()
+	l.f = tmp
+	l.size = int64(len(buf))
+	_, err = l.f.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
@@ -0,0 +1,101 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func collect(t *testing.T, path string) ([]Record, *Log) {
+	t.Helper()
+	var recs []Record
+	l, err := Open(path, func(r Record) error {
+		recs = append(recs, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return recs, l
+}
+
+func TestAppendAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	_, l := collect(t, path)
+	if err := l.Append(Record{Kind: 'a', Data: []byte("one")}, Record{Kind: 'b', Data: []byte("two")}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := l.Append(Record{Kind: 'c'}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	l.Close()
+
+	recs, l := collect(t, path)
+	defer l.Close()
+	if len(recs) != 3 || recs[0].Kind != 'a' || string(recs[0].Data) != "one" || string(recs[1].Data) != "two" || recs[2].Kind != 'c' {
+		t.Fatalf("unexpected records: %v", recs)
+	}
+}
+
+func TestTornTailIsTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	_, l := collect(t, path)
+	if err := l.Append(Record{Kind: 'a', Data: []byte("kept")}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := l.Append(Record{Kind: 'b', Data: []byte("torn")}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	l.Close()
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, l := collect(t, path)
+	if len(recs) != 1 || string(recs[0].Data) != "kept" {
+		t.Fatalf("expected only the intact record, got %v", recs)
+	}
+	if err := l.Append(Record{Kind: 'c', Data: []byte("after")}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	l.Close()
+
+	recs, l = collect(t, path)
+	defer l.Close()
+	if len(recs) != 2 || string(recs[1].Data) != "after" {
+		t.Fatalf("expected append after the truncated tail, got %v", recs)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	_, l := collect(t, path)
+	for i := 0; i < 10; i++ {
+		if err := l.Append(Record{Kind: 'a', Data: []byte("old")}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	if size := l.Size(); size != 10*(headerSize+4) {
+		t.Fatalf("expected 10 records of %d bytes, got a size of %d", headerSize+4, size)
+	}
+	if err := l.Rewrite([]Record{{Kind: 's', Data: []byte("state")}}); err != nil {
+		t.Fatalf("Rewrite failed: %v", err)
+	}
+	if err := l.Append(Record{Kind: 'a', Data: []byte("new")}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if size := l.Size(); size != 2*headerSize+6+4 {
+		t.Fatalf("expected the size of the rewritten log, got %d", size)
+	}
+	l.Close()
+
+	recs, l := collect(t, path)
+	defer l.Close()
+	if len(recs) != 2 || recs[0].Kind != 's' || string(recs[1].Data) != "new" {
+		t.Fatalf("unexpected records after rewrite: %v", recs)
+	}
+	if size := l.Size(); size != 2*headerSize+6+4 {
+		t.Fatalf("expected the size after reopening, got %d", size)
+	}
+}
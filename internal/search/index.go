@@ -0,0 +1,221 @@
+// Package search is a small in-memory inverted index with prefix matching,
+// TF-IDF style ranking and highlighted snippets.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Field is one searchable piece of a document. Matches in fields with a
+// higher Weight rank higher.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+type Hit struct {
+	ID      string
+	Score   float64
+	Snippet string
+}
+
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string][]Field
+	postings map[string]map[string]float64 // term -> doc id -> weighted term frequency
+	terms    []string                      // sorted keys of postings, nil when stale
+}
+
+func New() *Index {
+	return &Index{
+		docs:     make(map[string][]Field),
+		postings: make(map[string]map[string]float64),
+	}
+}
+
+// Put indexes a document, replacing any previous version with the same id.
+func (ix *Index) Put(id string, fields ...Field) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.removeLocked(id)
+	ix.docs[id] = fields
+	for _, f := range fields {
+		for _, term := range Tokenize(f.Text) {
+			docs := ix.postings[term]
+			if docs == nil {
+				docs = make(map[string]float64)
+				ix.postings[term] = docs
+				ix.terms = nil
+			}
+			docs[id] += f.Weight
+		}
+	}
+}
+
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.removeLocked(id)
+}
+
+func (ix *Index) removeLocked(id string) {
+	fields, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	delete(ix.docs, id)
+	for _, f := range fields {
+		for _, term := range Tokenize(f.Text) {
+			delete(ix.postings[term], id)
+			if len(ix.postings[term]) == 0 {
+				delete(ix.postings, term)
+				ix.terms = nil
+			}
+		}
+	}
+}
+
+// Len reports the number of indexed documents.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Search returns documents containing every query token, either exactly or
+// as a prefix of an indexed term, best match first.
+func (ix *Index) Search(query string) []Hit {
+	tokens := Tokenize(query)
+	if len(tokens) == 0 {
+		return nil
+	}
+	ix.mu.RLock()
+	for ix.terms == nil {
+		// Only sorting the terms needs the write lock; searches share the
+		// read lock.
+		ix.mu.RUnlock()
+		ix.mu.Lock()
+		if ix.terms == nil {
+			ix.terms = make([]string, 0, len(ix.postings))
+			for term := range ix.postings {
+				ix.terms = append(ix.terms, term)
+			}
+			sort.Strings(ix.terms)
+		}
+		ix.mu.Unlock()
+		ix.mu.RLock()
+	}
+	defer ix.mu.RUnlock()
+
+	n := float64(len(ix.docs))
+	var scores map[string]float64
+	for _, q := range tokens {
+		matched := make(map[string]float64)
+		i := sort.SearchStrings(ix.terms, q)
+		for ; i < len(ix.terms) && strings.HasPrefix(ix.terms[i], q); i++ {
+			term := ix.terms[i]
+			docs := ix.postings[term]
+			idf := math.Log(1 + n/float64(len(docs)))
+			boost := 1.0
+			if term != q {
+				// Prefix matches count, but less than the whole word.
+				boost = 0.5
+			}
+			for id, tf := range docs {
+				matched[id] += tf * idf * boost
+			}
+		}
+		if scores == nil {
+			scores = matched
+			continue
+		}
+		for id := range scores {
+			if s, ok := matched[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score, Snippet: snippet(ix.docs[id], tokens)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
+
+// Tokenize lower-cases text and splits it on anything that is not a letter
+// or digit.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+const snippetWords = 12
+
+// snippet picks the first field with a match and returns a window of words
+// around it as HTML: the text is escaped and matching words are wrapped in
+// <em></em>.
+func snippet(fields []Field, tokens []string) string {
+	for _, f := range fields {
+		words := strings.Fields(f.Text)
+		first := -1
+		marked := make([]bool, len(words))
+		for i, w := range words {
+			if wordMatches(w, tokens) {
+				marked[i] = true
+				if first < 0 {
+					first = i
+				}
+			}
+		}
+		if first < 0 {
+			continue
+		}
+		start := max(0, first-snippetWords/4)
+		end := min(len(words), start+snippetWords)
+		var b strings.Builder
+		if start > 0 {
+			b.WriteString("… ")
+		}
+		for i := start; i < end; i++ {
+			if i > start {
+				b.WriteByte(' ')
+			}
+			if marked[i] {
+				b.WriteString("<em>" + html.EscapeString(words[i]) + "</em>")
+			} else {
+				b.WriteString(html.EscapeString(words[i]))
+			}
+		}
+		if end < len(words) {
+			b.WriteString(" …")
+		}
+		return b.String()
+	}
+	return ""
+}
+
+func wordMatches(word string, tokens []string) bool {
+	for _, t := range Tokenize(word) {
+		for _, q := range tokens {
+			if strings.HasPrefix(t, q) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package search
+
+import "testing"
+
+func TestIndex_PrefixMatchAndRanking(t *testing.T) {
+	ix := New()
+	ix.Put("a", Field{Text: "Deploy database", Weight: 2}, Field{Text: "roll out the new schema", Weight: 1})
+	ix.Put("b", Field{Text: "Write docs", Weight: 2}, Field{Text: "describe how to deploy the database", Weight: 1})
+	ix.Put("c", Field{Text: "Buy milk", Weight: 2})
+
+	hits := ix.Search("data depl")
+	if len(hits) != 2 {
+		t.Fatalf("expected 2 hits, got %d: %v", len(hits), hits)
+	}
+	if hits[0].ID != "a" {
+		t.Fatalf("expected title match to rank first, got %q", hits[0].ID)
+	}
+	if hits[0].Snippet != "<em>Deploy</em> <em>database</em>" {
+		t.Fatalf("unexpected snippet %q", hits[0].Snippet)
+	}
+}
+
+func TestIndex_SnippetEscapesText(t *testing.T) {
+	ix := New()
+	ix.Put("a", Field{Text: `Fix <script>alert("x")</script> in login & signup`, Weight: 1})
+
+	hits := ix.Search("login")
+	if len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %v", hits)
+	}
+	want := "Fix &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; in <em>login</em> &amp; signup"
+	if hits[0].Snippet != want {
+		t.Fatalf("expected an escaped snippet %q, got %q", want, hits[0].Snippet)
+	}
+}
+
+func TestIndex_AllTokensRequired(t *testing.T) {
+	ix := New()
+	ix.Put("a", Field{Text: "deploy database", Weight: 1})
+	ix.Put("b", Field{Text: "deploy frontend", Weight: 1})
+
+	hits := ix.Search("deploy front")
+	if len(hits) != 1 || hits[0].ID != "b" {
+		t.Fatalf("expected only b, got %v", hits)
+	}
+}
+
+func TestIndex_PutReplacesAndRemove(t *testing.T) {
+	ix := New()
+	ix.Put("a", Field{Text: "old title", Weight: 1})
+	ix.Put("a", Field{Text: "new title", Weight: 1})
+	if hits := ix.Search("old"); len(hits) != 0 {
+		t.Fatalf("expected stale terms to be dropped, got %v", hits)
+	}
+	if hits := ix.Search("new"); len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %v", hits)
+	}
+	ix.Remove("a")
+	if hits := ix.Search("title"); len(hits) != 0 || ix.Len() != 0 {
+		t.Fatalf("expected empty index after Remove, got %v", hits)
+	}
+}
@@ -0,0 +1,83 @@
+// Package webhook signs and sends webhook payloads.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	IDHeader        = "X-Webhook-Id"
+)
+
+// Sign returns the value of SignatureHeader for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a SignatureHeader value in constant time.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(strings.TrimSpace(signature)))
+}
+
+// Request describes a single delivery attempt.
+type Request struct {
+	URL        string
+	Secret     string
+	WebhookID  string
+	DeliveryID string
+	Body       []byte
+}
+
+// Send POSTs the signed body and returns the response status code. Any
+// status outside 2xx is reported as an error along with the code.
+func Send(ctx context.Context, client *http.Client, r Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(r.Secret, r.Body))
+	req.Header.Set(IDHeader, r.WebhookID)
+	req.Header.Set(DeliveryHeader, r.DeliveryID)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &StatusError{Code: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return "webhook: receiver returned " + http.StatusText(e.Code)
+}
+
+// Backoff returns the delay before retry number attempt (1-based):
+// base doubled per attempt, capped at max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	return min(d, max)
+}
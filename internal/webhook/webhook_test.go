@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend_SignsBody(t *testing.T) {
+	var verified bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verified = Verify("s3cret", body, r.Header.Get(SignatureHeader)) && r.Header.Get(DeliveryHeader) == "d1"
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	code, err := Send(context.Background(), srv.Client(), Request{URL: srv.URL, Secret: "s3cret", WebhookID: "w1", DeliveryID: "d1", Body: []byte(`{"a":1}`)})
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("Send = %d, %v", code, err)
+	}
+	if !verified {
+		t.Fatalf("receiver could not verify the signature")
+	}
+}
+
+func TestSend_Non2xxIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	code, err := Send(context.Background(), srv.Client(), Request{URL: srv.URL, Body: []byte("{}")})
+	if code != http.StatusBadGateway || err == nil {
+		t.Fatalf("Send = %d, %v; want 502 and an error", code, err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: 5 * time.Second} {
+		if got := Backoff(attempt, time.Second, 5*time.Second); got != want {
+			t.Fatalf("Backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
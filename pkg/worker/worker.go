@@ -0,0 +1,394 @@
+// Package worker runs Go handlers for tasks of a TaskService server.
+//
+// A Worker holds a WorkerSession open, runs every task the server pushes to
+// it on its own goroutine and reports the outcome, so handlers never deal
+// with leases:
+//
+//	w := worker.New(client, worker.Options{WorkerID: "resizer-1", Concurrency: 4})
+//	w.Handle("resize", func(ctx context.Context, task *worker.Task) (*structpb.Struct, error) {
+//		task.Progress(50, "resize", "halfway")
+//		return nil, nil
+//	})
+//	err := w.Run(ctx)
+//
+// A handler's context is canceled when the task is canceled or its lease is
+// lost otherwise; its outcome is then discarded. A handler that panics fails
+// its task with error class "panic".
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+	"grpc-lab/internal/retry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	defaultConcurrency   = 1
+	defaultLeaseDuration = 30 * time.Second
+	defaultCallAttempts  = 5
+	defaultReconnect     = time.Second
+
+	// ErrorClassPanic is reported for a task whose handler panicked.
+	ErrorClassPanic = "panic"
+	// ErrorClassNoHandler is reported for a task the worker was given but
+	// has no handler for.
+	ErrorClassNoHandler = "no_handler"
+)
+
+// ErrRevoked is the cause of a handler's context when its task was taken
+// away from the worker.
+var ErrRevoked = errors.New("worker: task revoked")
+
+// Handler runs one attempt of a task. The returned result completes the
+// task; an error fails it, and the task's retry policy decides what
+// happens next.
+type Handler func(ctx context.Context, task *Task) (*structpb.Struct, error)
+
+type Options struct {
+	// WorkerID names the worker in leases; required.
+	WorkerID string
+	// Concurrency is how many tasks run at once; 1 when zero.
+	Concurrency int
+	// LeaseDuration is requested for every lease; 30s when zero. Leases are
+	// renewed three times per LeaseDuration.
+	LeaseDuration time.Duration
+	// CallAttempts bounds the tries of a call that fails with Unavailable;
+	// 5 when zero.
+	CallAttempts int
+	// ReconnectDelay is the pause before a lost session is opened again; 1s
+	// when zero.
+	ReconnectDelay time.Duration
+}
+
+type route struct {
+	queue, taskType string
+}
+
+// Worker dispatches tasks to the handlers registered with it. Handlers must
+// be registered before Run.
+type Worker struct {
+	client   taskv1.TaskServiceClient
+	opts     Options
+	handlers map[route]Handler
+}
+
+func New(client taskv1.TaskServiceClient, opts Options) *Worker {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = defaultLeaseDuration
+	}
+	if opts.CallAttempts <= 0 {
+		opts.CallAttempts = defaultCallAttempts
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = defaultReconnect
+	}
+	return &Worker{client: client, opts: opts, handlers: make(map[route]Handler)}
+}
+
+// Handle registers h for tasks labelled type=taskType in any queue. An
+// empty taskType matches tasks no other handler does.
+func (w *Worker) Handle(taskType string, h Handler) {
+	w.HandleQueue("", taskType, h)
+}
+
+// HandleQueue registers h for tasks labelled type=taskType in queue. An
+// empty queue matches every queue and an empty taskType every type; the
+// most specific registration wins.
+func (w *Worker) HandleQueue(queue, taskType string, h Handler) {
+	w.handlers[route{queue, taskType}] = h
+}
+
+func (w *Worker) handler(task *taskv1.Task) Handler {
+	queue, taskType := task.GetQueue(), task.GetLabels()["type"]
+	if queue == "" {
+		queue = "default"
+	}
+	for _, r := range []route{{queue, taskType}, {"", taskType}, {queue, ""}, {"", ""}} {
+		if h, ok := w.handlers[r]; ok {
+			return h
+		}
+	}
+	return nil
+}
+
+// hello asks only for tasks some handler accepts. The server matches queues
+// and types separately, so a task may still arrive that no handler takes.
+func (w *Worker) hello() *taskv1.WorkerHello {
+	hello := &taskv1.WorkerHello{
+		WorkerId:      w.opts.WorkerID,
+		Capacity:      int32(w.opts.Concurrency),
+		LeaseDuration: durationpb.New(w.opts.LeaseDuration),
+	}
+	queues, types := make(map[string]bool), make(map[string]bool)
+	for r := range w.handlers {
+		queues[r.queue] = true
+		types[r.taskType] = true
+	}
+	if !queues[""] {
+		for q := range queues {
+			hello.Queues = append(hello.Queues, q)
+		}
+	}
+	if !types[""] {
+		for t := range types {
+			hello.Capabilities = append(hello.Capabilities, t)
+		}
+	}
+	return hello
+}
+
+// Run processes tasks until ctx is done, opening the session again whenever
+// the server becomes unavailable. Tasks still running when the session ends
+// are abandoned to the server, which hands them out again.
+func (w *Worker) Run(ctx context.Context) error {
+	if w.opts.WorkerID == "" {
+		return errors.New("worker: WorkerID is required")
+	}
+	if len(w.handlers) == 0 {
+		return errors.New("worker: no handlers registered")
+	}
+	for {
+		err := w.runSession(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+		log.Printf("worker %s: session lost, reconnecting: %v", w.opts.WorkerID, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.opts.ReconnectDelay):
+		}
+	}
+}
+
+// session is one WorkerSession stream and the tasks running under it.
+type session struct {
+	w      *Worker
+	stream taskv1.TaskService_WorkerSessionClient
+
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc // by task_id
+}
+
+func (s *session) send(msg *taskv1.WorkerMessage) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.stream.Send(msg)
+}
+
+func (w *Worker) runSession(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var stream taskv1.TaskService_WorkerSessionClient
+	err := retry.CallWithRetry(ctx, w.opts.CallAttempts, func(ctx context.Context) error {
+		var err error
+		stream, err = w.client.WorkerSession(ctx)
+		if err != nil {
+			return err
+		}
+		err = stream.Send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Hello{Hello: w.hello()}})
+		if err == io.EOF {
+			// The stream failed; Recv returns why.
+			_, err = stream.Recv()
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s := &session{w: w, stream: stream, running: make(map[string]context.CancelCauseFunc)}
+	var wg sync.WaitGroup
+	// Handlers must not outlive the session: their leases go with it.
+	defer wg.Wait()
+	defer cancel()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.heartbeat(ctx)
+	}()
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return status.Error(codes.Unavailable, "session closed by the server")
+		}
+		if err != nil {
+			return err
+		}
+		switch m := msg.GetMsg().(type) {
+		case *taskv1.DispatchMessage_Assignment:
+			task := m.Assignment.GetTask()
+			task_ctx, task_cancel := context.WithCancelCause(ctx)
+			s.mu.Lock()
+			s.running[task.GetTaskId()] = task_cancel
+			s.mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.run(task_ctx, task)
+			}()
+		case *taskv1.DispatchMessage_Revoked:
+			s.mu.Lock()
+			if task_cancel, ok := s.running[m.Revoked.GetTaskId()]; ok {
+				task_cancel(fmt.Errorf("%w: %s", ErrRevoked, m.Revoked.GetReason()))
+				delete(s.running, m.Revoked.GetTaskId())
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// heartbeat renews the session's leases until ctx is done.
+func (s *session) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(s.w.opts.LeaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Heartbeat{Heartbeat: &taskv1.WorkerHeartbeat{}}}); err != nil {
+				// Recv sees the broken stream and ends the session.
+				return
+			}
+		}
+	}
+}
+
+// run calls the task's handler and reports its outcome, unless the task was
+// revoked meanwhile.
+func (s *session) run(ctx context.Context, pb *taskv1.Task) {
+	task := &Task{Task: pb, session: s}
+	res := &taskv1.WorkerResult{TaskId: pb.GetTaskId(), LeaseId: pb.GetLease().GetLeaseId()}
+	result, err := s.call(ctx, task)
+	if err != nil {
+		res.Outcome = &taskv1.WorkerResult_Error{Error: err.Error()}
+		res.ErrorClass = errorClass(err)
+	} else {
+		res.Outcome = &taskv1.WorkerResult_Result{Result: result}
+	}
+
+	s.mu.Lock()
+	task_cancel, held := s.running[pb.GetTaskId()]
+	delete(s.running, pb.GetTaskId())
+	s.mu.Unlock()
+	if !held {
+		return
+	}
+	defer task_cancel(nil)
+	if ctx.Err() != nil {
+		return
+	}
+	if err := s.send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Result{Result: res}}); err != nil {
+		log.Printf("worker %s: reporting task %s: %v", s.w.opts.WorkerID, pb.GetTaskId(), err)
+	}
+}
+
+func (s *session) call(ctx context.Context, task *Task) (result *structpb.Struct, err error) {
+	h := s.w.handler(task.Task)
+	if h == nil {
+		return nil, WithClass(ErrorClassNoHandler, fmt.Errorf("no handler for type %q in queue %q", task.GetLabels()["type"], task.GetQueue()))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("worker %s: task %s panicked: %v\n%s", s.w.opts.WorkerID, task.GetTaskId(), r, debug.Stack())
+			result, err = nil, WithClass(ErrorClassPanic, fmt.Errorf("panic: %v", r))
+		}
+	}()
+	return h(ctx, task)
+}
+
+// Task is the task a handler runs, with the lease the worker holds on it.
+type Task struct {
+	*taskv1.Task
+	session *session
+}
+
+// The server's limits on progress reports, checked before sending since a
+// rejected report costs the worker its lease on the task.
+const (
+	maxProgressStep    = 128
+	maxProgressMessage = 1024
+)
+
+// Progress reports how far the handler has got; it also renews the lease.
+// The server publishes at most one progress event per second for a step.
+func (t *Task) Progress(percent float64, step, message string) error {
+	switch {
+	case math.IsNaN(percent) || percent < 0 || percent > 100:
+		return fmt.Errorf("worker: percent must be between 0 and 100, got %v", percent)
+	case len(step) > maxProgressStep:
+		return fmt.Errorf("worker: step must be at most %d bytes", maxProgressStep)
+	case len(message) > maxProgressMessage:
+		return fmt.Errorf("worker: message must be at most %d bytes", maxProgressMessage)
+	}
+	return t.session.send(&taskv1.WorkerMessage{Msg: &taskv1.WorkerMessage_Progress{Progress: &taskv1.WorkerProgress{
+		TaskId:  t.GetTaskId(),
+		LeaseId: t.GetLease().GetLeaseId(),
+		Percent: percent,
+		Step:    step,
+		Message: message,
+	}}})
+}
+
+// Log appends lines to the task's output.
+func (t *Task) Log(ctx context.Context, lines ...string) error {
+	w := t.session.w
+	return retry.CallWithRetry(ctx, w.opts.CallAttempts, func(ctx context.Context) error {
+		stream, err := w.client.AppendTaskLogs(ctx)
+		if err != nil {
+			return err
+		}
+		req := &taskv1.AppendTaskLogsRequest{TaskId: t.GetTaskId(), WorkerId: w.opts.WorkerID, LeaseId: t.GetLease().GetLeaseId(), Lines: lines}
+		if err := stream.Send(req); err != nil && err != io.EOF {
+			return err
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	})
+}
+
+type classError struct {
+	class string
+	err   error
+}
+
+func (e *classError) Error() string { return e.err.Error() }
+func (e *classError) Unwrap() error { return e.err }
+
+// WithClass tags err with an error class, which is matched against the
+// retryable_errors of the task's retry policy. The server reserves "timeout"
+// and "lease_expired" and revokes a task reported with either.
+func WithClass(class string, err error) error {
+	return &classError{class: class, err: err}
+}
+
+func errorClass(err error) string {
+	var ce *classError
+	if errors.As(err, &ce) {
+		return ce.class
+	}
+	return ""
+}
@@ -0,0 +1,354 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	taskv1 "grpc-lab/gen/task/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakeServer hands every WorkerSession the worker opens to the test.
+type fakeServer struct {
+	taskv1.UnimplementedTaskServiceServer
+	// refuse is how many sessions fail with Unavailable before one is
+	// accepted.
+	refuse   atomic.Int32
+	sessions chan *fakeSession
+}
+
+type fakeSession struct {
+	stream taskv1.TaskService_WorkerSessionServer
+	hello  *taskv1.WorkerHello
+	// end finishes the session with the error sent to it.
+	end chan error
+}
+
+func (f *fakeServer) WorkerSession(stream taskv1.TaskService_WorkerSessionServer) error {
+	if f.refuse.Add(-1) >= 0 {
+		return status.Error(codes.Unavailable, "not ready")
+	}
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	s := &fakeSession{stream: stream, hello: msg.GetHello(), end: make(chan error, 1)}
+	f.sessions <- s
+	select {
+	case err := <-s.end:
+		return err
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	}
+}
+
+// newFakeServer serves a fakeServer over bufconn until the test ends.
+func newFakeServer(t *testing.T) (*fakeServer, taskv1.TaskServiceClient) {
+	t.Helper()
+	srv := &fakeServer{sessions: make(chan *fakeSession, 4)}
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	taskv1.RegisterTaskServiceServer(gs, srv)
+	go func() {
+		_ = gs.Serve(lis)
+	}()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		gs.Stop()
+	})
+	return srv, taskv1.NewTaskServiceClient(conn)
+}
+
+// nextSession waits for the worker to open a session.
+func (f *fakeServer) nextSession(t *testing.T) *fakeSession {
+	t.Helper()
+	select {
+	case s := <-f.sessions:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatalf("worker never opened a session")
+		return nil
+	}
+}
+
+func (s *fakeSession) assign(t *testing.T, task_id, taskType string) {
+	t.Helper()
+	task := &taskv1.Task{
+		TaskId: task_id,
+		Status: taskv1.TaskStatus_TASK_STATUS_RUNNING,
+		Labels: map[string]string{"type": taskType},
+		Lease:  &taskv1.Lease{LeaseId: "lease-" + task_id, WorkerId: s.hello.GetWorkerId()},
+	}
+	if err := s.stream.Send(&taskv1.DispatchMessage{Msg: &taskv1.DispatchMessage_Assignment{Assignment: &taskv1.TaskAssignment{Task: task}}}); err != nil {
+		t.Fatalf("Send assignment failed: %v", err)
+	}
+}
+
+// nextResult returns the next result the worker reports, skipping
+// heartbeats and progress.
+func (s *fakeSession) nextResult(t *testing.T) *taskv1.WorkerResult {
+	t.Helper()
+	results := make(chan *taskv1.WorkerResult, 1)
+	go func() {
+		for {
+			msg, err := s.stream.Recv()
+			if err != nil {
+				close(results)
+				return
+			}
+			if res := msg.GetResult(); res != nil {
+				results <- res
+				return
+			}
+		}
+	}()
+	select {
+	case res, ok := <-results:
+		if !ok {
+			t.Fatalf("session ended without a result")
+		}
+		return res
+	case <-time.After(5 * time.Second):
+		t.Fatalf("worker never reported a result")
+		return nil
+	}
+}
+
+// runWorker runs w until the test ends. Run's error is sent on the returned
+// channel.
+func runWorker(t *testing.T, w *Worker) <-chan error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(cancel)
+	return done
+}
+
+func echo(ctx context.Context, task *Task) (*structpb.Struct, error) {
+	return structpb.NewStruct(map[string]any{"task_id": task.GetTaskId()})
+}
+
+func TestWorker_ReconnectsWhileUnavailable(t *testing.T) {
+	srv, client := newFakeServer(t)
+	srv.refuse.Store(2)
+	w := New(client, Options{WorkerID: "w1", ReconnectDelay: 10 * time.Millisecond})
+	w.Handle("", echo)
+	done := runWorker(t, w)
+
+	// Two refused sessions, then one the server drops.
+	srv.nextSession(t).end <- status.Error(codes.Unavailable, "restarting")
+	s := srv.nextSession(t)
+	if s.hello.GetWorkerId() != "w1" {
+		t.Fatalf("expected the worker to introduce itself again, got %v", s.hello)
+	}
+	s.assign(t, "t1", "echo")
+	if res := s.nextResult(t); res.GetTaskId() != "t1" || res.GetLeaseId() != "lease-t1" {
+		t.Fatalf("expected the task to run on the new session, got %v", res)
+	}
+
+	// Any other error ends Run.
+	s.end <- status.Error(codes.PermissionDenied, "go away")
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected Run to fail with PermissionDenied, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return")
+	}
+}
+
+func TestWorker_RevokedTaskIsNotReported(t *testing.T) {
+	srv, client := newFakeServer(t)
+	started := make(chan struct{})
+	cause := make(chan error, 1)
+	w := New(client, Options{WorkerID: "w1"})
+	w.Handle("slow", func(ctx context.Context, task *Task) (*structpb.Struct, error) {
+		close(started)
+		<-ctx.Done()
+		cause <- context.Cause(ctx)
+		return nil, errors.New("interrupted")
+	})
+	w.Handle("quick", echo)
+	runWorker(t, w)
+
+	s := srv.nextSession(t)
+	s.assign(t, "t1", "slow")
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handler never started")
+	}
+	if err := s.stream.Send(&taskv1.DispatchMessage{Msg: &taskv1.DispatchMessage_Revoked{Revoked: &taskv1.TaskRevoked{TaskId: "t1", Reason: "canceled"}}}); err != nil {
+		t.Fatalf("Send revoked failed: %v", err)
+	}
+	select {
+	case err := <-cause:
+		if !errors.Is(err, ErrRevoked) {
+			t.Fatalf("expected ErrRevoked as the cause, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handler context was not canceled")
+	}
+
+	// The revoked task's outcome is dropped, so the next result is t2's.
+	s.assign(t, "t2", "quick")
+	if res := s.nextResult(t); res.GetTaskId() != "t2" {
+		t.Fatalf("expected no result for the revoked task, got %v", res)
+	}
+}
+
+func TestWorker_RunsUpToConcurrency(t *testing.T) {
+	srv, client := newFakeServer(t)
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	w := New(client, Options{WorkerID: "w1", Concurrency: 2})
+	w.Handle("", func(ctx context.Context, task *Task) (*structpb.Struct, error) {
+		started <- task.GetTaskId()
+		<-release
+		return nil, nil
+	})
+	runWorker(t, w)
+
+	s := srv.nextSession(t)
+	if s.hello.GetCapacity() != 2 {
+		t.Fatalf("expected capacity 2 in the hello, got %v", s.hello)
+	}
+	s.assign(t, "t1", "a")
+	s.assign(t, "t2", "b")
+	for range 2 {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected both tasks to run at once")
+		}
+	}
+	close(release)
+	got := []string{s.nextResult(t).GetTaskId(), s.nextResult(t).GetTaskId()}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"t1", "t2"}) {
+		t.Fatalf("expected results for both tasks, got %v", got)
+	}
+}
+
+func TestWorker_ErrorClasses(t *testing.T) {
+	srv, client := newFakeServer(t)
+	w := New(client, Options{WorkerID: "w1"})
+	w.Handle("flaky", func(ctx context.Context, task *Task) (*structpb.Struct, error) {
+		return nil, WithClass("transient", errors.New("try again"))
+	})
+	w.Handle("broken", func(ctx context.Context, task *Task) (*structpb.Struct, error) {
+		panic("boom")
+	})
+	runWorker(t, w)
+
+	s := srv.nextSession(t)
+	for _, tc := range []struct {
+		taskType, class string
+	}{
+		{"flaky", "transient"},
+		{"broken", ErrorClassPanic},
+		{"other", ErrorClassNoHandler},
+	} {
+		s.assign(t, tc.taskType, tc.taskType)
+		res := s.nextResult(t)
+		if res.GetError() == "" || res.GetErrorClass() != tc.class {
+			t.Fatalf("%s: expected an error of class %q, got %v", tc.taskType, tc.class, res)
+		}
+	}
+}
+
+func TestWorker_ProgressIsValidated(t *testing.T) {
+	srv, client := newFakeServer(t)
+	reported := make(chan error, 1)
+	w := New(client, Options{WorkerID: "w1"})
+	w.Handle("", func(ctx context.Context, task *Task) (*structpb.Struct, error) {
+		reported <- task.Progress(150, "", "")
+		return nil, nil
+	})
+	runWorker(t, w)
+
+	s := srv.nextSession(t)
+	s.assign(t, "t1", "echo")
+	if err := <-reported; err == nil {
+		t.Fatalf("expected a percent over 100 to be rejected")
+	}
+	// The report never reaches the server.
+	for {
+		msg, err := s.stream.Recv()
+		if err != nil || msg.GetProgress() != nil {
+			t.Fatalf("expected no progress before the result, got %v, %v", msg, err)
+		}
+		if msg.GetResult().GetTaskId() == "t1" {
+			return
+		}
+	}
+}
+
+func TestWorker_HandlerRouting(t *testing.T) {
+	w := New(nil, Options{WorkerID: "w1"})
+	handledBy := func(name string) Handler {
+		return func(ctx context.Context, task *Task) (*structpb.Struct, error) {
+			return structpb.NewStruct(map[string]any{"by": name})
+		}
+	}
+	w.HandleQueue("images", "resize", handledBy("images/resize"))
+	w.Handle("resize", handledBy("resize"))
+	w.HandleQueue("images", "", handledBy("images/*"))
+
+	for _, tc := range []struct {
+		queue, taskType, want string
+	}{
+		{"images", "resize", "images/resize"},
+		{"", "resize", "resize"},
+		{"images", "crop", "images/*"},
+		{"", "crop", ""},
+	} {
+		h := w.handler(&taskv1.Task{Queue: tc.queue, Labels: map[string]string{"type": tc.taskType}})
+		by := ""
+		if h != nil {
+			res, _ := h(context.Background(), nil)
+			by = res.GetFields()["by"].GetStringValue()
+		}
+		if by != tc.want {
+			t.Fatalf("task in %q of type %q: expected handler %q, got %q", tc.queue, tc.taskType, tc.want, by)
+		}
+	}
+}
+
+func TestWorker_HelloAsksForHandledTasks(t *testing.T) {
+	w := New(nil, Options{WorkerID: "w1", Concurrency: 3})
+	w.HandleQueue("images", "resize", echo)
+	w.HandleQueue("video", "crop", echo)
+	hello := w.hello()
+	slices.Sort(hello.Queues)
+	slices.Sort(hello.Capabilities)
+	if hello.GetCapacity() != 3 || !slices.Equal(hello.GetQueues(), []string{"images", "video"}) || !slices.Equal(hello.GetCapabilities(), []string{"crop", "resize"}) {
+		t.Fatalf("expected the handled queues and types, got %v", hello)
+	}
+
+	// A handler for any queue or type widens the hello to match.
+	w.Handle("", echo)
+	if hello := w.hello(); len(hello.GetQueues()) != 0 || len(hello.GetCapabilities()) != 0 {
+		t.Fatalf("expected no restriction, got %v", hello)
+	}
+}